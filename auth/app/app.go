@@ -2,79 +2,310 @@ package app
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"os/signal"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/controllers"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/services"
 	authrepo "github.com/khuchuz/go-clean-architecture-sql/auth/services/repository"
 	authusecase "github.com/khuchuz/go-clean-architecture-sql/auth/services/usecase"
+	bookmarkcontrollers "github.com/khuchuz/go-clean-architecture-sql/bookmark/controllers"
+	bookmarkservices "github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/exporter"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/fetcher"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/importer"
+	bookmarkrepo "github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	bookmarkusecase "github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase"
+	"github.com/khuchuz/go-clean-architecture-sql/health"
+	"github.com/khuchuz/go-clean-architecture-sql/lifecycle"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/khuchuz/go-clean-architecture-sql/metrics"
+	"github.com/khuchuz/go-clean-architecture-sql/tracing"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 )
 
 type App struct {
-	httpServer *http.Server
-	authUC     services.UseCase
+	config       *config.Config
+	log          *slog.Logger
+	lifecycle    *lifecycle.Manager
+	metrics      *metrics.Metrics
+	tracer       trace.TracerProvider
+	health       *health.Checker
+	httpServer   *http.Server
+	authUC       services.UseCase
+	bookmarkUC   bookmarkservices.UseCase
+	tagUC        bookmarkservices.TagUseCase
+	collectionUC bookmarkservices.CollectionUseCase
+	shareUC      bookmarkservices.ShareUseCase
+	highlightUC  bookmarkservices.HighlightUseCase
+	feedUC       bookmarkservices.FeedUseCase
+	searchUC     bookmarkservices.SearchUseCase
+	metadataUC   *bookmarkusecase.MetadataUseCase
+	importUC     *bookmarkusecase.ImportUseCase
+	linkCheckUC  *bookmarkusecase.LinkCheckUseCase
+	exportUC     bookmarkservices.ExportUseCase
 }
 
-func NewApp() *App {
-	db := database.SetupDatabase()
+// NewApp wires the server together, waiting for the database while ctx
+// allows. Everything logs to log, the queries of the database included.
+// What it starts is stopped by Run, or by NewApp itself when it fails.
+func NewApp(ctx context.Context, cfg *config.Config, log *slog.Logger) (*App, error) {
+	a := &App{
+		config:    cfg,
+		log:       log,
+		lifecycle: lifecycle.New(log),
+	}
+	if err := a.setup(ctx); err != nil {
+		a.stop()
+		return nil, err
+	}
+	return a, nil
+}
+
+// setup starts everything in the order it depends on each other, so that
+// the lifecycle stops it the other way around.
+func (a *App) setup(ctx context.Context) error {
+	cfg, log := a.config, a.log
+
+	db, err := database.SetupDatabase(ctx, cfg.Database, QueryLogger(cfg.Log, log), log)
+	if err != nil {
+		return err
+	}
+	a.lifecycle.OnStop("database", func(context.Context) error {
+		return database.Close(db)
+	})
+
+	bookmarkRepo := bookmarkrepo.InitBookmarkRepositorySQL(db)
+	tagRepo := bookmarkrepo.InitTagRepositorySQL(db)
+	collectionRepo := bookmarkrepo.InitCollectionRepositorySQL(db)
+	shareRepo := bookmarkrepo.InitShareRepositorySQL(db)
+	importRepo := bookmarkrepo.InitImportRepositorySQL(db)
+	highlightRepo := bookmarkrepo.InitHighlightRepositorySQL(db)
+	feedRepo := bookmarkrepo.InitFeedRepositorySQL(db)
+
+	if a.metrics, err = metrics.New(db); err != nil {
+		return err
+	}
+
+	tracer, stopTracing, err := tracing.Setup(ctx, cfg.Tracing, os.Stdout)
+	if err != nil {
+		return err
+	}
+	a.tracer = tracer
+	a.lifecycle.OnStop("tracing", stopTracing)
+	if err := tracing.RegisterGorm(db, tracer); err != nil {
+		return err
+	}
+
+	searchIndex, err := search.NewIndex(db)
+	if err != nil {
+		return err
+	}
+
+	metadataUC := bookmarkusecase.NewMetadataUseCase(bookmarkRepo, highlightRepo, fetcher.NewFetcher(fetcher.DefaultConfig()), searchIndex, log)
+	metadataUC.Start(4)
+	a.lifecycle.OnStop("metadata workers", func(context.Context) error {
+		metadataUC.Stop()
+		return nil
+	})
+
+	searchUC := bookmarkusecase.NewSearchUseCase(searchIndex, bookmarkRepo, log)
+	a.lifecycle.Go("search index rebuild", searchUC.RebuildIndex)
+
+	bookmarkUC := bookmarkusecase.NewBookmarkUseCase(bookmarkRepo, searchIndex, metadataUC, log)
+	a.lifecycle.Go("bookmark url normalization", bookmarkUC.NormalizeURLs)
+
+	linkCheckUC := bookmarkusecase.NewLinkCheckUseCase(bookmarkRepo, fetcher.NewLinkChecker(fetcher.DefaultConfig()), LinkCheckConfig(cfg.LinkCheck), log)
+	linkCheckUC.Start()
+	a.lifecycle.OnStop("link checker", func(context.Context) error {
+		linkCheckUC.Stop()
+		return nil
+	})
+
+	importUC := bookmarkusecase.NewImportUseCase(importRepo, bookmarkRepo, tagRepo, importer.NewParser(), searchIndex, metadataUC, log)
+	a.lifecycle.OnStop("import workers", func(context.Context) error {
+		importUC.Stop()
+		return nil
+	})
+	if err := importUC.RecoverInterrupted(ctx); err != nil {
+		log.Error("import: failed to recover interrupted jobs", "err", err)
+	}
+
+	a.health = health.NewChecker(health.Database(db), health.Migrations(db))
+	a.authUC = metrics.InstrumentAuth(tracing.InstrumentAuth(NewAuthUseCase(db, cfg.Auth, log), tracer), a.metrics)
+	a.bookmarkUC = bookmarkUC
+	a.tagUC = bookmarkusecase.NewTagUseCase(tagRepo, bookmarkRepo, searchIndex, log)
+	a.collectionUC = bookmarkusecase.NewCollectionUseCase(collectionRepo, bookmarkRepo, searchIndex, log)
+	a.shareUC = bookmarkusecase.NewShareUseCase(shareRepo, bookmarkRepo, collectionRepo, log)
+	a.highlightUC = bookmarkusecase.NewHighlightUseCase(highlightRepo, bookmarkRepo, exporter.NewExporter())
+	a.feedUC = bookmarkusecase.NewFeedUseCase(feedRepo, bookmarkRepo, tagRepo, collectionRepo, exporter.NewExporter(), log)
+	a.searchUC = searchUC
+	a.metadataUC = metadataUC
+	a.importUC = importUC
+	a.linkCheckUC = linkCheckUC
+	a.exportUC = bookmarkusecase.NewExportUseCase(bookmarkRepo, exporter.NewExporter())
+	return nil
+}
 
-	userRepo := authrepo.InitUserRepositorySQL(db)
+// NewAuthUseCase builds the usecase that manages users and their tokens, for
+// the server and for the command line.
+func NewAuthUseCase(db *gorm.DB, cfg config.AuthConfig, log *slog.Logger) *authusecase.AuthUseCase {
+	return authusecase.NewAuthUseCase(
+		authrepo.InitUserRepositorySQL(db),
+		string(cfg.HashSalt),
+		[]byte(cfg.SigningKey),
+		// NewAuthUseCase counts the TTL in seconds.
+		time.Duration(cfg.TokenTTL)/time.Second,
+		log,
+	)
+}
 
-	return &App{
-		authUC: authusecase.NewAuthUseCase(
-			userRepo,
-			"hash_salt",
-			[]byte("signing_key"),
-			86400,
-		),
+// LinkCheckConfig paces the link checker as cfg says.
+func LinkCheckConfig(cfg config.LinkCheckConfig) bookmarkusecase.LinkCheckConfig {
+	return bookmarkusecase.LinkCheckConfig{
+		Interval:         time.Duration(cfg.Interval),
+		MaxAge:           time.Duration(cfg.MaxAge),
+		RetryAfter:       time.Duration(cfg.RetryAfter),
+		FailureThreshold: cfg.FailureThreshold,
+		BatchSize:        cfg.BatchSize,
+		Workers:          cfg.Workers,
+		HostDelay:        time.Duration(cfg.HostDelay),
+		Timeout:          time.Duration(cfg.Timeout),
 	}
 }
 
-func (a *App) Run(port string) error {
-	// To Disable debug
-	//gin.SetMode(gin.ReleaseMode)
+// QueryLogger logs the queries of the database to log as cfg says.
+func QueryLogger(cfg config.LogConfig, log *slog.Logger) *logging.GormLogger {
+	return logging.NewGormLogger(log, cfg.QueryLevel, time.Duration(cfg.SlowQuery))
+}
+
+// Run serves until ctx is done or the server fails, then stops everything
+// NewApp started: the HTTP server first, then the workers, then the
+// database. It returns what went wrong along the way.
+func (a *App) Run(ctx context.Context) error {
+	// The route listing of debug mode is not structured; production goes
+	// without it.
+	if a.config.Env == config.EnvProduction {
+		gin.SetMode(gin.ReleaseMode)
+	}
 	// Init gin handler
-	router := gin.Default()
+	router := gin.New()
 	router.Use(
-		gin.Recovery(),
-		gin.Logger(),
+		logging.RequestIDMiddleware(),
+		tracing.Middleware(a.tracer),
+		a.metrics.Middleware(),
+		// Share and feed links hold their tokens in the path.
+		logging.AccessLogMiddleware(a.log, "/s/", "/feeds/"),
+		logging.RecoveryMiddleware(a.log),
 	)
 
+	health.RegisterHTTPEndpoints(router, a.health)
+
 	// Set up http handlers
-	controllers.RegisterHTTPEndpoints(router, a.authUC)
+	controllers.RegisterHTTPEndpoints(router, a.authUC, a.log)
+	controllers.RegisterIntrospectionEndpoint(router, a.authUC, a.config.Auth.IntrospectSecrets(), a.log)
+	bookmarkcontrollers.RegisterPublicShareEndpoints(router, a.shareUC)
+	bookmarkcontrollers.RegisterPublicFeedEndpoints(router, a.feedUC)
 
 	// API endpoints
 	authMiddleware := controllers.NewAuthMiddleware(a.authUC)
-	_ = router.Group("/api", authMiddleware)
+	api := router.Group("/api", authMiddleware)
+	bookmarkcontrollers.RegisterHTTPEndpoints(api, a.bookmarkUC, a.exportUC, a.log)
+	bookmarkcontrollers.RegisterTagEndpoints(api, a.tagUC)
+	bookmarkcontrollers.RegisterCollectionEndpoints(api, a.collectionUC)
+	bookmarkcontrollers.RegisterShareEndpoints(api, a.shareUC)
+	bookmarkcontrollers.RegisterHighlightEndpoints(api, a.highlightUC, a.log)
+	bookmarkcontrollers.RegisterFeedEndpoints(api, a.feedUC)
+	bookmarkcontrollers.RegisterSearchEndpoints(api, a.searchUC)
+	bookmarkcontrollers.RegisterMetadataEndpoints(api, a.metadataUC)
+	bookmarkcontrollers.RegisterImportEndpoints(api, a.importUC)
+
+	if err := a.serveMetrics(); err != nil {
+		return errors.Join(err, a.stop())
+	}
 
 	// HTTP Server
 	a.httpServer = &http.Server{
-		Addr:           ":" + port,
+		Addr:           ":" + strconv.Itoa(a.config.Server.Port),
 		Handler:        router,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
+		ReadTimeout:    time.Duration(a.config.Server.ReadTimeout),
+		WriteTimeout:   time.Duration(a.config.Server.WriteTimeout),
 		MaxHeaderBytes: 1 << 20,
 	}
 
+	// Listening first makes a port in use an error of Run.
+	listener, err := net.Listen("tcp", a.httpServer.Addr)
+	if err != nil {
+		return errors.Join(err, a.stop())
+	}
+	served := make(chan error, 1)
 	go func() {
-		if err := a.httpServer.ListenAndServe(); err != nil {
-			log.Fatalf("Failed to listen and serve: %+v", err)
-		}
+		served <- a.httpServer.Serve(listener)
 	}()
+	a.lifecycle.OnStop("http server", a.httpServer.Shutdown)
+	a.log.Info("listening", "addr", listener.Addr().String())
+
+	select {
+	case <-ctx.Done():
+		// Load balancers see /readyz fail and stop sending requests before
+		// the server stops taking them.
+		a.health.ShuttingDown()
+		if delay := time.Duration(a.config.Server.DrainDelay); delay > 0 {
+			a.log.Info("draining before shutdown", "delay", delay)
+			time.Sleep(delay)
+		}
+	case err = <-served:
+		a.health.ShuttingDown()
+		a.log.Error("failed to serve", "err", err)
+	}
+
+	a.log.Info("shutting down")
+	return errors.Join(err, a.stop())
+}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, os.Interrupt)
+// serveMetrics serves /metrics on an address of its own, away from the API
+// and its users. The API stops first, so its last requests are counted.
+func (a *App) serveMetrics() error {
+	if a.config.Metrics.Addr == "" {
+		return nil
+	}
 
-	<-quit
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", a.metrics.Handler())
+	server := &http.Server{
+		Addr:         a.config.Metrics.Addr,
+		Handler:      mux,
+		ReadTimeout:  time.Duration(a.config.Server.ReadTimeout),
+		WriteTimeout: time.Duration(a.config.Server.WriteTimeout),
+	}
 
-	ctx, shutdown := context.WithTimeout(context.Background(), 5*time.Second)
-	defer shutdown()
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			a.log.Error("failed to serve metrics", "err", err)
+		}
+	}()
+	a.lifecycle.OnStop("metrics server", server.Shutdown)
+	a.log.Info("serving metrics", "addr", listener.Addr().String())
+	return nil
+}
 
-	return a.httpServer.Shutdown(ctx)
+// stop stops everything started so far, giving it the shutdown timeout.
+func (a *App) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(a.config.Server.ShutdownTimeout))
+	defer cancel()
+	return a.lifecycle.Stop(ctx)
 }
@@ -1,7 +1,380 @@
 package config
 
-var DBHost string = "127.0.0.1"
-var DBPort string = "3306"
-var DBUser string = "root"
-var DBPass string = ""
-var DBName string = "go_clean_architecture"
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+
+	// DefaultSigningKey and DefaultHashSalt only do for development.
+	// Validate refuses to run in production with the default signing key.
+	DefaultSigningKey = "signing_key"
+	DefaultHashSalt   = "hash_salt"
+
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+
+	LogFormatJSON   = "json"
+	LogFormatLogfmt = "logfmt"
+
+	TracingNone   = "none"
+	TracingOTLP   = "otlp"
+	TracingStdout = "stdout"
+)
+
+// LogLevels are the levels of log.level, and of log.query_level along with
+// "silent".
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Config is everything the server is started with. Load fills it in from
+// defaults, a file, the environment and flags, in that order.
+type Config struct {
+	Env       string          `yaml:"env" toml:"env"`
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	Auth      AuthConfig      `yaml:"auth" toml:"auth"`
+	Log       LogConfig       `yaml:"log" toml:"log"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing"`
+	Metrics   MetricsConfig   `yaml:"metrics" toml:"metrics"`
+	LinkCheck LinkCheckConfig `yaml:"link_check" toml:"link_check"`
+}
+
+// ServerConfig says where the server listens and how long a request may
+// take to be read and answered. On shutdown it reports not ready for
+// DrainDelay before it stops taking requests, so that load balancers
+// probing /readyz stop sending them first, and then gives what is running
+// ShutdownTimeout to finish.
+type ServerConfig struct {
+	Port            int      `yaml:"port" toml:"port"`
+	ReadTimeout     Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout    Duration `yaml:"write_timeout" toml:"write_timeout"`
+	DrainDelay      Duration `yaml:"drain_delay" toml:"drain_delay"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+// DatabaseConfig says which database to use. For SQLite, Name is the path
+// of the database file, or ":memory:", and the rest is not used. A Port of
+// 0 is the default port of the driver.
+//
+// On startup the server waits up to ConnectTimeout for the database to
+// come up; 0 tries once. The pool settings left at 0 keep the defaults of
+// database/sql, and SQLite always uses a single connection.
+type DatabaseConfig struct {
+	Driver   string `yaml:"driver" toml:"driver"`
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	User     string `yaml:"user" toml:"user"`
+	Password Secret `yaml:"password" toml:"password"`
+	Name     string `yaml:"name" toml:"name"`
+
+	ConnectTimeout  Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+	MaxOpenConns    int      `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+}
+
+type AuthConfig struct {
+	HashSalt   Secret   `yaml:"hash_salt" toml:"hash_salt"`
+	SigningKey Secret   `yaml:"signing_key" toml:"signing_key"`
+	TokenTTL   Duration `yaml:"token_ttl" toml:"token_ttl"`
+
+	// IntrospectClients maps OAuth client IDs to the secrets allowed to call
+	// /auth/introspect.
+	IntrospectClients map[string]Secret `yaml:"introspect_clients" toml:"introspect_clients"`
+}
+
+// LogConfig says how the server logs. QueryLevel is the most GORM logs:
+// "error" for failed queries, "warn" for slow ones as well, "info" for every
+// query, or "silent". Queries slower than SlowQuery are slow; 0 turns that
+// off.
+type LogConfig struct {
+	Level      string   `yaml:"level" toml:"level"`
+	Format     string   `yaml:"format" toml:"format"`
+	QueryLevel string   `yaml:"query_level" toml:"query_level"`
+	SlowQuery  Duration `yaml:"slow_query" toml:"slow_query"`
+}
+
+// TracingConfig says where the spans of requests go: "none", "otlp" to an
+// OpenTelemetry collector over HTTP, or "stdout". An empty Endpoint leaves
+// it to the OTEL_EXPORTER_OTLP_* variables, or http://localhost:4318.
+// SampleRatio is the share of the traces started here that are kept;
+// those started by a caller keep its decision.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter"`
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"`
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// MetricsConfig says where /metrics is served. It has a listener of its own,
+// apart from the API, since the metrics are not for its users; an empty
+// Addr serves no metrics.
+type MetricsConfig struct {
+	Addr string `yaml:"addr" toml:"addr"`
+}
+
+// LinkCheckConfig paces the worker that checks saved links. A round of at
+// most BatchSize links starts every Interval, and a link is due again
+// MaxAge after its last check, or RetryAfter after a check that got no
+// answer or a server error. It only counts as broken after
+// FailureThreshold such checks in a row. Workers links are checked at once,
+// requests to one host HostDelay apart, each given Timeout.
+type LinkCheckConfig struct {
+	Interval         Duration `yaml:"interval" toml:"interval"`
+	MaxAge           Duration `yaml:"max_age" toml:"max_age"`
+	RetryAfter       Duration `yaml:"retry_after" toml:"retry_after"`
+	FailureThreshold int      `yaml:"failure_threshold" toml:"failure_threshold"`
+	BatchSize        int      `yaml:"batch_size" toml:"batch_size"`
+	Workers          int      `yaml:"workers" toml:"workers"`
+	HostDelay        Duration `yaml:"host_delay" toml:"host_delay"`
+	Timeout          Duration `yaml:"timeout" toml:"timeout"`
+}
+
+// Default is the configuration of a development setup with a local MySQL.
+func Default() *Config {
+	return &Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:            8000,
+			ReadTimeout:     Duration(10 * time.Second),
+			WriteTimeout:    Duration(10 * time.Second),
+			ShutdownTimeout: Duration(10 * time.Second),
+		},
+		Database: DatabaseConfig{
+			Driver:          DriverMySQL,
+			Host:            "127.0.0.1",
+			User:            "root",
+			Name:            "go_clean_architecture",
+			ConnectTimeout:  Duration(30 * time.Second),
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: Duration(30 * time.Minute),
+			ConnMaxIdleTime: Duration(5 * time.Minute),
+		},
+		Auth: AuthConfig{
+			HashSalt:          DefaultHashSalt,
+			SigningKey:        DefaultSigningKey,
+			TokenTTL:          Duration(24 * time.Hour),
+			IntrospectClients: map[string]Secret{},
+		},
+		Log: LogConfig{
+			Level:      "info",
+			Format:     LogFormatJSON,
+			QueryLevel: "warn",
+			SlowQuery:  Duration(200 * time.Millisecond),
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			ServiceName: "go-clean-architecture-sql",
+			SampleRatio: 1,
+		},
+		Metrics: MetricsConfig{
+			Addr: "127.0.0.1:9464",
+		},
+		LinkCheck: LinkCheckConfig{
+			Interval:         Duration(time.Hour),
+			MaxAge:           Duration(7 * 24 * time.Hour),
+			RetryAfter:       Duration(6 * time.Hour),
+			FailureThreshold: 3,
+			BatchSize:        500,
+			Workers:          8,
+			HostDelay:        Duration(2 * time.Second),
+			Timeout:          Duration(15 * time.Second),
+		},
+	}
+}
+
+// Validate reports every problem with c at once.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		add("env must be %q or %q, not %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("server.port %d is out of range", c.Server.Port)
+	}
+	if c.Server.ReadTimeout < Duration(time.Second) {
+		add("server.read_timeout must be at least 1s")
+	}
+	if c.Server.WriteTimeout < Duration(time.Second) {
+		add("server.write_timeout must be at least 1s")
+	}
+	if c.Server.DrainDelay < 0 {
+		add("server.drain_delay may not be negative")
+	}
+	if c.Server.ShutdownTimeout < Duration(time.Second) {
+		add("server.shutdown_timeout must be at least 1s")
+	}
+
+	switch c.Database.Driver {
+	case DriverMySQL, DriverPostgres:
+		if c.Database.Host == "" {
+			add("database.host is empty")
+		}
+		if c.Database.Port < 0 || c.Database.Port > 65535 {
+			add("database.port %d is out of range", c.Database.Port)
+		}
+		if c.Database.User == "" {
+			add("database.user is empty")
+		}
+	case DriverSQLite:
+	default:
+		add("database.driver must be %q, %q or %q, not %q", DriverMySQL, DriverPostgres, DriverSQLite, c.Database.Driver)
+	}
+	if c.Database.Name == "" {
+		add("database.name is empty")
+	}
+	if c.Database.ConnectTimeout < 0 || c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		add("database.connect_timeout, conn_max_lifetime and conn_max_idle_time may not be negative")
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		add("database.max_open_conns and max_idle_conns may not be negative")
+	}
+
+	if c.Auth.HashSalt == "" {
+		add("auth.hash_salt is empty")
+	}
+	if c.Auth.SigningKey == "" {
+		add("auth.signing_key is empty")
+	}
+	if c.Auth.TokenTTL < Duration(time.Second) {
+		add("auth.token_ttl must be at least 1s")
+	}
+	for id, secret := range c.Auth.IntrospectClients {
+		if id == "" || secret == "" {
+			add("auth.introspect_clients needs an ID and a secret for every client")
+			break
+		}
+	}
+
+	if !oneOf(c.Log.Level, LogLevels...) {
+		add("log.level must be one of %s, not %q", strings.Join(LogLevels, ", "), c.Log.Level)
+	}
+	if c.Log.Format != LogFormatJSON && c.Log.Format != LogFormatLogfmt {
+		add("log.format must be %q or %q, not %q", LogFormatJSON, LogFormatLogfmt, c.Log.Format)
+	}
+	if !oneOf(c.Log.QueryLevel, "silent", "error", "warn", "info") {
+		add("log.query_level must be one of silent, error, warn, info, not %q", c.Log.QueryLevel)
+	}
+	if c.Log.SlowQuery < 0 {
+		add("log.slow_query may not be negative")
+	}
+
+	if !oneOf(c.Tracing.Exporter, TracingNone, TracingOTLP, TracingStdout) {
+		add("tracing.exporter must be %q, %q or %q, not %q", TracingNone, TracingOTLP, TracingStdout, c.Tracing.Exporter)
+	}
+	if c.Tracing.ServiceName == "" {
+		add("tracing.service_name is empty")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio must be between 0 and 1, not %g", c.Tracing.SampleRatio)
+	}
+
+	if c.Metrics.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Metrics.Addr); err != nil {
+			add("metrics.addr must be host:port, not %q", c.Metrics.Addr)
+		}
+	}
+
+	if c.LinkCheck.Interval <= 0 || c.LinkCheck.MaxAge <= 0 || c.LinkCheck.RetryAfter <= 0 || c.LinkCheck.Timeout <= 0 {
+		add("link_check.interval, max_age, retry_after and timeout must be positive")
+	}
+	if c.LinkCheck.HostDelay < 0 {
+		add("link_check.host_delay may not be negative")
+	}
+	if c.LinkCheck.FailureThreshold < 1 || c.LinkCheck.BatchSize < 1 || c.LinkCheck.Workers < 1 {
+		add("link_check.failure_threshold, batch_size and workers must be at least 1")
+	}
+
+	if c.Env == EnvProduction && c.Auth.SigningKey == DefaultSigningKey {
+		add("auth.signing_key is still the default, which is not allowed in production")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func oneOf(s string, values ...string) bool {
+	for _, value := range values {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders c as YAML, with secrets redacted.
+func (c *Config) String() string {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
+
+// IntrospectSecrets returns the introspection clients with their secrets in
+// the clear, for the auth middleware.
+func (a AuthConfig) IntrospectSecrets() map[string]string {
+	clients := make(map[string]string, len(a.IntrospectClients))
+	for id, secret := range a.IntrospectClients {
+		clients[id] = string(secret)
+	}
+	return clients
+}
+
+const redacted = "[REDACTED]"
+
+// Secret is a string that is never printed as it is: fmt, YAML, TOML and
+// JSON all get a placeholder. Convert it to a string to use it.
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Duration is a time.Duration written like "24h" or "90m" in files,
+// the environment and flags.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
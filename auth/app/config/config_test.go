@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, _, err := load(nil, env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+	assert.Equal(t, 8000, cfg.Server.Port)
+	assert.Equal(t, Duration(24*time.Hour), cfg.Auth.TokenTTL)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "app.yaml", `
+server:
+  port: 9000
+  drain_delay: 5s
+  write_timeout: 30s
+database:
+  host: db.internal
+  name: bookmarks
+auth:
+  token_ttl: 1h
+  introspect_clients:
+    gateway: s3cret
+`)
+
+	cfg, args, err := load([]string{"-config", path, "-db.name", "from_flag", "up", "2"}, env(map[string]string{
+		"APP_DB_HOST": "db.env",
+		"APP_DB_NAME": "from_env",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, Duration(5*time.Second), cfg.Server.DrainDelay)
+	assert.Equal(t, Duration(10*time.Second), cfg.Server.ReadTimeout)
+	assert.Equal(t, Duration(30*time.Second), cfg.Server.WriteTimeout)
+	assert.Equal(t, "db.env", cfg.Database.Host)
+	assert.Equal(t, "from_flag", cfg.Database.Name)
+	assert.Equal(t, "root", cfg.Database.User)
+	assert.Equal(t, 20, cfg.Database.MaxOpenConns)
+	assert.Equal(t, Duration(time.Hour), cfg.Auth.TokenTTL)
+	assert.Equal(t, map[string]string{"gateway": "s3cret"}, cfg.Auth.IntrospectSecrets())
+	assert.Equal(t, []string{"up", "2"}, args)
+
+	cfg, _, err = load([]string{"-log.slow-query", "1s"}, env(map[string]string{"APP_LOG_FORMAT": "logfmt"}))
+	require.NoError(t, err)
+	assert.Equal(t, LogConfig{Level: "info", Format: LogFormatLogfmt, QueryLevel: "warn", SlowQuery: Duration(time.Second)}, cfg.Log)
+
+	cfg, _, err = load([]string{"-tracing.sample-ratio", "0.25"}, env(map[string]string{"APP_TRACING_EXPORTER": "otlp", "APP_TRACING_ENDPOINT": "http://collector:4318"}))
+	require.NoError(t, err)
+	assert.Equal(t, TracingConfig{Exporter: TracingOTLP, Endpoint: "http://collector:4318", ServiceName: "go-clean-architecture-sql", SampleRatio: 0.25}, cfg.Tracing)
+
+	cfg, _, err = load([]string{"-link-check.failure-threshold", "5"}, env(map[string]string{"APP_LINK_CHECK_RETRY_AFTER": "30m"}))
+	require.NoError(t, err)
+	assert.Equal(t, Duration(30*time.Minute), cfg.LinkCheck.RetryAfter)
+	assert.Equal(t, 5, cfg.LinkCheck.FailureThreshold)
+	assert.Equal(t, Duration(time.Hour), cfg.LinkCheck.Interval)
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "app.toml", `
+env = "production"
+
+[auth]
+signing_key = "a-long-random-key"
+token_ttl = "90m"
+`)
+
+	cfg, _, err := load(nil, env(map[string]string{"APP_CONFIG": path, "APP_AUTH_INTROSPECT_CLIENTS": "a:x,b:y:z"}))
+	require.NoError(t, err)
+	assert.Equal(t, EnvProduction, cfg.Env)
+	assert.Equal(t, Secret("a-long-random-key"), cfg.Auth.SigningKey)
+	assert.Equal(t, Duration(90*time.Minute), cfg.Auth.TokenTTL)
+	assert.Equal(t, map[string]string{"a": "x", "b": "y:z"}, cfg.Auth.IntrospectSecrets())
+}
+
+func TestLoad_Failed(t *testing.T) {
+	_, _, err := load(nil, env(map[string]string{"APP_ENV": "production"}))
+	assert.EqualError(t, err, "invalid config: auth.signing_key is still the default, which is not allowed in production")
+
+	_, _, err = load([]string{"-port", "eighty"}, env(nil))
+	assert.Error(t, err)
+	_, _, err = load(nil, env(map[string]string{"APP_AUTH_TOKEN_TTL": "forever"}))
+	assert.Error(t, err)
+
+	_, _, err = load([]string{"-config", writeFile(t, "app.yaml", "databse:\n  host: x\n")}, env(nil))
+	assert.Error(t, err, "unknown keys are refused")
+	_, _, err = load([]string{"-config", writeFile(t, "app.json", "{}")}, env(nil))
+	assert.Error(t, err)
+
+	_, _, err = load([]string{"-port", "0", "-drain-delay", "-5s", "-shutdown-timeout", "0s", "-read-timeout", "0s", "-write-timeout", "500ms", "-db.host", "", "-db.max-open-conns", "-1", "-env", "staging"}, env(nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.port 0 is out of range")
+	assert.Contains(t, err.Error(), "server.drain_delay may not be negative")
+	assert.Contains(t, err.Error(), "server.shutdown_timeout must be at least 1s")
+	assert.Contains(t, err.Error(), "server.read_timeout must be at least 1s")
+	assert.Contains(t, err.Error(), "server.write_timeout must be at least 1s")
+	assert.Contains(t, err.Error(), "database.max_open_conns and max_idle_conns may not be negative")
+	assert.Contains(t, err.Error(), "database.host is empty")
+	assert.Contains(t, err.Error(), `env must be "development" or "production", not "staging"`)
+
+	_, _, err = load([]string{"-log.level", "loud", "-log.format", "xml", "-log.query-level", "all", "-log.slow-query", "-1s"}, env(nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `log.level must be one of debug, info, warn, error, not "loud"`)
+	assert.Contains(t, err.Error(), `log.format must be "json" or "logfmt", not "xml"`)
+	assert.Contains(t, err.Error(), `log.query_level must be one of silent, error, warn, info, not "all"`)
+	assert.Contains(t, err.Error(), "log.slow_query may not be negative")
+
+	_, _, err = load([]string{"-tracing.exporter", "jaeger", "-tracing.sample-ratio", "2", "-metrics.addr", "9464"}, env(nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `tracing.exporter must be "none", "otlp" or "stdout", not "jaeger"`)
+	assert.Contains(t, err.Error(), "tracing.sample_ratio must be between 0 and 1, not 2")
+	assert.Contains(t, err.Error(), `metrics.addr must be host:port, not "9464"`)
+
+	_, _, err = load([]string{"-link-check.interval", "0s", "-link-check.host-delay", "-1s", "-link-check.workers", "0"}, env(nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "link_check.interval, max_age, retry_after and timeout must be positive")
+	assert.Contains(t, err.Error(), "link_check.host_delay may not be negative")
+	assert.Contains(t, err.Error(), "link_check.failure_threshold, batch_size and workers must be at least 1")
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "hunter2"
+	cfg.Auth.SigningKey = "very-secret"
+	cfg.Auth.IntrospectClients = map[string]Secret{"gateway": "s3cret"}
+
+	for _, out := range []string{cfg.String(), fmt.Sprintf("%v", cfg.Auth), fmt.Sprintf("%+v", cfg.Database), fmt.Sprintf("%#v", cfg.Auth)} {
+		assert.NotContains(t, out, "hunter2")
+		assert.NotContains(t, out, "very-secret")
+		assert.NotContains(t, out, "s3cret")
+	}
+	assert.Contains(t, cfg.String(), "signing_key: '[REDACTED]'")
+	assert.Contains(t, cfg.String(), "token_ttl: 24h0m0s")
+	assert.Contains(t, cfg.String(), "gateway: '[REDACTED]'")
+	assert.Equal(t, "very-secret", string(cfg.Auth.SigningKey))
+}
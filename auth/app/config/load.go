@@ -0,0 +1,237 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the name of every environment variable Load reads, as in
+// APP_DB_HOST or APP_AUTH_SIGNING_KEY.
+const EnvPrefix = "APP_"
+
+// setting is one value that can be set from the environment and by a flag.
+// The flag is called name; the variable is name upper-cased, with dots and
+// dashes turned into underscores, after EnvPrefix.
+type setting struct {
+	name  string
+	usage string
+	value func(c *Config) flag.Value
+}
+
+var settings = []setting{
+	{"env", "development or production", func(c *Config) flag.Value { return (*stringValue)(&c.Env) }},
+	{"port", "HTTP port", func(c *Config) flag.Value { return (*intValue)(&c.Server.Port) }},
+	{"read-timeout", "time allowed to read a request", func(c *Config) flag.Value { return &c.Server.ReadTimeout }},
+	{"write-timeout", "time allowed to answer a request, except exports", func(c *Config) flag.Value { return &c.Server.WriteTimeout }},
+	{"drain-delay", "time reported not ready on shutdown before requests stop", func(c *Config) flag.Value { return &c.Server.DrainDelay }},
+	{"shutdown-timeout", "time given to requests and workers to finish on shutdown", func(c *Config) flag.Value { return &c.Server.ShutdownTimeout }},
+	{"db.driver", "database driver: mysql, postgres or sqlite", func(c *Config) flag.Value { return (*stringValue)(&c.Database.Driver) }},
+	{"db.host", "database host", func(c *Config) flag.Value { return (*stringValue)(&c.Database.Host) }},
+	{"db.port", "database port, 0 for the driver default", func(c *Config) flag.Value { return (*intValue)(&c.Database.Port) }},
+	{"db.user", "database user", func(c *Config) flag.Value { return (*stringValue)(&c.Database.User) }},
+	{"db.password", "database password", func(c *Config) flag.Value { return (*secretValue)(&c.Database.Password) }},
+	{"db.name", "database name, or file for sqlite", func(c *Config) flag.Value { return (*stringValue)(&c.Database.Name) }},
+	{"db.connect-timeout", "time to wait for the database on startup, 0 to try once", func(c *Config) flag.Value { return &c.Database.ConnectTimeout }},
+	{"db.max-open-conns", "most open connections, 0 for no limit", func(c *Config) flag.Value { return (*intValue)(&c.Database.MaxOpenConns) }},
+	{"db.max-idle-conns", "most idle connections kept, 0 for the database/sql default", func(c *Config) flag.Value { return (*intValue)(&c.Database.MaxIdleConns) }},
+	{"db.conn-max-lifetime", "connections older than this are closed, 0 for never", func(c *Config) flag.Value { return &c.Database.ConnMaxLifetime }},
+	{"db.conn-max-idle-time", "connections idle for longer are closed, 0 for never", func(c *Config) flag.Value { return &c.Database.ConnMaxIdleTime }},
+	{"auth.hash-salt", "salt of password hashes", func(c *Config) flag.Value { return (*secretValue)(&c.Auth.HashSalt) }},
+	{"auth.signing-key", "key signing access tokens", func(c *Config) flag.Value { return (*secretValue)(&c.Auth.SigningKey) }},
+	{"auth.token-ttl", "lifetime of access tokens, like 24h", func(c *Config) flag.Value { return &c.Auth.TokenTTL }},
+	{"auth.introspect-clients", "introspection clients as id:secret,id:secret", func(c *Config) flag.Value { return (*clientsValue)(&c.Auth.IntrospectClients) }},
+	{"log.level", "least level logged: debug, info, warn or error", func(c *Config) flag.Value { return (*stringValue)(&c.Log.Level) }},
+	{"log.format", "log format: json or logfmt", func(c *Config) flag.Value { return (*stringValue)(&c.Log.Format) }},
+	{"log.query-level", "queries logged: silent, error, warn (slow ones too) or info (all)", func(c *Config) flag.Value { return (*stringValue)(&c.Log.QueryLevel) }},
+	{"log.slow-query", "queries slower than this are logged as slow, 0 for never", func(c *Config) flag.Value { return &c.Log.SlowQuery }},
+	{"tracing.exporter", "where spans go: none, otlp or stdout", func(c *Config) flag.Value { return (*stringValue)(&c.Tracing.Exporter) }},
+	{"tracing.endpoint", "URL of the OTLP/HTTP collector, like http://localhost:4318", func(c *Config) flag.Value { return (*stringValue)(&c.Tracing.Endpoint) }},
+	{"tracing.service-name", "service name of the spans", func(c *Config) flag.Value { return (*stringValue)(&c.Tracing.ServiceName) }},
+	{"tracing.sample-ratio", "share of new traces kept, from 0 to 1", func(c *Config) flag.Value { return (*floatValue)(&c.Tracing.SampleRatio) }},
+	{"metrics.addr", "host:port to serve /metrics on, empty for none", func(c *Config) flag.Value { return (*stringValue)(&c.Metrics.Addr) }},
+	{"link-check.interval", "time between rounds of link checks", func(c *Config) flag.Value { return &c.LinkCheck.Interval }},
+	{"link-check.max-age", "links checked longer ago than this are due again", func(c *Config) flag.Value { return &c.LinkCheck.MaxAge }},
+	{"link-check.retry-after", "links whose check failed are due again after this", func(c *Config) flag.Value { return &c.LinkCheck.RetryAfter }},
+	{"link-check.failure-threshold", "failed checks in a row before a link is broken", func(c *Config) flag.Value { return (*intValue)(&c.LinkCheck.FailureThreshold) }},
+	{"link-check.batch-size", "most links checked in one round", func(c *Config) flag.Value { return (*intValue)(&c.LinkCheck.BatchSize) }},
+	{"link-check.workers", "links checked at once", func(c *Config) flag.Value { return (*intValue)(&c.LinkCheck.Workers) }},
+	{"link-check.host-delay", "time between requests to one host", func(c *Config) flag.Value { return &c.LinkCheck.HostDelay }},
+	{"link-check.timeout", "time given to one link check", func(c *Config) flag.Value { return &c.LinkCheck.Timeout }},
+}
+
+func envName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+}
+
+// Load builds the configuration from, lowest precedence first: the
+// defaults, the file named by -config or APP_CONFIG (YAML or TOML, told
+// apart by extension), environment variables and the flags in args. The
+// result is validated. The arguments after the flags are returned as well.
+func Load(args []string) (*Config, []string, error) {
+	return load(args, os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (*Config, []string, error) {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	path := fs.String("config", "", "config file, .yaml or .toml (env "+EnvPrefix+"CONFIG)")
+	flagged := make(map[string]*rawValue, len(settings))
+	for _, s := range settings {
+		flagged[s.name] = new(rawValue)
+		fs.Var(flagged[s.name], s.name, s.usage+" (env "+envName(s.name)+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	cfg := Default()
+
+	if *path == "" {
+		*path, _ = lookupEnv(EnvPrefix + "CONFIG")
+	}
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := lookupEnv(envName(s.name)); ok {
+			if err := s.value(cfg).Set(value); err != nil {
+				return nil, nil, fmt.Errorf("%s: %v", envName(s.name), err)
+			}
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.name == f.Name && err == nil {
+				if setErr := s.value(cfg).Set(string(*flagged[s.name])); setErr != nil {
+					err = fmt.Errorf("-%s: %v", s.name, setErr)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+// loadFile reads path over c. Unknown keys are an error, so a misspelled
+// one does not go unnoticed.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(c)
+		if err == io.EOF {
+			// An empty file changes nothing.
+			err = nil
+		}
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	default:
+		return fmt.Errorf("%s: config files must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// rawValue keeps what a flag was given, to be applied once the file and the
+// environment are in.
+type rawValue string
+
+func (v *rawValue) String() string     { return string(*v) }
+func (v *rawValue) Set(s string) error { *v = rawValue(s); return nil }
+
+type stringValue string
+
+func (v *stringValue) String() string     { return string(*v) }
+func (v *stringValue) Set(s string) error { *v = stringValue(s); return nil }
+
+type secretValue Secret
+
+func (v *secretValue) String() string     { return Secret(*v).String() }
+func (v *secretValue) Set(s string) error { *v = secretValue(s); return nil }
+
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", s)
+	}
+	*v = intValue(n)
+	return nil
+}
+
+type floatValue float64
+
+func (v *floatValue) String() string { return strconv.FormatFloat(float64(*v), 'g', -1, 64) }
+
+func (v *floatValue) Set(s string) error {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", s)
+	}
+	*v = floatValue(f)
+	return nil
+}
+
+func (d *Duration) Set(s string) error {
+	return d.UnmarshalText([]byte(s))
+}
+
+type clientsValue map[string]Secret
+
+func (v *clientsValue) String() string {
+	ids := make([]string, 0, len(*v))
+	for id := range *v {
+		ids = append(ids, id+":"+redacted)
+	}
+	return strings.Join(ids, ",")
+}
+
+// Set replaces the clients, rather than adding to them, so a variable or
+// flag says exactly who may introspect.
+func (v *clientsValue) Set(s string) error {
+	clients := make(map[string]Secret)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		i := strings.Index(pair, ":")
+		if i < 0 {
+			return fmt.Errorf("client %q has no secret, want id:secret", strings.TrimSpace(pair))
+		}
+		clients[strings.TrimSpace(pair[:i])] = Secret(pair[i+1:])
+	}
+	*v = clients
+	return nil
+}
@@ -1,22 +1,175 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
-	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database/migrate"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
-func SetupDatabase() *gorm.DB {
+// Back-off between attempts to connect.
+const (
+	retryMin = 500 * time.Millisecond
+	retryMax = 10 * time.Second
+)
+
+// SetupDatabase connects to the database as Connect does and applies the
+// migrations it is missing.
+func SetupDatabase(ctx context.Context, cfg config.DatabaseConfig, queryLog logger.Interface, log *slog.Logger) (*gorm.DB, error) {
+	db, err := Connect(ctx, cfg, queryLog, log)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migrate.New(db)
+	if err == nil {
+		_, err = migrator.Up(0)
+	}
+	if err != nil {
+		Close(db)
+		return nil, err
+	}
+	return db, nil
+}
+
+// Connect opens the database as Open does. While it cannot, it tries again
+// with a growing pause for up to cfg.ConnectTimeout, so that the server can
+// start along with its database, or until ctx is done.
+func Connect(ctx context.Context, cfg config.DatabaseConfig, queryLog logger.Interface, log *slog.Logger) (*gorm.DB, error) {
+	deadline := time.Now().Add(time.Duration(cfg.ConnectTimeout))
+	wait := retryMin
+	for attempt := 1; ; attempt++ {
+		db, err := Open(cfg, queryLog)
+		if err == nil {
+			return db, nil
+		}
+		if time.Now().Add(wait).After(deadline) {
+			if attempt == 1 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("database: gave up after %d attempts: %w", attempt, err)
+		}
 
-	DSN := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", config.DBUser, config.DBPass, config.DBHost, config.DBPort, config.DBName)
+		log.Warn("database: failed to connect, retrying", "attempt", attempt, "retry_in", wait, "err", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > retryMax {
+			wait = retryMax
+		}
+	}
+}
+
+// Open connects to the database cfg names, with the pool cfg sets up and
+// the errors of its driver translated by dberr. Queries are logged to
+// queryLog, or to the default logger of GORM when it is nil.
+func Open(cfg config.DatabaseConfig, queryLog logger.Interface) (*gorm.DB, error) {
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	db, err := gorm.Open(mysql.Open(DSN), &gorm.Config{})
+	// Connect reports failing to connect; gorm would log it on every
+	// attempt.
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
 	if err != nil {
-		panic(err)
+		// A database that is down fails the ping of gorm, which leaves the
+		// pool it opened to us.
+		if db != nil && db.ConnPool != nil {
+			Close(db)
+		}
+		return nil, err
+	}
+	if queryLog == nil {
+		queryLog = logger.Default
+	}
+	db.Logger = queryLog
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Driver == config.DriverSQLite {
+		// SQLite writes one at a time anyway, and every connection to
+		// ":memory:" would get a database of its own, which closing the
+		// connection would lose.
+		sqlDB.SetMaxOpenConns(1)
+	} else {
+		tunePool(sqlDB, cfg)
+	}
+
+	if err := dberr.Register(db); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func tunePool(sqlDB *sql.DB, cfg config.DatabaseConfig) {
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime))
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime))
+	}
+}
+
+// Close closes the connections of db.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Dialector picks the gorm driver for cfg.Driver and builds its DSN.
+func Dialector(cfg config.DatabaseConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case config.DriverMySQL, "":
+		DSN := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", cfg.User, string(cfg.Password), hostPort(cfg, 3306), cfg.Name)
+		return mysql.Open(DSN), nil
+	case config.DriverPostgres:
+		DSN := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(cfg.User, string(cfg.Password)),
+			Host:   hostPort(cfg, 5432),
+			Path:   "/" + cfg.Name,
+		}
+		return postgres.Open(DSN.String()), nil
+	case config.DriverSQLite:
+		DSN := cfg.Name + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+		return sqlite.Open(DSN), nil
+	}
+	return nil, fmt.Errorf("unknown database driver %q", cfg.Driver)
+}
+
+func hostPort(cfg config.DatabaseConfig, defaultPort int) string {
+	port := cfg.Port
+	if port == 0 {
+		port = defaultPort
 	}
-	db.AutoMigrate(&models.User{})
-	return db
+	return net.JoinHostPort(cfg.Host, strconv.Itoa(port))
 }
@@ -1,24 +1,84 @@
 package database
 
 import (
+	"bytes"
+	"context"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
 )
 
+func sqliteConfig(name string) config.DatabaseConfig {
+	return config.DatabaseConfig{Driver: config.DriverSQLite, Name: name}
+}
+
 func TestSetupDatabase(t *testing.T) {
-	dbreal := SetupDatabase()
-	assert.NoError(t, dbreal.Error)
-
-	func() {
-		config.DBName = "seharusnya_gaada_sih"
-		defer func() {
-			if r := recover(); r == nil {
-				t.Error("SetupDatabase should have panicked!")
-			}
-		}()
-		// This function should cause a panic
-		SetupDatabase()
-	}()
+	cfg := sqliteConfig(filepath.Join(t.TempDir(), "test.db"))
+	dbreal, err := SetupDatabase(context.Background(), cfg, nil, logging.Discard())
+	require.NoError(t, err)
+	assert.True(t, dbreal.Migrator().HasTable(&models.User{}))
+	assert.NoError(t, Close(dbreal))
+
+	cfg.Name = filepath.Join(t.TempDir(), "seharusnya_gaada_sih", "test.db")
+	_, err = SetupDatabase(context.Background(), cfg, nil, logging.Discard())
+	assert.Error(t, err)
+}
+
+func TestConnect_Retries(t *testing.T) {
+	cfg := config.Default().Database
+	// Nothing listens on port 1, so every attempt is refused at once.
+	cfg.Port = 1
+	cfg.ConnectTimeout = config.Duration(time.Minute)
+	out := new(bytes.Buffer)
+	log := logging.New(config.LogConfig{Level: "info", Format: config.LogFormatLogfmt}, out)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	_, err := Connect(ctx, cfg, logger.Discard, log)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "a shutdown stops the retries")
+	assert.Contains(t, out.String(), `msg="database: failed to connect, retrying" attempt=1 retry_in=500ms`)
+	assert.Contains(t, out.String(), "attempt=2 retry_in=1s")
+
+	cfg.ConnectTimeout = 0
+	_, err = Connect(context.Background(), cfg, logger.Discard, log)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "gave up", "without a timeout it tries once")
+}
+
+func TestDialector(t *testing.T) {
+	for driver, name := range map[string]string{
+		config.DriverMySQL:    "mysql",
+		config.DriverPostgres: "postgres",
+		config.DriverSQLite:   "sqlite",
+	} {
+		cfg := config.Default().Database
+		cfg.Driver = driver
+		dialector, err := Dialector(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, name, dialector.Name())
+	}
+
+	_, err := Dialector(config.DatabaseConfig{Driver: "oracle"})
+	assert.Error(t, err)
+}
+
+func TestTranslatedErrors(t *testing.T) {
+	db, err := SetupDatabase(context.Background(), sqliteConfig(":memory:"), nil, logging.Discard())
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&models.User{Username: "khuchuz", Email: "a@example.com"}).Error)
+
+	err = db.Create(&models.User{Username: "khuchuz", Email: "b@example.com"}).Error
+	assert.ErrorIs(t, err, dberr.ErrDuplicateKey)
+
+	err = db.Where("username = ?", "nobody").First(&models.User{}).Error
+	assert.ErrorIs(t, err, dberr.ErrNotFound)
 }
@@ -0,0 +1,83 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+)
+
+// Dir is where the migrations are kept, from the root of the repository.
+const Dir = "auth/app/database/migrate/migrations"
+
+// Drivers are the databases every migration is written for.
+var Drivers = []string{config.DriverMySQL, config.DriverPostgres, config.DriverSQLite}
+
+var nameSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create adds empty up and down scripts for a new migration called name to
+// the directory of every driver under dir, numbered after the newest one
+// there, and returns their paths. Load refuses them until they are given
+// statements.
+func Create(dir, name string) ([]string, error) {
+	name = strings.Trim(nameSeparators.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, fmt.Errorf("migrate: a migration needs a name")
+	}
+
+	version, err := newestVersion(dir)
+	if err != nil {
+		return nil, err
+	}
+	version++
+
+	var paths []string
+	for _, driver := range Drivers {
+		if err := os.MkdirAll(filepath.Join(dir, driver), 0755); err != nil {
+			return paths, err
+		}
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver, fmt.Sprintf("%04d_%s.%s.sql", version, name, direction))
+			header := fmt.Sprintf("-- %s (%s, %s)\n", name, driver, direction)
+			if err := os.WriteFile(path, []byte(header), 0644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// newestVersion is the highest version among the scripts of every driver
+// under dir. It only reads the names, so that a migration still waiting for
+// its statements counts.
+func newestVersion(dir string) (uint, error) {
+	var version uint
+	for _, driver := range Drivers {
+		entries, err := os.ReadDir(filepath.Join(dir, driver))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range entries {
+			match := fileName.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			v, err := strconv.ParseUint(match[1], 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("migrate: %s: %v", filepath.Join(dir, driver, entry.Name()), err)
+			}
+			if uint(v) > version {
+				version = uint(v)
+			}
+		}
+	}
+	return version, nil
+}
@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/crc32"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"gorm.io/gorm"
+)
+
+// LockTimeout is how long to wait for another server to finish migrating.
+var LockTimeout = time.Minute
+
+const lockName = "schema_migrations"
+
+var lockers = map[string]func(db *gorm.DB) (func(), error){
+	config.DriverMySQL:    lockMySQL,
+	config.DriverPostgres: lockPostgres,
+	config.DriverSQLite:   lockSQLite,
+}
+
+// lockMySQL takes a named lock, which belongs to the connection that took
+// it, so one connection is kept aside until the lock is let go.
+func lockMySQL(db *gorm.DB) (func(), error) {
+	conn, err := dedicatedConn(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var got sql.NullInt64
+	err = conn.QueryRowContext(context.Background(), "SELECT GET_LOCK(CONCAT(DATABASE(), '.', ?), ?)", lockName, int(LockTimeout/time.Second)).Scan(&got)
+	if err == nil && got.Int64 != 1 {
+		err = errors.New("migrate: timed out waiting for another server to finish migrating")
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "DO RELEASE_LOCK(CONCAT(DATABASE(), '.', ?))", lockName)
+		conn.Close()
+	}, nil
+}
+
+// lockPostgres takes a session advisory lock, which like the MySQL one
+// belongs to its connection.
+func lockPostgres(db *gorm.DB) (func(), error) {
+	conn, err := dedicatedConn(db)
+	if err != nil {
+		return nil, err
+	}
+
+	key := int64(crc32.ChecksumIEEE([]byte(lockName)))
+	ctx, cancel := context.WithTimeout(context.Background(), LockTimeout)
+	defer cancel()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, errors.New("migrate: timed out waiting for another server to finish migrating")
+		}
+		return nil, err
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		conn.Close()
+	}, nil
+}
+
+// lockSQLite does nothing: a SQLite database belongs to one server, which
+// keeps a single connection to it, so there is no one to race with and no
+// connection to spare.
+func lockSQLite(*gorm.DB) (func(), error) {
+	return func() {}, nil
+}
+
+func dedicatedConn(db *gorm.DB) (*sql.Conn, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return sqlDB.Conn(context.Background())
+}
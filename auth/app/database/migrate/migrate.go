@@ -0,0 +1,300 @@
+// Package migrate keeps the database schema up to date with versioned SQL
+// migrations. They live in migrations/<driver> as <version>_<name>.up.sql
+// and <version>_<name>.down.sql, and are built into the binary.
+package migrate
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var embedded embed.FS
+
+// Migration is one step of the schema, with the SQL to take it and to take
+// it back.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration along with when it was applied, if it was.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaMigration is a row of the version table, one per applied migration.
+type schemaMigration struct {
+	Version   uint   `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	lock       func(db *gorm.DB) (unlock func(), err error)
+}
+
+// New reads the migrations for the driver of db.
+func New(db *gorm.DB) (*Migrator, error) {
+	driver := db.Dialector.Name()
+	lock, ok := lockers[driver]
+	if !ok {
+		return nil, fmt.Errorf("migrate: no migrations for driver %q", driver)
+	}
+
+	migrations, err := Load(embedded, path.Join("migrations", driver))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations, lock: lock}, nil
+}
+
+// Up applies up to n pending migrations in order, or all of them when n is
+// 0, and returns those it applied.
+func (m *Migrator) Up(n int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(func(applied map[uint]schemaMigration) error {
+		for _, migration := range m.migrations {
+			if n > 0 && len(done) == n {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err := m.apply(migration, migration.Up, func(tx *gorm.DB) error {
+				return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down takes back the last n applied migrations, newest first, and returns
+// those it took back.
+func (m *Migrator) Down(n int) ([]Migration, error) {
+	if n < 1 {
+		return nil, errors.New("migrate: down takes back at least one migration")
+	}
+
+	var done []Migration
+
+	err := m.locked(func(applied map[uint]schemaMigration) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < n; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			err := m.apply(migration, migration.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+			})
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists every migration, oldest first.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending lists the migrations not applied yet, oldest first.
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// locked runs fn with the migration lock held, so that servers starting
+// together do not migrate at the same time, and with the version table in
+// place.
+func (m *Migrator) locked(fn func(applied map[uint]schemaMigration) error) error {
+	unlock, err := m.lock(m.db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !m.db.Migrator().HasTable(&schemaMigration{}) {
+		if err := m.db.Migrator().CreateTable(&schemaMigration{}); err != nil {
+			return err
+		}
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+func (m *Migrator) applied() (map[uint]schemaMigration, error) {
+	applied := make(map[uint]schemaMigration)
+	if !m.db.Migrator().HasTable(&schemaMigration{}) {
+		return applied, nil
+	}
+
+	var rows []schemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// apply runs the statements of script and record in one transaction. MySQL
+// commits each schema change as it goes, so there a failed migration may
+// leave part of it applied and has to be cleaned up by hand.
+func (m *Migrator) apply(migration Migration, script string, record func(tx *gorm.DB) error) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range Statements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("migrate: %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+		}
+		return record(tx)
+	})
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in dir, oldest first. Every migration needs an
+// up and a down script with at least one statement each, so that one made
+// by Create is not applied before it is written, and versions may not
+// repeat.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrate: %s is not named like 0001_name.up.sql", path.Join(dir, entry.Name()))
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %v", path.Join(dir, entry.Name()), err)
+		}
+		script, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrate: %s: version %d is also %s", dir, version, migration.Name)
+		}
+		if match[3] == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if len(Statements(migration.Up)) == 0 || len(Statements(migration.Down)) == 0 {
+			return nil, fmt.Errorf("migrate: %s: %04d_%s needs statements in both its up and down script", dir, migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Statements splits a script into its statements, each of which ends with a
+// semicolon at the end of a line. Lines starting with -- are comments.
+func Statements(script string) []string {
+	var (
+		statements []string
+		current    []string
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t\r"))
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.Join(current, "\n")
+			statements = append(statements, strings.TrimSuffix(statement, ";"))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		statements = append(statements, strings.Join(current, "\n"))
+	}
+	return statements
+}
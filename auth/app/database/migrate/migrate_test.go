@@ -0,0 +1,159 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/glebarez/sqlite"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	bookmarkmodels "github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func openSQLite(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	db := openSQLite(t)
+	migrator, err := New(db)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	assert.Nil(t, statuses[0].AppliedAt)
+
+	done, err := migrator.Up(1)
+	require.NoError(t, err)
+	assert.Len(t, done, 1)
+	pending, err := migrator.Pending()
+	require.NoError(t, err)
+	assert.Len(t, pending, len(statuses)-1)
+
+	done, err = migrator.Up(0)
+	require.NoError(t, err)
+	assert.Len(t, done, len(statuses)-1)
+	assert.True(t, db.Migrator().HasTable("bookmarks"))
+	pending, err = migrator.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	done, err = migrator.Up(0)
+	require.NoError(t, err)
+	assert.Empty(t, done)
+
+	statuses, err = migrator.Status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, status.Name)
+	}
+
+	done, err = migrator.Down(len(statuses))
+	require.NoError(t, err)
+	assert.Len(t, done, len(statuses))
+	assert.False(t, db.Migrator().HasTable("bookmarks"))
+
+	_, err = migrator.Down(0)
+	assert.Error(t, err)
+}
+
+// The migrations have to keep up with the models: every field needs its
+// column.
+func TestMigrator_ModelsHaveColumns(t *testing.T) {
+	db := openSQLite(t)
+	migrator, err := New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(0)
+	require.NoError(t, err)
+
+	for _, model := range []interface{}{&models.User{}, &bookmarkmodels.Bookmark{}, &bookmarkmodels.Tag{}, &bookmarkmodels.BookmarkTag{}, &bookmarkmodels.Collection{}, &bookmarkmodels.Share{}, &bookmarkmodels.BookmarkContent{}, &bookmarkmodels.ImportJob{}, &bookmarkmodels.ImportError{}, &bookmarkmodels.Highlight{}, &bookmarkmodels.Feed{}} {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			assert.True(t, db.Migrator().HasColumn(model, field.DBName), "%s.%s", stmt.Schema.Table, field.DBName)
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			assert.True(t, db.Migrator().HasIndex(model, index.Name), "%s: %s", stmt.Schema.Table, index.Name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(fstest.MapFS{
+		"m/0002_add_notes.up.sql":   {Data: []byte("ALTER TABLE a ADD notes text;")},
+		"m/0002_add_notes.down.sql": {Data: []byte("ALTER TABLE a DROP notes;")},
+		"m/0001_init.up.sql":        {Data: []byte("CREATE TABLE a (id integer);")},
+		"m/0001_init.down.sql":      {Data: []byte("DROP TABLE a;")},
+	}, "m")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "init", Up: "CREATE TABLE a (id integer);", Down: "DROP TABLE a;"}, migrations[0])
+	assert.Equal(t, uint(2), migrations[1].Version)
+
+	_, err = Load(fstest.MapFS{"m/0001_init.up.sql": {Data: []byte("CREATE TABLE a (id integer);")}}, "m")
+	assert.Error(t, err)
+
+	_, err = Load(fstest.MapFS{"m/init.sql": {Data: []byte("CREATE TABLE a (id integer);")}}, "m")
+	assert.Error(t, err)
+
+	_, err = Load(fstest.MapFS{
+		"m/0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id integer);")},
+		"m/0001_other.down.sql": {Data: []byte("DROP TABLE a;")},
+	}, "m")
+	assert.Error(t, err)
+
+	_, err = Load(fstest.MapFS{
+		"m/0001_init.up.sql":   {Data: []byte("-- init (sqlite, up)\n")},
+		"m/0001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+	}, "m")
+	assert.Error(t, err)
+}
+
+func TestStatements(t *testing.T) {
+	statements := Statements(`-- two tables
+CREATE TABLE a (
+	id integer
+);
+
+CREATE TABLE b (id integer);
+INSERT INTO b VALUES (1)`)
+
+	assert.Equal(t, []string{
+		"CREATE TABLE a (\n\tid integer\n)",
+		"CREATE TABLE b (id integer)",
+		"INSERT INTO b VALUES (1)",
+	}, statements)
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := Create(dir, "Add reading list")
+	require.NoError(t, err)
+	assert.Len(t, paths, 2*len(Drivers))
+	assert.FileExists(t, filepath.Join(dir, "sqlite", "0001_add_reading_list.up.sql"))
+
+	paths, err = Create(dir, "drop-notes")
+	require.NoError(t, err)
+	assert.Contains(t, paths, filepath.Join(dir, "mysql", "0002_drop_notes.down.sql"))
+
+	// They are not run until they are written.
+	_, err = Load(os.DirFS(dir), "postgres")
+	assert.Error(t, err)
+
+	_, err = Create(dir, " - ")
+	assert.Error(t, err)
+}
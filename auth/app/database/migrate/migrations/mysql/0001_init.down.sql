@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS feeds;
+DROP TABLE IF EXISTS highlights;
+DROP TABLE IF EXISTS import_errors;
+DROP TABLE IF EXISTS import_jobs;
+DROP TABLE IF EXISTS bookmark_contents;
+DROP TABLE IF EXISTS shares;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS bookmark_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS users;
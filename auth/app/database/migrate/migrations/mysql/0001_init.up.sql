@@ -0,0 +1,184 @@
+-- The schema as AutoMigrate used to leave it. IF NOT EXISTS lets this run
+-- over a database it created; the indexes are in the tables, as MySQL has
+-- no CREATE INDEX IF NOT EXISTS.
+
+CREATE TABLE IF NOT EXISTS users (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	username varchar(191),
+	email varchar(191),
+	password longtext,
+	PRIMARY KEY (id),
+	UNIQUE INDEX idx_users_username (username),
+	UNIQUE INDEX idx_users_email (email)
+);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	user_id bigint unsigned,
+	url longtext,
+	title longtext,
+	notes longtext,
+	created_at datetime(3) NULL,
+	updated_at datetime(3) NULL,
+	normalized_url longtext,
+	url_hash varchar(64),
+	canonical_hash varchar(64),
+	description text,
+	canonical_url longtext,
+	image_url longtext,
+	favicon_url longtext,
+	site_name longtext,
+	fetched_at datetime(3) NULL,
+	fetch_error longtext,
+	word_count bigint,
+	reading_time bigint,
+	status varchar(16) DEFAULT 'unread',
+	favorite boolean,
+	priority bigint,
+	progress bigint,
+	read_at datetime(3) NULL,
+	archived_at datetime(3) NULL,
+	favorited_at datetime(3) NULL,
+	collection_id bigint unsigned,
+	public boolean,
+	link_status varchar(16),
+	link_code bigint,
+	redirect_url longtext,
+	link_error longtext,
+	link_checked_at datetime(3) NULL,
+	PRIMARY KEY (id),
+	INDEX idx_bookmarks_user_id (user_id),
+	UNIQUE INDEX idx_bookmarks_user_url_hash (user_id, url_hash),
+	INDEX idx_bookmarks_canonical_hash (canonical_hash),
+	INDEX idx_bookmarks_status (status),
+	INDEX idx_bookmarks_collection_id (collection_id),
+	INDEX idx_bookmarks_public (public),
+	INDEX idx_bookmarks_link_status (link_status),
+	INDEX idx_bookmarks_link_checked_at (link_checked_at)
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	user_id bigint unsigned,
+	name varchar(100),
+	PRIMARY KEY (id),
+	UNIQUE INDEX idx_tags_user_name (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS bookmark_tags (
+	bookmark_id bigint unsigned,
+	tag_id bigint unsigned,
+	PRIMARY KEY (bookmark_id, tag_id),
+	CONSTRAINT fk_bookmark_tags_bookmark FOREIGN KEY (bookmark_id) REFERENCES bookmarks (id),
+	CONSTRAINT fk_bookmark_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	user_id bigint unsigned,
+	parent_id bigint unsigned,
+	name varchar(100),
+	path varchar(255),
+	position bigint,
+	created_at datetime(3) NULL,
+	updated_at datetime(3) NULL,
+	PRIMARY KEY (id),
+	INDEX idx_collections_user_path (user_id, path),
+	INDEX idx_collections_parent_id (parent_id)
+);
+
+CREATE TABLE IF NOT EXISTS shares (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	user_id bigint unsigned,
+	token varchar(64),
+	bookmark_id bigint unsigned,
+	collection_id bigint unsigned,
+	protected boolean,
+	password_salt varchar(32),
+	password_hash varchar(64),
+	expires_at datetime(3) NULL,
+	views bigint,
+	last_viewed_at datetime(3) NULL,
+	revoked_at datetime(3) NULL,
+	created_at datetime(3) NULL,
+	PRIMARY KEY (id),
+	INDEX idx_shares_user_id (user_id),
+	UNIQUE INDEX idx_shares_token (token),
+	INDEX idx_shares_bookmark_id (bookmark_id),
+	INDEX idx_shares_collection_id (collection_id)
+);
+
+CREATE TABLE IF NOT EXISTS bookmark_contents (
+	bookmark_id bigint unsigned NOT NULL,
+	user_id bigint unsigned,
+	html mediumtext,
+	text mediumtext,
+	word_count bigint,
+	reading_time bigint,
+	extracted_at datetime(3) NULL,
+	PRIMARY KEY (bookmark_id),
+	INDEX idx_bookmark_contents_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	user_id bigint unsigned,
+	format varchar(20),
+	folder_mode varchar(20),
+	status varchar(20),
+	total bigint,
+	processed bigint,
+	imported bigint,
+	duplicates bigint,
+	failed bigint,
+	error longtext,
+	created_at datetime(3) NULL,
+	updated_at datetime(3) NULL,
+	finished_at datetime(3) NULL,
+	PRIMARY KEY (id),
+	INDEX idx_import_jobs_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS import_errors (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	job_id bigint unsigned,
+	line bigint,
+	url longtext,
+	error longtext,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_import_jobs_errors FOREIGN KEY (job_id) REFERENCES import_jobs (id),
+	INDEX idx_import_errors_job_id (job_id)
+);
+
+CREATE TABLE IF NOT EXISTS highlights (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	user_id bigint unsigned,
+	bookmark_id bigint unsigned,
+	start_offset bigint,
+	end_offset bigint,
+	quote text,
+	prefix varchar(255),
+	suffix varchar(255),
+	note text,
+	color varchar(16),
+	created_at datetime(3) NULL,
+	updated_at datetime(3) NULL,
+	PRIMARY KEY (id),
+	INDEX idx_highlights_user_id (user_id),
+	INDEX idx_highlights_bookmark_id (bookmark_id)
+);
+
+CREATE TABLE IF NOT EXISTS feeds (
+	id bigint unsigned AUTO_INCREMENT NOT NULL,
+	user_id bigint unsigned,
+	token varchar(64),
+	tag_id bigint unsigned,
+	collection_id bigint unsigned,
+	revoked_at datetime(3) NULL,
+	created_at datetime(3) NULL,
+	PRIMARY KEY (id),
+	INDEX idx_feeds_user_id (user_id),
+	UNIQUE INDEX idx_feeds_token (token),
+	INDEX idx_feeds_tag_id (tag_id),
+	INDEX idx_feeds_collection_id (collection_id)
+);
@@ -0,0 +1,4 @@
+-- user_roles (mysql, down)
+ALTER TABLE users
+	DROP COLUMN disabled,
+	DROP COLUMN role;
@@ -0,0 +1,2 @@
+-- search_documents (mysql, down)
+DROP TABLE IF EXISTS search_documents;
@@ -0,0 +1,14 @@
+-- search_documents (mysql, up)
+-- IF NOT EXISTS takes over the table the search index used to create for
+-- itself at startup, FULLTEXT index included.
+CREATE TABLE IF NOT EXISTS search_documents (
+	bookmark_id bigint unsigned NOT NULL,
+	user_id bigint unsigned,
+	title text,
+	url text,
+	notes text,
+	tags text,
+	PRIMARY KEY (bookmark_id),
+	INDEX idx_search_documents_user_id (user_id),
+	FULLTEXT INDEX idx_search_documents_fulltext (title, url, notes, tags)
+);
@@ -0,0 +1,4 @@
+-- search_content (mysql, down)
+ALTER TABLE search_documents DROP INDEX idx_search_documents_fulltext;
+CREATE FULLTEXT INDEX idx_search_documents_fulltext ON search_documents (title, url, notes, tags);
+ALTER TABLE search_documents DROP COLUMN content;
@@ -0,0 +1,9 @@
+-- search_content (mysql, up)
+-- The article extracted from each page is searched too. The text already
+-- extracted is copied over before the FULLTEXT index is rebuilt with it.
+ALTER TABLE search_documents ADD COLUMN content mediumtext;
+UPDATE search_documents
+	JOIN bookmark_contents ON bookmark_contents.bookmark_id = search_documents.bookmark_id
+	SET search_documents.content = bookmark_contents.text;
+ALTER TABLE search_documents DROP INDEX idx_search_documents_fulltext;
+CREATE FULLTEXT INDEX idx_search_documents_fulltext ON search_documents (title, url, notes, tags, content);
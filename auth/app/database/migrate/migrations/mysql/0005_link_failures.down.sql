@@ -0,0 +1,2 @@
+-- link_failures (mysql, down)
+ALTER TABLE bookmarks DROP COLUMN link_failures;
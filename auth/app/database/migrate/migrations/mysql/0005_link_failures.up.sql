@@ -0,0 +1,2 @@
+-- link_failures (mysql, up)
+ALTER TABLE bookmarks ADD COLUMN link_failures bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- feed_changes (mysql, up)
+ALTER TABLE feeds ADD COLUMN etag varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE feeds ADD COLUMN changed_at datetime(3) NULL;
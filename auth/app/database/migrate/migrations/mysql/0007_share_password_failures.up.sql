@@ -0,0 +1,3 @@
+-- share_password_failures (mysql, up)
+ALTER TABLE shares ADD COLUMN password_failures int NOT NULL DEFAULT 0;
+ALTER TABLE shares ADD COLUMN locked_until datetime(3) NULL;
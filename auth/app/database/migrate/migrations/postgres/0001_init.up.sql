@@ -0,0 +1,183 @@
+-- The schema as AutoMigrate used to leave it. IF NOT EXISTS lets this run
+-- over a database it created.
+
+CREATE TABLE IF NOT EXISTS users (
+	id bigserial,
+	username text,
+	email text,
+	password text,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+	id bigserial,
+	user_id bigint,
+	url text,
+	title text,
+	notes text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	normalized_url text,
+	url_hash varchar(64),
+	canonical_hash varchar(64),
+	description text,
+	canonical_url text,
+	image_url text,
+	favicon_url text,
+	site_name text,
+	fetched_at timestamptz,
+	fetch_error text,
+	word_count bigint,
+	reading_time bigint,
+	status varchar(16) DEFAULT 'unread',
+	favorite boolean,
+	priority bigint,
+	progress bigint,
+	read_at timestamptz,
+	archived_at timestamptz,
+	favorited_at timestamptz,
+	collection_id bigint,
+	public boolean,
+	link_status varchar(16),
+	link_code bigint,
+	redirect_url text,
+	link_error text,
+	link_checked_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_user_id ON bookmarks (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmarks_user_url_hash ON bookmarks (user_id, url_hash);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_canonical_hash ON bookmarks (canonical_hash);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_status ON bookmarks (status);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_collection_id ON bookmarks (collection_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_public ON bookmarks (public);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_link_status ON bookmarks (link_status);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_link_checked_at ON bookmarks (link_checked_at);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id bigserial,
+	user_id bigint,
+	name varchar(100),
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags (user_id, name);
+
+CREATE TABLE IF NOT EXISTS bookmark_tags (
+	bookmark_id bigint,
+	tag_id bigint,
+	PRIMARY KEY (bookmark_id, tag_id),
+	CONSTRAINT fk_bookmark_tags_bookmark FOREIGN KEY (bookmark_id) REFERENCES bookmarks (id),
+	CONSTRAINT fk_bookmark_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+	id bigserial,
+	user_id bigint,
+	parent_id bigint,
+	name varchar(100),
+	path varchar(255),
+	position bigint,
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_collections_user_path ON collections (user_id, path);
+CREATE INDEX IF NOT EXISTS idx_collections_parent_id ON collections (parent_id);
+
+CREATE TABLE IF NOT EXISTS shares (
+	id bigserial,
+	user_id bigint,
+	token varchar(64),
+	bookmark_id bigint,
+	collection_id bigint,
+	protected boolean,
+	password_salt varchar(32),
+	password_hash varchar(64),
+	expires_at timestamptz,
+	views bigint,
+	last_viewed_at timestamptz,
+	revoked_at timestamptz,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_shares_user_id ON shares (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_token ON shares (token);
+CREATE INDEX IF NOT EXISTS idx_shares_bookmark_id ON shares (bookmark_id);
+CREATE INDEX IF NOT EXISTS idx_shares_collection_id ON shares (collection_id);
+
+CREATE TABLE IF NOT EXISTS bookmark_contents (
+	bookmark_id bigint,
+	user_id bigint,
+	html text,
+	text text,
+	word_count bigint,
+	reading_time bigint,
+	extracted_at timestamptz,
+	PRIMARY KEY (bookmark_id)
+);
+CREATE INDEX IF NOT EXISTS idx_bookmark_contents_user_id ON bookmark_contents (user_id);
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+	id bigserial,
+	user_id bigint,
+	format varchar(20),
+	folder_mode varchar(20),
+	status varchar(20),
+	total bigint,
+	processed bigint,
+	imported bigint,
+	duplicates bigint,
+	failed bigint,
+	error text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	finished_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_user_id ON import_jobs (user_id);
+
+CREATE TABLE IF NOT EXISTS import_errors (
+	id bigserial,
+	job_id bigint,
+	line bigint,
+	url text,
+	error text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_import_jobs_errors FOREIGN KEY (job_id) REFERENCES import_jobs (id)
+);
+CREATE INDEX IF NOT EXISTS idx_import_errors_job_id ON import_errors (job_id);
+
+CREATE TABLE IF NOT EXISTS highlights (
+	id bigserial,
+	user_id bigint,
+	bookmark_id bigint,
+	start_offset bigint,
+	end_offset bigint,
+	quote text,
+	prefix varchar(255),
+	suffix varchar(255),
+	note text,
+	color varchar(16),
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_highlights_user_id ON highlights (user_id);
+CREATE INDEX IF NOT EXISTS idx_highlights_bookmark_id ON highlights (bookmark_id);
+
+CREATE TABLE IF NOT EXISTS feeds (
+	id bigserial,
+	user_id bigint,
+	token varchar(64),
+	tag_id bigint,
+	collection_id bigint,
+	revoked_at timestamptz,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_feeds_user_id ON feeds (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feeds_token ON feeds (token);
+CREATE INDEX IF NOT EXISTS idx_feeds_tag_id ON feeds (tag_id);
+CREATE INDEX IF NOT EXISTS idx_feeds_collection_id ON feeds (collection_id);
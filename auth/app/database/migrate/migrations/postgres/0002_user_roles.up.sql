@@ -0,0 +1,4 @@
+-- user_roles (postgres, up)
+ALTER TABLE users
+	ADD COLUMN role varchar(32) NOT NULL DEFAULT 'user',
+	ADD COLUMN disabled boolean NOT NULL DEFAULT false;
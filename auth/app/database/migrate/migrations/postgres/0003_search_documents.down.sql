@@ -0,0 +1,2 @@
+-- search_documents (postgres, down)
+DROP TABLE IF EXISTS search_documents;
@@ -0,0 +1,22 @@
+-- search_documents (postgres, up)
+-- document is what the GIN index searches: the title weighs most, then the
+-- tags, the words of the URL and the notes. The simple configuration keeps
+-- words as they are, without stemming or stop words, like the other
+-- indexes.
+CREATE TABLE IF NOT EXISTS search_documents (
+	bookmark_id bigint,
+	user_id bigint,
+	title text,
+	url text,
+	notes text,
+	tags text,
+	document tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(tags, '')), 'B') ||
+		setweight(to_tsvector('simple', regexp_replace(coalesce(url, ''), '[^[:alnum:]]+', ' ', 'g')), 'C') ||
+		setweight(to_tsvector('simple', coalesce(notes, '')), 'D')
+	) STORED,
+	PRIMARY KEY (bookmark_id)
+);
+CREATE INDEX IF NOT EXISTS idx_search_documents_user_id ON search_documents (user_id);
+CREATE INDEX IF NOT EXISTS idx_search_documents_document ON search_documents USING GIN (document);
@@ -0,0 +1,10 @@
+-- search_content (postgres, down)
+ALTER TABLE search_documents DROP COLUMN document;
+ALTER TABLE search_documents DROP COLUMN content;
+ALTER TABLE search_documents ADD COLUMN document tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(tags, '')), 'B') ||
+	setweight(to_tsvector('simple', regexp_replace(coalesce(url, ''), '[^[:alnum:]]+', ' ', 'g')), 'C') ||
+	setweight(to_tsvector('simple', coalesce(notes, '')), 'D')
+) STORED;
+CREATE INDEX idx_search_documents_document ON search_documents USING GIN (document);
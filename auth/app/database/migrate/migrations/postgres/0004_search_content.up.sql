@@ -0,0 +1,17 @@
+-- search_content (postgres, up)
+-- The article extracted from each page is searched too, weighing as much
+-- as the notes. The text already extracted is copied over before document
+-- is generated again with it, GIN index included.
+ALTER TABLE search_documents DROP COLUMN document;
+ALTER TABLE search_documents ADD COLUMN content text;
+UPDATE search_documents SET content = bookmark_contents.text
+	FROM bookmark_contents
+	WHERE bookmark_contents.bookmark_id = search_documents.bookmark_id;
+ALTER TABLE search_documents ADD COLUMN document tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(tags, '')), 'B') ||
+	setweight(to_tsvector('simple', regexp_replace(coalesce(url, ''), '[^[:alnum:]]+', ' ', 'g')), 'C') ||
+	setweight(to_tsvector('simple', coalesce(notes, '')), 'D') ||
+	setweight(to_tsvector('simple', coalesce(content, '')), 'D')
+) STORED;
+CREATE INDEX idx_search_documents_document ON search_documents USING GIN (document);
@@ -0,0 +1,2 @@
+-- link_failures (postgres, down)
+ALTER TABLE bookmarks DROP COLUMN link_failures;
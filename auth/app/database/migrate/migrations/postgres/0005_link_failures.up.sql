@@ -0,0 +1,2 @@
+-- link_failures (postgres, up)
+ALTER TABLE bookmarks ADD COLUMN link_failures bigint NOT NULL DEFAULT 0;
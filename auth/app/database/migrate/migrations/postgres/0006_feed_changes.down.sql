@@ -0,0 +1,3 @@
+-- feed_changes (postgres, down)
+ALTER TABLE feeds DROP COLUMN changed_at;
+ALTER TABLE feeds DROP COLUMN etag;
@@ -0,0 +1,3 @@
+-- share_password_failures (postgres, up)
+ALTER TABLE shares ADD COLUMN password_failures integer NOT NULL DEFAULT 0;
+ALTER TABLE shares ADD COLUMN locked_until timestamptz;
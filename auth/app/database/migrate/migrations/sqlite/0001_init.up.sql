@@ -0,0 +1,183 @@
+-- The schema as AutoMigrate used to leave it. IF NOT EXISTS lets this run
+-- over a database it created.
+
+CREATE TABLE IF NOT EXISTS users (
+	id integer,
+	username text,
+	email text,
+	password text,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+	id integer,
+	user_id integer,
+	url text,
+	title text,
+	notes text,
+	created_at datetime,
+	updated_at datetime,
+	normalized_url text,
+	url_hash text,
+	canonical_hash text,
+	description text,
+	canonical_url text,
+	image_url text,
+	favicon_url text,
+	site_name text,
+	fetched_at datetime,
+	fetch_error text,
+	word_count integer,
+	reading_time integer,
+	status text DEFAULT 'unread',
+	favorite numeric,
+	priority integer,
+	progress integer,
+	read_at datetime,
+	archived_at datetime,
+	favorited_at datetime,
+	collection_id integer,
+	public numeric,
+	link_status text,
+	link_code integer,
+	redirect_url text,
+	link_error text,
+	link_checked_at datetime,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_user_id ON bookmarks (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmarks_user_url_hash ON bookmarks (user_id, url_hash);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_canonical_hash ON bookmarks (canonical_hash);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_status ON bookmarks (status);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_collection_id ON bookmarks (collection_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_public ON bookmarks (public);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_link_status ON bookmarks (link_status);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_link_checked_at ON bookmarks (link_checked_at);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id integer,
+	user_id integer,
+	name text,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags (user_id, name);
+
+CREATE TABLE IF NOT EXISTS bookmark_tags (
+	bookmark_id integer,
+	tag_id integer,
+	PRIMARY KEY (bookmark_id, tag_id),
+	CONSTRAINT fk_bookmark_tags_bookmark FOREIGN KEY (bookmark_id) REFERENCES bookmarks (id),
+	CONSTRAINT fk_bookmark_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+	id integer,
+	user_id integer,
+	parent_id integer,
+	name text,
+	path text,
+	position integer,
+	created_at datetime,
+	updated_at datetime,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_collections_user_path ON collections (user_id, path);
+CREATE INDEX IF NOT EXISTS idx_collections_parent_id ON collections (parent_id);
+
+CREATE TABLE IF NOT EXISTS shares (
+	id integer,
+	user_id integer,
+	token text,
+	bookmark_id integer,
+	collection_id integer,
+	protected numeric,
+	password_salt text,
+	password_hash text,
+	expires_at datetime,
+	views integer,
+	last_viewed_at datetime,
+	revoked_at datetime,
+	created_at datetime,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_shares_user_id ON shares (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_token ON shares (token);
+CREATE INDEX IF NOT EXISTS idx_shares_bookmark_id ON shares (bookmark_id);
+CREATE INDEX IF NOT EXISTS idx_shares_collection_id ON shares (collection_id);
+
+CREATE TABLE IF NOT EXISTS bookmark_contents (
+	bookmark_id integer,
+	user_id integer,
+	html text,
+	text text,
+	word_count integer,
+	reading_time integer,
+	extracted_at datetime,
+	PRIMARY KEY (bookmark_id)
+);
+CREATE INDEX IF NOT EXISTS idx_bookmark_contents_user_id ON bookmark_contents (user_id);
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+	id integer,
+	user_id integer,
+	format text,
+	folder_mode text,
+	status text,
+	total integer,
+	processed integer,
+	imported integer,
+	duplicates integer,
+	failed integer,
+	error text,
+	created_at datetime,
+	updated_at datetime,
+	finished_at datetime,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_user_id ON import_jobs (user_id);
+
+CREATE TABLE IF NOT EXISTS import_errors (
+	id integer,
+	job_id integer,
+	line integer,
+	url text,
+	error text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_import_jobs_errors FOREIGN KEY (job_id) REFERENCES import_jobs (id)
+);
+CREATE INDEX IF NOT EXISTS idx_import_errors_job_id ON import_errors (job_id);
+
+CREATE TABLE IF NOT EXISTS highlights (
+	id integer,
+	user_id integer,
+	bookmark_id integer,
+	start_offset integer,
+	end_offset integer,
+	quote text,
+	prefix text,
+	suffix text,
+	note text,
+	color text,
+	created_at datetime,
+	updated_at datetime,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_highlights_user_id ON highlights (user_id);
+CREATE INDEX IF NOT EXISTS idx_highlights_bookmark_id ON highlights (bookmark_id);
+
+CREATE TABLE IF NOT EXISTS feeds (
+	id integer,
+	user_id integer,
+	token text,
+	tag_id integer,
+	collection_id integer,
+	revoked_at datetime,
+	created_at datetime,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_feeds_user_id ON feeds (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feeds_token ON feeds (token);
+CREATE INDEX IF NOT EXISTS idx_feeds_tag_id ON feeds (tag_id);
+CREATE INDEX IF NOT EXISTS idx_feeds_collection_id ON feeds (collection_id);
@@ -0,0 +1,3 @@
+-- user_roles (sqlite, down)
+ALTER TABLE users DROP COLUMN disabled;
+ALTER TABLE users DROP COLUMN role;
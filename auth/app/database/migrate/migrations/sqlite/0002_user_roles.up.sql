@@ -0,0 +1,3 @@
+-- user_roles (sqlite, up)
+ALTER TABLE users ADD COLUMN role text NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN disabled numeric NOT NULL DEFAULT false;
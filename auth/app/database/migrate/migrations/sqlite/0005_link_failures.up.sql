@@ -0,0 +1,2 @@
+-- link_failures (sqlite, up)
+ALTER TABLE bookmarks ADD COLUMN link_failures integer NOT NULL DEFAULT 0;
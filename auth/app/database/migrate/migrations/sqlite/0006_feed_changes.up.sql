@@ -0,0 +1,3 @@
+-- feed_changes (sqlite, up)
+ALTER TABLE feeds ADD COLUMN etag text NOT NULL DEFAULT '';
+ALTER TABLE feeds ADD COLUMN changed_at datetime;
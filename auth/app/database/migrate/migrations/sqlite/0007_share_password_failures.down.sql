@@ -0,0 +1,3 @@
+-- share_password_failures (sqlite, down)
+ALTER TABLE shares DROP COLUMN locked_until;
+ALTER TABLE shares DROP COLUMN password_failures;
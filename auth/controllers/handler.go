@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,11 +13,13 @@ import (
 
 type Handler struct {
 	useCase services.UseCase
+	log     *slog.Logger
 }
 
-func NewHandler(useCase services.UseCase) *Handler {
+func NewHandler(useCase services.UseCase, log *slog.Logger) *Handler {
 	return &Handler{
 		useCase: useCase,
+		log:     log,
 	}
 }
 
@@ -28,7 +31,12 @@ func (h *Handler) SignUp(c *gin.Context) {
 		return
 	}
 
-	if err := h.useCase.SignUp(*inp); err != nil {
+	if err := h.useCase.SignUp(c.Request.Context(), *inp); err != nil {
+		switch err {
+		case auth.ErrDataTidakLengkap, auth.ErrUserDuplicate, auth.ErrEmailDuplicate:
+		default:
+			h.log.ErrorContext(c.Request.Context(), "sign up failed", "username", inp.Username, "err", err)
+		}
 		c.JSON(http.StatusInternalServerError, models.SignResponse{Message: err.Error()})
 		return
 	}
@@ -44,12 +52,17 @@ func (h *Handler) SignIn(c *gin.Context) {
 		return
 	}
 
-	token, err := h.useCase.SignIn(*inp)
+	token, err := h.useCase.SignIn(c.Request.Context(), *inp)
 	if err != nil {
 		if err == auth.ErrUserNotFound {
 			c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUserNotFound.Error()})
 			return
 		}
+		if err == auth.ErrUserDisabled {
+			c.JSON(http.StatusForbidden, models.SignResponse{Message: auth.ErrUserDisabled.Error()})
+			return
+		}
+		h.log.ErrorContext(c.Request.Context(), "sign in failed", "username", inp.Username, "err", err)
 		c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnknown.Error()})
 		return
 	}
@@ -65,12 +78,13 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	err := h.useCase.ChangePassword(*inp)
+	err := h.useCase.ChangePassword(c.Request.Context(), *inp)
 	if err != nil {
 		if err == gorm.ErrInvalidTransaction {
 			c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrInvalidCreds.Error()})
 			return
 		}
+		h.log.ErrorContext(c.Request.Context(), "password change failed", "username", inp.Username, "err", err)
 		c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnknown.Error()})
 		return
 	}
@@ -86,15 +100,36 @@ func (h *Handler) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	err := h.useCase.DeleteAccount(*inp)
+	err := h.useCase.DeleteAccount(c.Request.Context(), *inp)
 	if err != nil {
 		if err.Error() == "record not found" {
 			c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUserNotFound.Error()})
 			return
 		}
+		h.log.ErrorContext(c.Request.Context(), "account deletion failed", "username", inp.Username, "err", err)
 		c.JSON(http.StatusUnauthorized, models.SignResponse{Message: err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SignResponse{Message: "Akun berhasil dihapus"})
 }
+
+func (h *Handler) Introspect(c *gin.Context) {
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, models.IntrospectErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	resp, err := h.useCase.IntrospectToken(c.Request.Context(), token)
+	if err != nil {
+		h.log.ErrorContext(c.Request.Context(), "introspection failed", "err", err)
+		c.JSON(http.StatusInternalServerError, models.IntrospectErrorResponse{Error: "server_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -6,12 +6,14 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/khuchuz/go-clean-architecture-sql/auth"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/services/usecase/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
@@ -21,7 +23,7 @@ func TestSignUp_Success_200(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	signUpBody := &models.SignUpInput{
 		Username: "testuser",
@@ -46,7 +48,7 @@ func TestSignUp_Failed_400(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	body, err := json.Marshal("not json")
 	assert.NoError(t, err)
@@ -62,7 +64,7 @@ func TestSignUp_Failed_500(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	signUpBody := &models.SignUpInput{
 		Username: "testuser",
@@ -86,7 +88,7 @@ func TestSignIn_Sucess_200(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	signInBody := &models.SignInput{
 		Username: "testuser",
@@ -111,7 +113,7 @@ func TestSignIn_Failed_400(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	body, err := json.Marshal("not json")
 	assert.NoError(t, err)
@@ -127,7 +129,7 @@ func TestSignIn_ErrUserNotFound(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	signInBody := &models.SignInput{
 		Username: "testuser",
@@ -147,12 +149,37 @@ func TestSignIn_ErrUserNotFound(t *testing.T) {
 	assert.Equal(t, "{\"message\":\"user not found\"}", w.Body.String())
 }
 
+func TestSignIn_ErrUserDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	uc := new(mock.AuthUseCaseMock)
+
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
+
+	signInBody := &models.SignInput{
+		Username: "testuser",
+		Password: "testpass",
+	}
+
+	body, err := json.Marshal(signInBody)
+	assert.NoError(t, err)
+
+	uc.On("SignIn", signInBody.Username, signInBody.Password).Return("", auth.ErrUserDisabled)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/sign-in", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, "{\"message\":\"user disabled\"}", w.Body.String())
+}
+
 func TestSignIn_ErrUnknown(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	signInBody := &models.SignInput{
 		Username: "testuser",
@@ -177,7 +204,7 @@ func TestChangePassword_ErrInvalidCreds(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	changePassBody := &models.ChangePasswordInput{
 		Username:    "testuser",
@@ -203,7 +230,7 @@ func TestChangePassword_ErrUnknown(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	changePassBody := &models.ChangePasswordInput{
 		Username:    "testuser",
@@ -229,7 +256,7 @@ func TestChangePassword_Success(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	changePassBody := &models.ChangePasswordInput{
 		Username:    "testuser",
@@ -255,7 +282,7 @@ func TestChangePassword_Failed_400(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	body, err := json.Marshal("not json")
 	assert.NoError(t, err)
@@ -271,7 +298,7 @@ func TestDeleteUser_Sucess_200(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	deleteMeBody := &models.DeleteInput{
 		Username: "testuser",
@@ -296,7 +323,7 @@ func TestDeleteUser_Failed_400(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	body, err := json.Marshal("not json")
 	assert.NoError(t, err)
@@ -312,7 +339,7 @@ func TestDeleteUser_ErrUserNotFound(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	deleteMeBody := &models.DeleteInput{
 		Username: "testuser",
@@ -337,7 +364,7 @@ func TestDeleteUser_ErrUnknown(t *testing.T) {
 	r := gin.Default()
 	uc := new(mock.AuthUseCaseMock)
 
-	RegisterHTTPEndpoints(r, uc)
+	RegisterHTTPEndpoints(r, uc, logging.Discard())
 
 	deleteMeBody := &models.DeleteInput{
 		Username: "testuser",
@@ -356,3 +383,75 @@ func TestDeleteUser_ErrUnknown(t *testing.T) {
 	assert.Equal(t, 401, w.Code)
 	assert.Equal(t, "{\"message\":\"unknown error\"}", w.Body.String())
 }
+
+func TestIntrospect_Success_200(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	uc := new(mock.AuthUseCaseMock)
+
+	RegisterIntrospectionEndpoint(r, uc, map[string]string{"resource": "secret"}, logging.Discard())
+
+	uc.On("IntrospectToken", "jwt").Return(&models.IntrospectResponse{Active: true, Sub: "testuser", Exp: 100}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/introspect", strings.NewReader("token=jwt&token_type_hint=access_token"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("resource", "secret")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "{\"active\":true,\"exp\":100,\"sub\":\"testuser\"}", w.Body.String())
+}
+
+func TestIntrospect_Failed_500(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	uc := new(mock.AuthUseCaseMock)
+
+	RegisterIntrospectionEndpoint(r, uc, map[string]string{"resource": "secret"}, logging.Discard())
+
+	uc.On("IntrospectToken", "jwt").Return((*models.IntrospectResponse)(nil), errors.New("connection refused"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/introspect", strings.NewReader("token=jwt"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("resource", "secret")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, "{\"error\":\"server_error\"}", w.Body.String())
+}
+
+func TestIntrospect_Failed_400(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	uc := new(mock.AuthUseCaseMock)
+
+	RegisterIntrospectionEndpoint(r, uc, map[string]string{"resource": "secret"}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/introspect", strings.NewReader("client_id=resource&client_secret=secret"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "{\"error\":\"invalid_request\"}", w.Body.String())
+}
+
+func TestIntrospect_Failed_401(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	uc := new(mock.AuthUseCaseMock)
+
+	RegisterIntrospectionEndpoint(r, uc, map[string]string{"resource": "secret"}, logging.Discard())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/introspect", strings.NewReader("token=jwt&client_id=resource&client_secret=salah"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, "{\"error\":\"invalid_client\"}", w.Body.String())
+	uc.AssertNotCalled(t, "IntrospectToken", "jwt")
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -23,31 +24,57 @@ func NewAuthMiddleware(usecase services.UseCase) gin.HandlerFunc {
 func (m *AuthMiddleware) Handle(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnauthorized.Error()})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnauthorized.Error()})
 		return
 	}
 
 	headerParts := strings.Split(authHeader, " ")
 	if len(headerParts) != 2 {
-		c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnauthorized.Error()})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnauthorized.Error()})
 		return
 	}
 
 	if headerParts[0] != "Bearer" {
-		c.JSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnauthorized.Error()})
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.SignResponse{Message: auth.ErrUnauthorized.Error()})
 		return
 	}
 
-	user, err := m.usecase.ParseToken(headerParts[1])
+	user, err := m.usecase.ParseToken(c.Request.Context(), headerParts[1])
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err == auth.ErrInvalidAccessToken {
 			status = http.StatusUnauthorized
 		}
 
-		c.JSON(status, models.SignResponse{Message: auth.ErrUnknown.Error()})
+		c.AbortWithStatusJSON(status, models.SignResponse{Message: auth.ErrUnknown.Error()})
 		return
 	}
 
 	c.Set(services.CtxUserKey, user)
 }
+
+type ClientAuthMiddleware struct {
+	clients map[string]string
+}
+
+// NewClientAuthMiddleware authenticates OAuth clients with either HTTP Basic
+// (client_secret_basic) or form parameters (client_secret_post).
+func NewClientAuthMiddleware(clients map[string]string) gin.HandlerFunc {
+	return (&ClientAuthMiddleware{
+		clients: clients,
+	}).Handle
+}
+
+func (m *ClientAuthMiddleware) Handle(c *gin.Context) {
+	clientID, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, secret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+
+	expected, found := m.clients[clientID]
+	if clientID == "" || !found || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		c.Header("WWW-Authenticate", `Basic realm="introspect"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.IntrospectErrorResponse{Error: auth.ErrInvalidClient.Error()})
+		return
+	}
+}
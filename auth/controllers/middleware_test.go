@@ -116,3 +116,24 @@ func Test_Middleware_Unauthorized5(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func Test_Middleware_Unauthorized_Aborts(t *testing.T) {
+	r := gin.Default()
+	uc := new(mock.AuthUseCaseMock)
+
+	called := false
+	r.POST("/api/endpoint", NewAuthMiddleware(uc), func(c *gin.Context) {
+		called = true
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/endpoint", nil)
+
+	// Invalid token must not reach the protected handler
+	uc.On("ParseToken", "token").Return(&models.User{}, auth.ErrInvalidAccessToken)
+	req.Header.Set("Authorization", "Bearer token")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.False(t, called)
+}
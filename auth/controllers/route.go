@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/services"
 )
 
-func RegisterHTTPEndpoints(router *gin.Engine, uc services.UseCase) {
-	h := NewHandler(uc)
+func RegisterHTTPEndpoints(router *gin.Engine, uc services.UseCase, log *slog.Logger) {
+	h := NewHandler(uc, log)
 
 	authEndpoints := router.Group("/auth")
 	{
@@ -16,3 +18,9 @@ func RegisterHTTPEndpoints(router *gin.Engine, uc services.UseCase) {
 		authEndpoints.POST("/delete-me", h.DeleteAccount)
 	}
 }
+
+func RegisterIntrospectionEndpoint(router *gin.Engine, uc services.UseCase, clients map[string]string, log *slog.Logger) {
+	h := NewHandler(uc, log)
+
+	router.POST("/auth/introspect", NewClientAuthMiddleware(clients), h.Introspect)
+}
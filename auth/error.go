@@ -13,4 +13,7 @@ var (
 	ErrDataTidakLengkap   = errors.New("data tidak lengkap")
 	ErrPasswordSame       = errors.New("password baru tidak boleh sama dengan password lama")
 	ErrInvalidCreds       = errors.New("invalid credentials")
+	ErrInvalidClient      = errors.New("invalid_client")
+	ErrUserDisabled       = errors.New("user disabled")
+	ErrInvalidRole        = errors.New("invalid role")
 )
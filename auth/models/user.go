@@ -1,10 +1,28 @@
 package models
 
+// Roles a user can have. Everyone who signs up is a RoleUser; admins are
+// made from the command line.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID       uint   `gorm:"primaryKey"`
 	Username string `gorm:"uniqueIndex"`
 	Email    string `gorm:"uniqueIndex"`
 	Password string
+	Role     string `gorm:"size:32"`
+	Disabled bool
+}
+
+// CreateUserInput is an account made by an operator rather than by signing
+// up. An empty Role is RoleUser.
+type CreateUserInput struct {
+	Username string
+	Email    string
+	Password string
+	Role     string
 }
 
 type Register struct {
@@ -42,3 +60,17 @@ type SignResponse struct {
 type SignInResponse struct {
 	Token string `json:"token"`
 }
+
+type IntrospectResponse struct {
+	Active    bool   `json:"active"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Revoked   bool   `json:"revoked,omitempty"`
+}
+
+type IntrospectErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -1,14 +1,18 @@
 package services
 
 import (
+	"context"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 )
 
 type UserRepositorySQL interface {
-	SQLCreateUser(user *models.User) error
-	SQLGetUser(username, password string) (*models.User, error)
-	SQLIsUserExistByUsername(username string) bool
-	SQLIsUserExistByEmail(email string) bool
-	SQLUpdatePassword(username, oldpassword, password string) error
-	SQLDeleteUser(username, password string) error
+	SQLCreateUser(ctx context.Context, user *models.User) error
+	SQLGetUser(ctx context.Context, username, password string) (*models.User, error)
+	SQLIsUserExistByUsername(ctx context.Context, username string) bool
+	SQLIsUserExistByEmail(ctx context.Context, email string) bool
+	SQLUpdatePassword(ctx context.Context, username, oldpassword, password string) error
+	SQLDeleteUser(ctx context.Context, username, password string) error
+	SQLGetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	SQLListUsers(ctx context.Context) ([]models.User, error)
+	SQLSaveUser(ctx context.Context, user *models.User) error
 }
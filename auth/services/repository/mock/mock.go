@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,38 +11,56 @@ type UserStorageMock struct {
 	mock.Mock
 }
 
-func (s *UserStorageMock) SQLCreateUser(user *models.User) error {
+func (s *UserStorageMock) SQLCreateUser(ctx context.Context, user *models.User) error {
 	args := s.Called(user)
 
 	return args.Error(0)
 }
 
-func (s *UserStorageMock) SQLGetUser(username, password string) (*models.User, error) {
+func (s *UserStorageMock) SQLGetUser(ctx context.Context, username, password string) (*models.User, error) {
 	args := s.Called(username, password)
 
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (s *UserStorageMock) SQLUpdatePassword(username, oldpassword, password string) error {
+func (s *UserStorageMock) SQLUpdatePassword(ctx context.Context, username, oldpassword, password string) error {
 	args := s.Called(username, oldpassword, password)
 
 	return args.Error(0)
 }
 
-func (s *UserStorageMock) SQLIsUserExistByUsername(username string) bool {
+func (s *UserStorageMock) SQLIsUserExistByUsername(ctx context.Context, username string) bool {
 	args := s.Called(username)
 
 	return args.Bool(0)
 }
 
-func (s *UserStorageMock) SQLIsUserExistByEmail(email string) bool {
+func (s *UserStorageMock) SQLIsUserExistByEmail(ctx context.Context, email string) bool {
 	args := s.Called(email)
 
 	return args.Bool(0)
 }
 
-func (s *UserStorageMock) SQLDeleteUser(username, password string) error {
+func (s *UserStorageMock) SQLDeleteUser(ctx context.Context, username, password string) error {
 	args := s.Called(username, password)
 
 	return args.Error(0)
 }
+
+func (s *UserStorageMock) SQLGetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	args := s.Called(username)
+
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (s *UserStorageMock) SQLListUsers(ctx context.Context) ([]models.User, error) {
+	args := s.Called()
+
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (s *UserStorageMock) SQLSaveUser(ctx context.Context, user *models.User) error {
+	args := s.Called(user)
+
+	return args.Error(0)
+}
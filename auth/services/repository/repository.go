@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 	"gorm.io/gorm"
 )
@@ -13,8 +15,8 @@ func InitUserRepositorySQL(db *gorm.DB) *UserRepositorySQL {
 	return &UserRepositorySQL{DB: db}
 }
 
-func (r *UserRepositorySQL) SQLCreateUser(user *models.User) error {
-	tx := r.DB.Begin()
+func (r *UserRepositorySQL) SQLCreateUser(ctx context.Context, user *models.User) error {
+	tx := r.DB.WithContext(ctx).Begin()
 
 	if err := tx.Error; err != nil {
 		return err
@@ -28,14 +30,14 @@ func (r *UserRepositorySQL) SQLCreateUser(user *models.User) error {
 	return tx.Commit().Error
 }
 
-func (r *UserRepositorySQL) SQLGetUser(username, password string) (*models.User, error) {
+func (r *UserRepositorySQL) SQLGetUser(ctx context.Context, username, password string) (*models.User, error) {
 	user := new(models.User)
-	err := r.DB.Where("username = ?", username).Where("password = ?", password).First(&user).Error
+	err := r.DB.WithContext(ctx).Where("username = ?", username).Where("password = ?", password).First(&user).Error
 	return user, err
 }
 
-func (r *UserRepositorySQL) SQLUpdatePassword(username, oldpassword, password string) error {
-	tx := r.DB.Begin()
+func (r *UserRepositorySQL) SQLUpdatePassword(ctx context.Context, username, oldpassword, password string) error {
+	tx := r.DB.WithContext(ctx).Begin()
 
 	if err := tx.Error; err != nil {
 		return err
@@ -56,20 +58,20 @@ func (r *UserRepositorySQL) SQLUpdatePassword(username, oldpassword, password st
 	return tx.Commit().Error
 }
 
-func (r *UserRepositorySQL) SQLIsUserExistByUsername(username string) bool {
+func (r *UserRepositorySQL) SQLIsUserExistByUsername(ctx context.Context, username string) bool {
 	user := new(models.User)
-	ret := r.DB.Where("username = ?", username).First(&user)
+	ret := r.DB.WithContext(ctx).Where("username = ?", username).First(&user)
 	return ret.Error == nil
 }
 
-func (r *UserRepositorySQL) SQLIsUserExistByEmail(email string) bool {
+func (r *UserRepositorySQL) SQLIsUserExistByEmail(ctx context.Context, email string) bool {
 	user := new(models.User)
-	ret := r.DB.Where("email = ?", email).First(&user)
+	ret := r.DB.WithContext(ctx).Where("email = ?", email).First(&user)
 	return ret.Error == nil
 }
 
-func (r *UserRepositorySQL) SQLDeleteUser(username, password string) error {
-	tx := r.DB.Begin()
+func (r *UserRepositorySQL) SQLDeleteUser(ctx context.Context, username, password string) error {
+	tx := r.DB.WithContext(ctx).Begin()
 
 	if err := tx.Error; err != nil {
 		return err
@@ -89,3 +91,19 @@ func (r *UserRepositorySQL) SQLDeleteUser(username, password string) error {
 
 	return tx.Commit().Error
 }
+
+func (r *UserRepositorySQL) SQLGetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user := new(models.User)
+	err := r.DB.WithContext(ctx).Where("username = ?", username).First(&user).Error
+	return user, err
+}
+
+func (r *UserRepositorySQL) SQLListUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := r.DB.WithContext(ctx).Order("id").Find(&users).Error
+	return users, err
+}
+
+func (r *UserRepositorySQL) SQLSaveUser(ctx context.Context, user *models.User) error {
+	return r.DB.WithContext(ctx).Save(user).Error
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"crypto/sha1"
 	"database/sql"
 	"errors"
@@ -54,12 +55,12 @@ func (s *Suite) TestSQLCreateUser_Success() {
 	}
 
 	s.mock.ExpectBegin() // start transaction
-	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`username`,`email`,`password`) VALUES (?,?,?)")).
-		WithArgs(user.Username, user.Email, user.Password).
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`username`,`email`,`password`,`role`,`disabled`) VALUES (?,?,?,?,?)")).
+		WithArgs(user.Username, user.Email, user.Password, user.Role, user.Disabled).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	s.mock.ExpectCommit() // commit transaction
 
-	err := s.userRepositorySQL.SQLCreateUser(user)
+	err := s.userRepositorySQL.SQLCreateUser(context.Background(), user)
 	s.NoError(err)
 }
 
@@ -71,12 +72,12 @@ func (s *Suite) TestSQLCreateUser_Failed() {
 	}
 
 	s.mock.ExpectBegin() // start transaction
-	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`username`,`email`,`password`) VALUES (?,?,?)")).
-		WithArgs(user.Username, user.Email, user.Password).
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users` (`username`,`email`,`password`,`role`,`disabled`) VALUES (?,?,?,?,?)")).
+		WithArgs(user.Username, user.Email, user.Password, user.Role, user.Disabled).
 		WillReturnError(errors.New("some error"))
 	s.mock.ExpectRollback() // commit transaction
 
-	err := s.userRepositorySQL.SQLCreateUser(user)
+	err := s.userRepositorySQL.SQLCreateUser(context.Background(), user)
 	s.Error(err)
 }
 
@@ -89,7 +90,7 @@ func (s *Suite) TestSQLCreateUser_Failed_atBegin() {
 
 	s.mock.ExpectBegin().WillReturnError(errors.New("some error"))
 
-	err := s.userRepositorySQL.SQLCreateUser(user)
+	err := s.userRepositorySQL.SQLCreateUser(context.Background(), user)
 	s.Error(err)
 }
 
@@ -106,7 +107,7 @@ func (s *Suite) TestSQLGetUser_Success() {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password"}).
 			AddRow(id, username, email, password))
 
-	res, err := s.userRepositorySQL.SQLGetUser(username, password)
+	res, err := s.userRepositorySQL.SQLGetUser(context.Background(), username, password)
 
 	require.NoError(s.T(), err)
 	require.Nil(s.T(), deep.Equal(&models.User{ID: res.ID, Username: username, Email: email, Password: password}, res))
@@ -120,7 +121,7 @@ func (s *Suite) TestSQLGetUser_Failed_NotExist() {
 
 	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE username = ? AND password = ?")).WithArgs(username, password).WillReturnError(gorm.ErrRecordNotFound)
 
-	res, err := s.userRepositorySQL.SQLGetUser(username, password)
+	res, err := s.userRepositorySQL.SQLGetUser(context.Background(), username, password)
 	if assert.Error(s.T(), err) {
 		assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
 	}
@@ -140,7 +141,7 @@ func (s *Suite) TestSQLIsUserExistByUsername_True() {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password"}).
 			AddRow(id, username, email, password))
 
-	res := s.userRepositorySQL.SQLIsUserExistByUsername(username)
+	res := s.userRepositorySQL.SQLIsUserExistByUsername(context.Background(), username)
 	require.Equal(s.T(), true, res)
 }
 
@@ -149,7 +150,7 @@ func (s *Suite) TestSQLIsUserExistByUsername_False() {
 
 	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE username = ?")).WithArgs(username).WillReturnError(gorm.ErrRecordNotFound)
 
-	res := s.userRepositorySQL.SQLIsUserExistByUsername(username)
+	res := s.userRepositorySQL.SQLIsUserExistByUsername(context.Background(), username)
 	require.Equal(s.T(), false, res)
 }
 
@@ -166,7 +167,7 @@ func (s *Suite) TestSQLIsUserExistByEmail_True() {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password"}).
 			AddRow(id, username, email, password))
 
-	res := s.userRepositorySQL.SQLIsUserExistByEmail(email)
+	res := s.userRepositorySQL.SQLIsUserExistByEmail(context.Background(), email)
 	require.Equal(s.T(), true, res)
 }
 
@@ -175,7 +176,7 @@ func (s *Suite) TestSQLIsUserExistByEmail_False() {
 
 	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE email = ?")).WithArgs(email).WillReturnError(gorm.ErrRecordNotFound)
 
-	res := s.userRepositorySQL.SQLIsUserExistByEmail(email)
+	res := s.userRepositorySQL.SQLIsUserExistByEmail(context.Background(), email)
 	require.Equal(s.T(), false, res)
 }
 
@@ -192,7 +193,7 @@ func (s *Suite) TestSQLUpdatePassword_Success() {
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	s.mock.ExpectCommit() // commit transaction
 
-	err := s.userRepositorySQL.SQLUpdatePassword(username, oldpassword, password)
+	err := s.userRepositorySQL.SQLUpdatePassword(context.Background(), username, oldpassword, password)
 	s.NoError(err)
 }
 
@@ -209,7 +210,7 @@ func (s *Suite) TestSQLUpdatePassword_Failed_MultipleRowAffected() {
 		WillReturnResult(sqlmock.NewResult(1, 2))
 	s.mock.ExpectRollback() // rollback transaction
 
-	err := s.userRepositorySQL.SQLUpdatePassword(username, oldpassword, password)
+	err := s.userRepositorySQL.SQLUpdatePassword(context.Background(), username, oldpassword, password)
 	if s.Error(err) {
 		assert.Equal(s.T(), gorm.ErrInvalidTransaction, err)
 	}
@@ -228,7 +229,7 @@ func (s *Suite) TestSQLUpdatePassword_Failed_ZeroRowAffected() {
 		WillReturnResult(sqlmock.NewResult(1, 0))
 	s.mock.ExpectRollback() // rollback transaction
 
-	err := s.userRepositorySQL.SQLUpdatePassword(username, oldpassword, password)
+	err := s.userRepositorySQL.SQLUpdatePassword(context.Background(), username, oldpassword, password)
 	if s.Error(err) {
 		assert.Equal(s.T(), gorm.ErrInvalidTransaction, err)
 	}
@@ -247,7 +248,7 @@ func (s *Suite) TestSQLUpdatePassword_Failed_ReturnError() {
 		WillReturnError(errors.New("something went wrong"))
 	s.mock.ExpectRollback() // rollback transaction
 
-	err := s.userRepositorySQL.SQLUpdatePassword(username, oldpassword, password)
+	err := s.userRepositorySQL.SQLUpdatePassword(context.Background(), username, oldpassword, password)
 	s.Error(err)
 }
 
@@ -260,7 +261,7 @@ func (s *Suite) TestSQLUpdatePassword_Failed_atBegin() {
 
 	s.mock.ExpectBegin().WillReturnError(errors.New("some error"))
 
-	err := s.userRepositorySQL.SQLUpdatePassword(username, oldpassword, password)
+	err := s.userRepositorySQL.SQLUpdatePassword(context.Background(), username, oldpassword, password)
 	s.Error(err)
 }
 
@@ -275,7 +276,7 @@ func (s *Suite) TestSQLDeleteUser_Success() {
 		WithArgs(username, password).WillReturnResult(sqlmock.NewResult(1, 1))
 	s.mock.ExpectCommit()
 
-	err := s.userRepositorySQL.SQLDeleteUser(username, password)
+	err := s.userRepositorySQL.SQLDeleteUser(context.Background(), username, password)
 
 	require.NoError(s.T(), err)
 }
@@ -288,7 +289,7 @@ func (s *Suite) TestSQLDeleteUser_Failed_atBegin() {
 
 	s.mock.ExpectBegin().WillReturnError(errors.New("some error"))
 
-	err := s.userRepositorySQL.SQLDeleteUser(username, password)
+	err := s.userRepositorySQL.SQLDeleteUser(context.Background(), username, password)
 	s.Error(err)
 }
 
@@ -304,7 +305,7 @@ func (s *Suite) TestSQLDeleteUser_Failed_MultipleRowAffected() {
 		WillReturnResult(sqlmock.NewResult(1, 2))
 	s.mock.ExpectRollback() // rollback transaction
 
-	err := s.userRepositorySQL.SQLDeleteUser(username, password)
+	err := s.userRepositorySQL.SQLDeleteUser(context.Background(), username, password)
 	if s.Error(err) {
 		assert.Equal(s.T(), gorm.ErrInvalidTransaction, err)
 	}
@@ -322,7 +323,7 @@ func (s *Suite) TestSQLDeleteUser_Failed_ZeroRowAffected() {
 		WillReturnResult(sqlmock.NewResult(1, 0))
 	s.mock.ExpectRollback() // rollback transaction
 
-	err := s.userRepositorySQL.SQLDeleteUser(username, password)
+	err := s.userRepositorySQL.SQLDeleteUser(context.Background(), username, password)
 	if s.Error(err) {
 		assert.Equal(s.T(), gorm.ErrInvalidTransaction, err)
 	}
@@ -340,7 +341,7 @@ func (s *Suite) TestSQLDeleteUser_Failed_ReturnError() {
 		WillReturnError(errors.New("some error"))
 	s.mock.ExpectRollback() // rollback transaction
 
-	err := s.userRepositorySQL.SQLDeleteUser(username, password)
+	err := s.userRepositorySQL.SQLDeleteUser(context.Background(), username, password)
 	s.Error(err)
 }
 
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSQLite_Users(t *testing.T) {
+	db, err := database.SetupDatabase(context.Background(), config.DatabaseConfig{Driver: config.DriverSQLite, Name: ":memory:"}, nil, logging.Discard())
+	require.NoError(t, err)
+	repo := InitUserRepositorySQL(db)
+
+	require.NoError(t, repo.SQLCreateUser(context.Background(), &models.User{Username: "khuchuz", Email: "khuchuz@example.com", Password: "hash", Role: models.RoleUser}))
+	assert.True(t, repo.SQLIsUserExistByUsername(context.Background(), "khuchuz"))
+	assert.True(t, repo.SQLIsUserExistByEmail(context.Background(), "khuchuz@example.com"))
+
+	err = repo.SQLCreateUser(context.Background(), &models.User{Username: "other", Email: "khuchuz@example.com", Password: "hash"})
+	assert.True(t, errors.Is(err, dberr.ErrDuplicateKey), err)
+
+	_, err = repo.SQLGetUser(context.Background(), "khuchuz", "wrong")
+	assert.True(t, errors.Is(err, dberr.ErrNotFound))
+
+	require.NoError(t, repo.SQLUpdatePassword(context.Background(), "khuchuz", "hash", "new"))
+	user, err := repo.SQLGetUser(context.Background(), "khuchuz", "new")
+	require.NoError(t, err)
+	assert.Equal(t, "khuchuz@example.com", user.Email)
+
+	user, err = repo.SQLGetUserByUsername(context.Background(), "khuchuz")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleUser, user.Role)
+	user.Role = models.RoleAdmin
+	user.Disabled = true
+	require.NoError(t, repo.SQLSaveUser(context.Background(), user))
+
+	users, err := repo.SQLListUsers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, *user, users[0])
+
+	assert.Equal(t, gorm.ErrInvalidTransaction, repo.SQLDeleteUser(context.Background(), "khuchuz", "hash"))
+	require.NoError(t, repo.SQLDeleteUser(context.Background(), "khuchuz", "new"))
+	assert.False(t, repo.SQLIsUserExistByUsername(context.Background(), "khuchuz"))
+}
+
+func TestSQLite_Users_Canceled(t *testing.T) {
+	db, err := database.SetupDatabase(context.Background(), config.DatabaseConfig{Driver: config.DriverSQLite, Name: ":memory:"}, nil, logging.Discard())
+	require.NoError(t, err)
+	repo := InitUserRepositorySQL(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = repo.SQLGetUserByUsername(ctx, "khuchuz")
+	assert.True(t, errors.Is(err, context.Canceled), err)
+	assert.True(t, errors.Is(repo.SQLCreateUser(ctx, &models.User{Username: "khuchuz", Email: "khuchuz@example.com"}), context.Canceled))
+}
@@ -1,15 +1,30 @@
 package services
 
 import (
+	"context"
+	"time"
+
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 )
 
 const CtxUserKey = "user"
 
 type UseCase interface {
-	SignUp(inp models.SignUpInput) error
-	SignIn(inp models.SignInput) (string, error)
-	ChangePassword(inp models.ChangePasswordInput) error
-	ParseToken(accessToken string) (*models.User, error)
-	DeleteAccount(inp models.DeleteInput) error
+	SignUp(ctx context.Context, inp models.SignUpInput) error
+	SignIn(ctx context.Context, inp models.SignInput) (string, error)
+	ChangePassword(ctx context.Context, inp models.ChangePasswordInput) error
+	ParseToken(ctx context.Context, accessToken string) (*models.User, error)
+	DeleteAccount(ctx context.Context, inp models.DeleteInput) error
+	IntrospectToken(ctx context.Context, token string) (*models.IntrospectResponse, error)
+}
+
+// AdminUseCase manages accounts for operators, without the passwords of
+// their owners.
+type AdminUseCase interface {
+	CreateUser(ctx context.Context, inp models.CreateUserInput) (*models.User, error)
+	ListUsers(ctx context.Context) ([]models.User, error)
+	SetDisabled(ctx context.Context, username string, disabled bool) error
+	ResetPassword(ctx context.Context, username, password string) error
+	SetRole(ctx context.Context, username, role string) error
+	IssueToken(ctx context.Context, username string, ttl time.Duration) (string, error)
 }
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,32 +11,38 @@ type AuthUseCaseMock struct {
 	mock.Mock
 }
 
-func (m *AuthUseCaseMock) SignUp(inp models.SignUpInput) error {
+func (m *AuthUseCaseMock) SignUp(ctx context.Context, inp models.SignUpInput) error {
 	args := m.Called(inp.Username, inp.Email, inp.Password)
 
 	return args.Error(0)
 }
 
-func (m *AuthUseCaseMock) SignIn(inp models.SignInput) (string, error) {
+func (m *AuthUseCaseMock) SignIn(ctx context.Context, inp models.SignInput) (string, error) {
 	args := m.Called(inp.Username, inp.Password)
 
 	return args.Get(0).(string), args.Error(1)
 }
 
-func (m *AuthUseCaseMock) DeleteAccount(inp models.DeleteInput) error {
+func (m *AuthUseCaseMock) DeleteAccount(ctx context.Context, inp models.DeleteInput) error {
 	args := m.Called(inp.Username, inp.Password)
 
 	return args.Error(0)
 }
 
-func (m *AuthUseCaseMock) ChangePassword(inp models.ChangePasswordInput) error {
+func (m *AuthUseCaseMock) ChangePassword(ctx context.Context, inp models.ChangePasswordInput) error {
 	args := m.Called(inp.Username, inp.OldPassword, inp.Password)
 
 	return args.Error(0)
 }
 
-func (m *AuthUseCaseMock) ParseToken(accessToken string) (*models.User, error) {
+func (m *AuthUseCaseMock) ParseToken(ctx context.Context, accessToken string) (*models.User, error) {
 	args := m.Called(accessToken)
 
 	return args.Get(0).(*models.User), args.Error(1)
 }
+
+func (m *AuthUseCaseMock) IntrospectToken(ctx context.Context, token string) (*models.IntrospectResponse, error) {
+	args := m.Called(token)
+
+	return args.Get(0).(*models.IntrospectResponse), args.Error(1)
+}
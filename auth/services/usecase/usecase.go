@@ -1,7 +1,10 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/dgrijalva/jwt-go/v4"
@@ -9,6 +12,7 @@ import (
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/services"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/utils"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
 )
 
 type AuthClaims struct {
@@ -21,57 +25,119 @@ type AuthUseCase struct {
 	hashSalt       string
 	signingKey     []byte
 	expireDuration time.Duration
+	log            *slog.Logger
 }
 
 func NewAuthUseCase(
 	userRepo services.UserRepositorySQL,
 	hashSalt string,
 	signingKey []byte,
-	tokenTTL time.Duration) *AuthUseCase {
+	tokenTTL time.Duration,
+	log *slog.Logger) *AuthUseCase {
 	return &AuthUseCase{
 		userRepo:       userRepo,
 		hashSalt:       hashSalt,
 		signingKey:     signingKey,
 		expireDuration: time.Second * tokenTTL,
+		log:            log,
 	}
 }
 
-func (a *AuthUseCase) SignUp(inp models.SignUpInput) error {
+func (a *AuthUseCase) SignUp(ctx context.Context, inp models.SignUpInput) error {
+	_, err := a.CreateUser(ctx, models.CreateUserInput{
+		Username: inp.Username,
+		Email:    inp.Email,
+		Password: inp.Password,
+	})
+	return err
+}
+
+// CreateUser makes an account as SignUp does, with the role of the input.
+func (a *AuthUseCase) CreateUser(ctx context.Context, inp models.CreateUserInput) (*models.User, error) {
 
 	if inp.Username == "" || inp.Email == "" || inp.Password == "" {
-		return auth.ErrDataTidakLengkap
+		return nil, auth.ErrDataTidakLengkap
 	}
 
-	if a.userRepo.SQLIsUserExistByUsername(inp.Username) {
-		return auth.ErrUserDuplicate
+	if inp.Role == "" {
+		inp.Role = models.RoleUser
+	}
+	if !validRole(inp.Role) {
+		return nil, auth.ErrInvalidRole
+	}
+
+	if a.userRepo.SQLIsUserExistByUsername(ctx, inp.Username) {
+		return nil, auth.ErrUserDuplicate
 	}
 
-	if a.userRepo.SQLIsUserExistByEmail(inp.Email) {
-		return auth.ErrEmailDuplicate
+	if a.userRepo.SQLIsUserExistByEmail(ctx, inp.Email) {
+		return nil, auth.ErrEmailDuplicate
 	}
 
 	user := &models.User{
 		Username: inp.Username,
 		Email:    inp.Email,
 		Password: utils.HashThis(inp.Password, a.hashSalt),
+		Role:     inp.Role,
+	}
+
+	err := a.userRepo.SQLCreateUser(ctx, user)
+	if errors.Is(err, dberr.ErrDuplicateKey) {
+		// Someone signed up with the same name or email since the checks.
+		if a.userRepo.SQLIsUserExistByUsername(ctx, inp.Username) {
+			return nil, auth.ErrUserDuplicate
+		}
+		return nil, auth.ErrEmailDuplicate
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return a.userRepo.SQLCreateUser(user)
+	a.log.InfoContext(ctx, "user created", "username", user.Username, "role", user.Role)
+	return user, nil
 }
 
-func (a *AuthUseCase) SignIn(inp models.SignInput) (string, error) {
+func (a *AuthUseCase) SignIn(ctx context.Context, inp models.SignInput) (string, error) {
 
 	password := utils.HashThis(inp.Password, a.hashSalt)
 
-	user, err := a.userRepo.SQLGetUser(inp.Username, password)
+	user, err := a.userRepo.SQLGetUser(ctx, inp.Username, password)
 	if err != nil {
+		a.log.InfoContext(ctx, "sign in failed", "username", inp.Username)
 		return "", auth.ErrUserNotFound
 	}
+	if user.Disabled {
+		a.log.WarnContext(ctx, "sign in refused to disabled user", "username", user.Username)
+		return "", auth.ErrUserDisabled
+	}
 
+	return a.signToken(user, a.expireDuration)
+}
+
+// IssueToken signs a token for username without its password, lasting ttl,
+// or as long as one from SignIn when ttl is 0.
+func (a *AuthUseCase) IssueToken(ctx context.Context, username string, ttl time.Duration) (string, error) {
+	user, err := a.userRepo.SQLGetUserByUsername(ctx, username)
+	if err != nil {
+		return "", auth.ErrUserNotFound
+	}
+	if user.Disabled {
+		return "", auth.ErrUserDisabled
+	}
+
+	if ttl == 0 {
+		ttl = a.expireDuration
+	}
+
+	a.log.InfoContext(ctx, "token issued", "username", user.Username, "ttl", ttl)
+	return a.signToken(user, ttl)
+}
+
+func (a *AuthUseCase) signToken(user *models.User, ttl time.Duration) (string, error) {
 	claims := AuthClaims{
 		User: user,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: jwt.At(time.Now().Add(a.expireDuration)),
+			ExpiresAt: jwt.At(time.Now().Add(ttl)),
 		},
 	}
 
@@ -80,7 +146,62 @@ func (a *AuthUseCase) SignIn(inp models.SignInput) (string, error) {
 	return token.SignedString(a.signingKey)
 }
 
-func (a *AuthUseCase) ChangePassword(inp models.ChangePasswordInput) error {
+func (a *AuthUseCase) ListUsers(ctx context.Context) ([]models.User, error) {
+	return a.userRepo.SQLListUsers(ctx)
+}
+
+// SetDisabled keeps username from signing in, or lets it again. Tokens it
+// already has stay valid until they expire, but introspection reports them
+// revoked.
+func (a *AuthUseCase) SetDisabled(ctx context.Context, username string, disabled bool) error {
+	return a.updateUser(ctx, username, func(user *models.User) {
+		user.Disabled = disabled
+	})
+}
+
+// ResetPassword sets the password of username without asking for the old
+// one.
+func (a *AuthUseCase) ResetPassword(ctx context.Context, username, password string) error {
+	if password == "" {
+		return auth.ErrDataTidakLengkap
+	}
+	return a.updateUser(ctx, username, func(user *models.User) {
+		user.Password = utils.HashThis(password, a.hashSalt)
+	})
+}
+
+func (a *AuthUseCase) SetRole(ctx context.Context, username, role string) error {
+	if !validRole(role) {
+		return auth.ErrInvalidRole
+	}
+	return a.updateUser(ctx, username, func(user *models.User) {
+		user.Role = role
+	})
+}
+
+func (a *AuthUseCase) updateUser(ctx context.Context, username string, update func(user *models.User)) error {
+	user, err := a.userRepo.SQLGetUserByUsername(ctx, username)
+	if errors.Is(err, dberr.ErrNotFound) {
+		return auth.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	update(user)
+	if err := a.userRepo.SQLSaveUser(ctx, user); err != nil {
+		return err
+	}
+
+	a.log.InfoContext(ctx, "user updated", "username", user.Username, "role", user.Role, "disabled", user.Disabled)
+	return nil
+}
+
+func validRole(role string) bool {
+	return role == models.RoleUser || role == models.RoleAdmin
+}
+
+func (a *AuthUseCase) ChangePassword(ctx context.Context, inp models.ChangePasswordInput) error {
 	if inp.Username == "" || inp.OldPassword == "" || inp.Password == "" {
 		return auth.ErrDataTidakLengkap
 	}
@@ -91,20 +212,91 @@ func (a *AuthUseCase) ChangePassword(inp models.ChangePasswordInput) error {
 	oldpassword := utils.HashThis(inp.OldPassword, a.hashSalt)
 	password := utils.HashThis(inp.Password, a.hashSalt)
 
-	return a.userRepo.SQLUpdatePassword(inp.Username, oldpassword, password)
+	if err := a.userRepo.SQLUpdatePassword(ctx, inp.Username, oldpassword, password); err != nil {
+		return err
+	}
+
+	a.log.InfoContext(ctx, "password changed", "username", inp.Username)
+	return nil
 }
 
-func (a *AuthUseCase) DeleteAccount(inp models.DeleteInput) error {
+func (a *AuthUseCase) DeleteAccount(ctx context.Context, inp models.DeleteInput) error {
 	password := utils.HashThis(inp.Password, a.hashSalt)
 
-	err := a.userRepo.SQLDeleteUser(inp.Username, password)
+	err := a.userRepo.SQLDeleteUser(ctx, inp.Username, password)
 	if err != nil {
 		return err
 	}
+
+	a.log.InfoContext(ctx, "account deleted", "username", inp.Username)
 	return nil
 }
 
-func (a *AuthUseCase) ParseToken(accessToken string) (*models.User, error) {
+// ParseToken returns the account a token was issued to, as it is now.
+// Access tokens are self-contained, so without looking the account up a
+// token would outlive the account being deleted or disabled until it
+// expired.
+func (a *AuthUseCase) ParseToken(ctx context.Context, accessToken string) (*models.User, error) {
+	claims, err := a.parseClaims(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.User == nil {
+		return nil, auth.ErrInvalidAccessToken
+	}
+
+	user, err := a.userRepo.SQLGetUserByUsername(ctx, claims.User.Username)
+	if errors.Is(err, dberr.ErrNotFound) {
+		return nil, auth.ErrInvalidAccessToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, auth.ErrInvalidAccessToken
+	}
+
+	return user, nil
+}
+
+// IntrospectToken reports the state of a token as described by RFC 7662.
+// Tokens that cannot be parsed are reported inactive without further detail.
+// It only fails when the account of a token cannot be looked up.
+func (a *AuthUseCase) IntrospectToken(ctx context.Context, token string) (*models.IntrospectResponse, error) {
+	claims, err := a.parseClaims(token)
+	if err != nil || claims.User == nil {
+		return &models.IntrospectResponse{Active: false}, nil
+	}
+
+	// Access tokens are self-contained, so a token outliving its account, or
+	// the account being disabled, is the only way one gets revoked before it
+	// expires.
+	user, err := a.userRepo.SQLGetUserByUsername(ctx, claims.User.Username)
+	if errors.Is(err, dberr.ErrNotFound) {
+		return &models.IntrospectResponse{Active: false, Revoked: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return &models.IntrospectResponse{Active: false, Revoked: true}, nil
+	}
+
+	resp := &models.IntrospectResponse{
+		Active:    true,
+		Username:  user.Username,
+		Sub:       user.Username,
+		Role:      user.Role,
+		TokenType: "Bearer",
+	}
+	if claims.ExpiresAt != nil {
+		resp.Exp = claims.ExpiresAt.Unix()
+	}
+
+	return resp, nil
+}
+
+func (a *AuthUseCase) parseClaims(accessToken string) (*AuthClaims, error) {
 	token, err := jwt.ParseWithClaims(accessToken, &AuthClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -117,7 +309,7 @@ func (a *AuthUseCase) ParseToken(accessToken string) (*models.User, error) {
 	}
 
 	if claims, ok := token.Claims.(*AuthClaims); ok && token.Valid {
-		return claims.User, nil
+		return claims, nil
 	}
 
 	return nil, auth.ErrInvalidAccessToken
@@ -1,18 +1,24 @@
 package usecase
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/khuchuz/go-clean-architecture-sql/auth"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
 	"github.com/khuchuz/go-clean-architecture-sql/auth/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
 	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
 func Test_SignUp_Success(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -22,6 +28,7 @@ func Test_SignUp_Success(t *testing.T) {
 			Username: username,
 			Email:    email,
 			Password: "11f5639f22525155cb0b43573ee4212838c78d87", // sha1 of pass+salt
+			Role:     models.RoleUser,
 		}
 	)
 
@@ -29,13 +36,13 @@ func Test_SignUp_Success(t *testing.T) {
 	repo.On("SQLIsUserExistByUsername", username).Return(false)
 	repo.On("SQLIsUserExistByEmail", email).Return(false)
 	repo.On("SQLCreateUser", user).Return(nil)
-	err := uc.SignUp(models.SignUpInput{Username: username, Email: email, Password: password})
+	err := uc.SignUp(context.Background(), models.SignUpInput{Username: username, Email: email, Password: password})
 	assert.NoError(t, err)
 }
 
 func Test_SignUp_Failed_DupUsername(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -51,13 +58,13 @@ func Test_SignUp_Failed_DupUsername(t *testing.T) {
 	// Sign Up
 	repo.On("SQLIsUserExistByUsername", username).Return(true)
 	repo.On("SQLCreateUser", user).Return(nil)
-	err := uc.SignUp(models.SignUpInput{Username: username, Email: email, Password: password})
+	err := uc.SignUp(context.Background(), models.SignUpInput{Username: username, Email: email, Password: password})
 	assert.Error(t, err, auth.ErrUserDuplicate)
 }
 
 func Test_SignUp_Failed_DupEmail(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -74,12 +81,29 @@ func Test_SignUp_Failed_DupEmail(t *testing.T) {
 	repo.On("SQLIsUserExistByUsername", username).Return(false)
 	repo.On("SQLIsUserExistByEmail", email).Return(true)
 	repo.On("SQLCreateUser", user).Return(nil)
-	err := uc.SignUp(models.SignUpInput{Username: username, Email: email, Password: password})
+	err := uc.SignUp(context.Background(), models.SignUpInput{Username: username, Email: email, Password: password})
 	assert.Error(t, err, auth.ErrEmailDuplicate)
 }
+func Test_SignUp_Failed_DupEmail_LostRace(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+	var (
+		username = "usermock"
+		email    = "usermock@gmail.com"
+		password = "pass"
+	)
+
+	// Sign Up
+	repo.On("SQLIsUserExistByUsername", username).Return(false)
+	repo.On("SQLIsUserExistByEmail", email).Return(false)
+	repo.On("SQLCreateUser", testifymock.Anything).Return(fmt.Errorf("%w: Duplicate entry", dberr.ErrDuplicateKey))
+	err := uc.SignUp(context.Background(), models.SignUpInput{Username: username, Email: email, Password: password})
+	assert.Equal(t, auth.ErrEmailDuplicate, err)
+}
+
 func Test_SignUp_Failed_EmptyUsername(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = ""
 		email    = "usermock@gmail.com"
@@ -94,13 +118,13 @@ func Test_SignUp_Failed_EmptyUsername(t *testing.T) {
 
 	// Sign Up
 	repo.On("SQLCreateUser", user).Return(nil)
-	err := uc.SignUp(models.SignUpInput{Username: username, Email: email, Password: password})
+	err := uc.SignUp(context.Background(), models.SignUpInput{Username: username, Email: email, Password: password})
 	assert.Error(t, err, auth.ErrDataTidakLengkap)
 }
 
 func Test_SignUp_Failed_EmptyEmail(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = ""
@@ -115,13 +139,13 @@ func Test_SignUp_Failed_EmptyEmail(t *testing.T) {
 
 	// Sign Up
 	repo.On("SQLCreateUser", user).Return(nil)
-	err := uc.SignUp(models.SignUpInput{Username: username, Email: email, Password: password})
+	err := uc.SignUp(context.Background(), models.SignUpInput{Username: username, Email: email, Password: password})
 	assert.Error(t, err, auth.ErrDataTidakLengkap)
 }
 
 func Test_SignUp_Failed_Password(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -136,13 +160,13 @@ func Test_SignUp_Failed_Password(t *testing.T) {
 
 	// Sign Up
 	repo.On("SQLCreateUser", user).Return(nil)
-	err := uc.SignUp(models.SignUpInput{Username: username, Email: email, Password: password})
+	err := uc.SignUp(context.Background(), models.SignUpInput{Username: username, Email: email, Password: password})
 	assert.Error(t, err, auth.ErrDataTidakLengkap)
 }
 
 func Test_SignIn_Success(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -157,14 +181,14 @@ func Test_SignIn_Success(t *testing.T) {
 
 	// Sign In (Get Auth Token)
 	repo.On("SQLGetUser", user.Username, user.Password).Return(user, nil)
-	token, err := uc.SignIn(models.SignInput{Username: username, Password: password})
+	token, err := uc.SignIn(context.Background(), models.SignInput{Username: username, Password: password})
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
 }
 
 func Test_SignIn_Failed(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -179,13 +203,33 @@ func Test_SignIn_Failed(t *testing.T) {
 
 	// Sign In (Get Auth Token)
 	repo.On("SQLGetUser", user.Username, user.Password).Return(user, auth.ErrUnknown)
-	token, err := uc.SignIn(models.SignInput{Username: username, Password: password})
+	token, err := uc.SignIn(context.Background(), models.SignInput{Username: username, Password: password})
 	assert.Error(t, err, auth.ErrUserNotFound)
 	assert.Empty(t, token)
 }
+func Test_SignIn_Failed_Disabled(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+	var (
+		username = "usermock"
+		password = "pass"
+
+		user = &models.User{
+			Username: username,
+			Password: "11f5639f22525155cb0b43573ee4212838c78d87", // sha1 of pass+salt
+			Disabled: true,
+		}
+	)
+
+	repo.On("SQLGetUser", user.Username, user.Password).Return(user, nil)
+	token, err := uc.SignIn(context.Background(), models.SignInput{Username: username, Password: password})
+	assert.Equal(t, auth.ErrUserDisabled, err)
+	assert.Empty(t, token)
+}
+
 func Test_ParseToken_Success(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -199,19 +243,46 @@ func Test_ParseToken_Success(t *testing.T) {
 	)
 
 	repo.On("SQLGetUser", user.Username, user.Password).Return(user, nil)
-	token, err := uc.SignIn(models.SignInput{Username: username, Password: password})
+	repo.On("SQLGetUserByUsername", username).Return(user, nil)
+	token, err := uc.SignIn(context.Background(), models.SignInput{Username: username, Password: password})
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
 
 	// Verify token
-	parsedUser, err := uc.ParseToken(token)
+	parsedUser, err := uc.ParseToken(context.Background(), token)
 	assert.NoError(t, err)
 	assert.Equal(t, user, parsedUser)
 }
 
+func Test_ParseToken_Revoked(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+
+	repo.On("SQLGetUserByUsername", "deleted").Return(&models.User{Username: "deleted"}, nil).Once()
+	repo.On("SQLGetUserByUsername", "disabled").Return(&models.User{Username: "disabled"}, nil).Once()
+	repo.On("SQLGetUserByUsername", "unreachable").Return(&models.User{Username: "unreachable"}, nil).Once()
+	tokens := make(map[string]string)
+	for _, username := range []string{"deleted", "disabled", "unreachable"} {
+		token, err := uc.IssueToken(context.Background(), username, time.Hour)
+		assert.NoError(t, err)
+		tokens[username] = token
+	}
+
+	repo.On("SQLGetUserByUsername", "deleted").Return(&models.User{}, gorm.ErrRecordNotFound)
+	repo.On("SQLGetUserByUsername", "disabled").Return(&models.User{Username: "disabled", Disabled: true}, nil)
+	repo.On("SQLGetUserByUsername", "unreachable").Return(&models.User{}, fmt.Errorf("connection refused"))
+
+	_, err := uc.ParseToken(context.Background(), tokens["deleted"])
+	assert.Equal(t, auth.ErrInvalidAccessToken, err)
+	_, err = uc.ParseToken(context.Background(), tokens["disabled"])
+	assert.Equal(t, auth.ErrInvalidAccessToken, err)
+	_, err = uc.ParseToken(context.Background(), tokens["unreachable"])
+	assert.EqualError(t, err, "connection refused")
+}
+
 func Test_ParseToken_Failed(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		email    = "usermock@gmail.com"
@@ -225,14 +296,14 @@ func Test_ParseToken_Failed(t *testing.T) {
 	var token = "mboh"
 
 	// Verify token
-	parsedUser, err := uc.ParseToken(token)
+	parsedUser, err := uc.ParseToken(context.Background(), token)
 	assert.Error(t, err)
 	assert.NotEqual(t, user, parsedUser)
 }
 
 func Test_ChangePassword_Sucess(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username     = "usermock"
 		email        = "usermock@gmail.com"
@@ -249,13 +320,13 @@ func Test_ChangePassword_Sucess(t *testing.T) {
 
 	// Change Password
 	repo.On("SQLUpdatePassword", user.Username, user.Password, newpasscrypt).Return(nil)
-	err := uc.ChangePassword(models.ChangePasswordInput{Username: username, OldPassword: password, Password: newpass})
+	err := uc.ChangePassword(context.Background(), models.ChangePasswordInput{Username: username, OldPassword: password, Password: newpass})
 	assert.NoError(t, err)
 }
 
 func Test_ChangePassword_Failed_WrongOldPass(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username     = "usermock"
 		email        = "usermock@gmail.com"
@@ -272,13 +343,13 @@ func Test_ChangePassword_Failed_WrongOldPass(t *testing.T) {
 
 	// Change Password
 	repo.On("SQLUpdatePassword", user.Username, user.Password, newpasscrypt).Return(gorm.ErrInvalidTransaction)
-	err := uc.ChangePassword(models.ChangePasswordInput{Username: username, OldPassword: password, Password: newpass})
+	err := uc.ChangePassword(context.Background(), models.ChangePasswordInput{Username: username, OldPassword: password, Password: newpass})
 	assert.Error(t, err, auth.ErrInvalidCreds)
 }
 
 func Test_ChangePassword_Failed_EmptyField(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		password = "pass"
@@ -286,35 +357,35 @@ func Test_ChangePassword_Failed_EmptyField(t *testing.T) {
 	)
 
 	// Empty Username
-	err := uc.ChangePassword(models.ChangePasswordInput{Username: "", OldPassword: password, Password: newpass})
+	err := uc.ChangePassword(context.Background(), models.ChangePasswordInput{Username: "", OldPassword: password, Password: newpass})
 	assert.EqualError(t, err, "data tidak lengkap")
 
 	// Empty Password
-	err = uc.ChangePassword(models.ChangePasswordInput{Username: username, OldPassword: password, Password: ""})
+	err = uc.ChangePassword(context.Background(), models.ChangePasswordInput{Username: username, OldPassword: password, Password: ""})
 	assert.EqualError(t, err, "data tidak lengkap")
 
 	// Empty OldPassword
-	err = uc.ChangePassword(models.ChangePasswordInput{Username: username, OldPassword: "", Password: newpass})
+	err = uc.ChangePassword(context.Background(), models.ChangePasswordInput{Username: username, OldPassword: "", Password: newpass})
 	assert.EqualError(t, err, "data tidak lengkap")
 
 }
 
 func Test_ChangePassword_Failed_EqualNewOld(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		password = "pass"
 	)
 
 	// Empty OldPassword
-	err := uc.ChangePassword(models.ChangePasswordInput{Username: username, OldPassword: password, Password: password})
+	err := uc.ChangePassword(context.Background(), models.ChangePasswordInput{Username: username, OldPassword: password, Password: password})
 	assert.EqualError(t, err, "password baru tidak boleh sama dengan password lama")
 }
 
 func Test_DeleteUser_Success(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		password = "pass"
@@ -327,13 +398,13 @@ func Test_DeleteUser_Success(t *testing.T) {
 
 	// Delete User
 	repo.On("SQLDeleteUser", user.Username, user.Password).Return(nil)
-	err := uc.DeleteAccount(models.DeleteInput{Username: username, Password: password})
+	err := uc.DeleteAccount(context.Background(), models.DeleteInput{Username: username, Password: password})
 	assert.NoError(t, err)
 }
 
 func Test_DeleteUser_Failed(t *testing.T) {
 	repo := new(mock.UserStorageMock)
-	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
 	var (
 		username = "usermock"
 		password = "pass"
@@ -346,6 +417,189 @@ func Test_DeleteUser_Failed(t *testing.T) {
 
 	// Delete User
 	repo.On("SQLDeleteUser", user.Username, user.Password).Return(auth.ErrUnknown)
-	err := uc.DeleteAccount(models.DeleteInput{Username: username, Password: password})
+	err := uc.DeleteAccount(context.Background(), models.DeleteInput{Username: username, Password: password})
 	assert.Error(t, err, auth.ErrUserNotFound)
 }
+
+func Test_IntrospectToken_Active(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+	var (
+		username = "usermock"
+		password = "pass"
+
+		user = &models.User{
+			Username: username,
+			Password: "11f5639f22525155cb0b43573ee4212838c78d87", // sha1 of pass+salt
+		}
+	)
+
+	repo.On("SQLGetUser", user.Username, user.Password).Return(user, nil)
+	repo.On("SQLGetUserByUsername", username).Return(&models.User{Username: username, Role: models.RoleAdmin}, nil)
+	token, err := uc.SignIn(context.Background(), models.SignInput{Username: username, Password: password})
+	assert.NoError(t, err)
+
+	resp, err := uc.IntrospectToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.True(t, resp.Active)
+	assert.Equal(t, models.RoleAdmin, resp.Role)
+	assert.Equal(t, username, resp.Sub)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.NotZero(t, resp.Exp)
+}
+
+func Test_IntrospectToken_Revoked(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+	var (
+		username = "usermock"
+		password = "pass"
+
+		user = &models.User{
+			Username: username,
+			Password: "11f5639f22525155cb0b43573ee4212838c78d87", // sha1 of pass+salt
+		}
+	)
+
+	repo.On("SQLGetUser", user.Username, user.Password).Return(user, nil)
+	repo.On("SQLGetUserByUsername", username).Return(&models.User{}, gorm.ErrRecordNotFound)
+	token, err := uc.SignIn(context.Background(), models.SignInput{Username: username, Password: password})
+	assert.NoError(t, err)
+
+	resp, err := uc.IntrospectToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.IntrospectResponse{Active: false, Revoked: true}, resp)
+}
+
+func Test_IntrospectToken_Disabled(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+	var (
+		username = "usermock"
+		password = "pass"
+
+		user = &models.User{
+			Username: username,
+			Password: "11f5639f22525155cb0b43573ee4212838c78d87", // sha1 of pass+salt
+		}
+	)
+
+	repo.On("SQLGetUser", user.Username, user.Password).Return(user, nil)
+	repo.On("SQLGetUserByUsername", username).Return(&models.User{Username: username, Disabled: true}, nil)
+	token, err := uc.SignIn(context.Background(), models.SignInput{Username: username, Password: password})
+	assert.NoError(t, err)
+
+	resp, err := uc.IntrospectToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.IntrospectResponse{Active: false, Revoked: true}, resp)
+}
+
+func Test_IntrospectToken_Invalid(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+
+	resp, err := uc.IntrospectToken(context.Background(), "mboh")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.IntrospectResponse{Active: false}, resp)
+}
+
+func Test_IntrospectToken_LookupFailed(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+
+	repo.On("SQLGetUserByUsername", "usermock").Return(&models.User{Username: "usermock"}, nil).Once()
+	token, err := uc.IssueToken(context.Background(), "usermock", time.Hour)
+	assert.NoError(t, err)
+
+	// A database that cannot answer is no proof the token was revoked.
+	repo.On("SQLGetUserByUsername", "usermock").Return(&models.User{}, fmt.Errorf("connection refused"))
+	resp, err := uc.IntrospectToken(context.Background(), token)
+	assert.EqualError(t, err, "connection refused")
+	assert.Nil(t, resp)
+}
+
+func Test_CreateUser_Admin(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+	var (
+		username = "admin"
+		email    = "admin@gmail.com"
+
+		user = &models.User{
+			Username: username,
+			Email:    email,
+			Password: "11f5639f22525155cb0b43573ee4212838c78d87", // sha1 of pass+salt
+			Role:     models.RoleAdmin,
+		}
+	)
+
+	repo.On("SQLIsUserExistByUsername", username).Return(false)
+	repo.On("SQLIsUserExistByEmail", email).Return(false)
+	repo.On("SQLCreateUser", user).Return(nil)
+	created, err := uc.CreateUser(context.Background(), models.CreateUserInput{Username: username, Email: email, Password: "pass", Role: models.RoleAdmin})
+	assert.NoError(t, err)
+	assert.Equal(t, user, created)
+}
+
+func Test_CreateUser_Failed_InvalidRole(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+
+	_, err := uc.CreateUser(context.Background(), models.CreateUserInput{Username: "root", Email: "root@gmail.com", Password: "pass", Role: "root"})
+	assert.Equal(t, auth.ErrInvalidRole, err)
+}
+
+func Test_SetDisabled(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+
+	repo.On("SQLGetUserByUsername", "usermock").Return(&models.User{ID: 1, Username: "usermock"}, nil)
+	repo.On("SQLSaveUser", &models.User{ID: 1, Username: "usermock", Disabled: true}).Return(nil)
+	assert.NoError(t, uc.SetDisabled(context.Background(), "usermock", true))
+
+	repo.On("SQLGetUserByUsername", "nobody").Return(&models.User{}, gorm.ErrRecordNotFound)
+	assert.Equal(t, auth.ErrUserNotFound, uc.SetDisabled(context.Background(), "nobody", true))
+	repo.AssertNumberOfCalls(t, "SQLSaveUser", 1)
+}
+
+func Test_ResetPassword(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+
+	repo.On("SQLGetUserByUsername", "usermock").Return(&models.User{ID: 1, Username: "usermock", Password: "old"}, nil)
+	repo.On("SQLSaveUser", &models.User{ID: 1, Username: "usermock", Password: "11f5639f22525155cb0b43573ee4212838c78d87"}).Return(nil)
+	assert.NoError(t, uc.ResetPassword(context.Background(), "usermock", "pass"))
+
+	assert.Equal(t, auth.ErrDataTidakLengkap, uc.ResetPassword(context.Background(), "usermock", ""))
+}
+
+func Test_SetRole(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+
+	repo.On("SQLGetUserByUsername", "usermock").Return(&models.User{ID: 1, Username: "usermock", Role: models.RoleUser}, nil)
+	repo.On("SQLSaveUser", &models.User{ID: 1, Username: "usermock", Role: models.RoleAdmin}).Return(nil)
+	assert.NoError(t, uc.SetRole(context.Background(), "usermock", models.RoleAdmin))
+
+	assert.Equal(t, auth.ErrInvalidRole, uc.SetRole(context.Background(), "usermock", "root"))
+	repo.AssertNumberOfCalls(t, "SQLSaveUser", 1)
+}
+
+func Test_IssueToken(t *testing.T) {
+	repo := new(mock.UserStorageMock)
+	uc := NewAuthUseCase(repo, "salt", []byte("secret"), 86400, logging.Discard())
+	user := &models.User{ID: 1, Username: "usermock", Role: models.RoleAdmin}
+
+	repo.On("SQLGetUserByUsername", "usermock").Return(user, nil)
+	token, err := uc.IssueToken(context.Background(), "usermock", time.Hour)
+	assert.NoError(t, err)
+
+	resp, err := uc.IntrospectToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.True(t, resp.Active)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), resp.Exp, 5)
+
+	repo.On("SQLGetUserByUsername", "disabled").Return(&models.User{Username: "disabled", Disabled: true}, nil)
+	_, err = uc.IssueToken(context.Background(), "disabled", 0)
+	assert.Equal(t, auth.ErrUserDisabled, err)
+}
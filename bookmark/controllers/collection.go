@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+type CollectionHandler struct {
+	useCase services.CollectionUseCase
+}
+
+func NewCollectionHandler(useCase services.CollectionUseCase) *CollectionHandler {
+	return &CollectionHandler{
+		useCase: useCase,
+	}
+}
+
+func (h *CollectionHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	collections, err := h.useCase.ListCollections(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CollectionListResponse{Collections: collections})
+}
+
+func (h *CollectionHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.CollectionInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	collection, err := h.useCase.CreateCollection(c.Request.Context(), userID, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+func (h *CollectionHandler) Get(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.useCase.GetCollection(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func (h *CollectionHandler) Rename(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.RenameCollectionInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	collection, err := h.useCase.RenameCollection(c.Request.Context(), userID, id, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func (h *CollectionHandler) Move(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.MoveCollectionInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	collection, err := h.useCase.MoveCollection(c.Request.Context(), userID, id, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func (h *CollectionHandler) Delete(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteCollection(c.Request.Context(), userID, id, c.Query("mode")); err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BookmarkResponse{Message: "Collection berhasil dihapus"})
+}
+
+func (h *CollectionHandler) SetBookmarkCollection(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.BookmarkCollectionInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	bm, err := h.useCase.SetBookmarkCollection(c.Request.Context(), userID, id, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bm)
+}
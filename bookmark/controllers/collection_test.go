@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCollectionRouter(uc *mock.CollectionUseCaseMock, user *authmodels.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		if user != nil {
+			c.Set(authservices.CtxUserKey, user)
+		}
+	})
+	RegisterCollectionEndpoints(api, uc)
+
+	return r
+}
+
+func TestListCollections_Success_200(t *testing.T) {
+	uc := new(mock.CollectionUseCaseMock)
+	r := newCollectionRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("ListCollections", uint(1)).Return([]models.CollectionNode{{
+		Collection:    models.Collection{ID: 1, Name: "work", Path: "/1/"},
+		BookmarkCount: 2,
+		Children:      []models.CollectionNode{{Collection: models.Collection{ID: 2, Name: "go", Path: "/1/2/"}, Children: []models.CollectionNode{}}},
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/collections", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"name\":\"work\"")
+	assert.Contains(t, w.Body.String(), "\"bookmark_count\":2")
+	assert.Contains(t, w.Body.String(), "\"children\":[{\"id\":2")
+}
+
+func TestCreateCollection_Duplicate_409(t *testing.T) {
+	uc := new(mock.CollectionUseCaseMock)
+	r := newCollectionRouter(uc, &authmodels.User{ID: 1})
+
+	parentID := uint(1)
+	uc.On("CreateCollection", uint(1), models.CollectionInput{Name: "go", ParentID: &parentID}).Return((*models.Collection)(nil), bookmark.ErrCollectionDuplicate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/collections", bytes.NewBufferString(`{"name":"go","parent_id":1}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestMoveCollection_Success_200(t *testing.T) {
+	uc := new(mock.CollectionUseCaseMock)
+	r := newCollectionRouter(uc, &authmodels.User{ID: 1})
+
+	position := 0
+	uc.On("MoveCollection", uint(1), uint(2), models.MoveCollectionInput{Position: &position}).Return(&models.Collection{ID: 2, Path: "/2/"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/collections/2/move", bytes.NewBufferString(`{"parent_id":null,"position":0}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"path\":\"/2/\"")
+}
+
+func TestMoveCollection_Cycle_400(t *testing.T) {
+	uc := new(mock.CollectionUseCaseMock)
+	r := newCollectionRouter(uc, &authmodels.User{ID: 1})
+
+	parentID := uint(5)
+	uc.On("MoveCollection", uint(1), uint(1), models.MoveCollectionInput{ParentID: &parentID}).Return((*models.Collection)(nil), bookmark.ErrCollectionCycle)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/collections/1/move", bytes.NewBufferString(`{"parent_id":5}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestDeleteCollection_Success_200(t *testing.T) {
+	uc := new(mock.CollectionUseCaseMock)
+	r := newCollectionRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("DeleteCollection", uint(1), uint(2), "delete").Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/collections/2?mode=delete", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestSetBookmarkCollection_NotFound_404(t *testing.T) {
+	uc := new(mock.CollectionUseCaseMock)
+	r := newCollectionRouter(uc, &authmodels.User{ID: 1})
+
+	collectionID := uint(9)
+	uc.On("SetBookmarkCollection", uint(1), uint(7), models.BookmarkCollectionInput{CollectionID: &collectionID}).Return((*models.Bookmark)(nil), bookmark.ErrCollectionNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/bookmarks/7/collection", bytes.NewBufferString(`{"collection_id":9}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "{\"message\":\"collection not found\"}", w.Body.String())
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+type exportType struct {
+	contentType string
+	extension   string
+}
+
+var exportTypes = map[string]exportType{
+	models.ExportFormatHTML:     {"text/html; charset=utf-8", "html"},
+	models.ExportFormatJSON:     {"application/json; charset=utf-8", "json"},
+	models.ExportFormatCSV:      {"text/csv; charset=utf-8", "csv"},
+	models.ExportFormatMarkdown: {"text/markdown; charset=utf-8", "md"},
+}
+
+type ExportHandler struct {
+	useCase services.ExportUseCase
+	log     *slog.Logger
+}
+
+func NewExportHandler(useCase services.ExportUseCase, log *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		useCase: useCase,
+		log:     log,
+	}
+}
+
+// Export streams the file as it is written, so it is never held in memory.
+// Large libraries take longer than the write timeout of the server, which is
+// lifted for it.
+func (h *ExportHandler) Export(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", models.ExportFormatHTML)
+	typ, ok := exportTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrExportFormat.Error()})
+		return
+	}
+
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.log.WarnContext(c.Request.Context(), "export: cannot lift the write deadline", "user", userID, "err", err)
+	}
+
+	filename := "bookmarks-" + time.Now().Format("2006-01-02") + "." + typ.extension
+	c.Header("Content-Type", typ.contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if err := h.useCase.ExportBookmarks(c.Request.Context(), userID, format, c.Writer); err != nil {
+		if c.Writer.Written() {
+			// Part of the file is out already; all that is left is to stop.
+			h.log.ErrorContext(c.Request.Context(), "export failed", "user", userID, "err", err)
+			return
+		}
+
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+	}
+}
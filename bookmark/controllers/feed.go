@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+// feedMaxAge is how long feed readers may keep a feed before asking again.
+const feedMaxAge = "900"
+
+var feedTypes = map[string]string{
+	models.FeedFormatAtom: "application/atom+xml; charset=utf-8",
+	models.FeedFormatRSS:  "application/rss+xml; charset=utf-8",
+}
+
+type FeedHandler struct {
+	useCase services.FeedUseCase
+}
+
+func NewFeedHandler(useCase services.FeedUseCase) *FeedHandler {
+	return &FeedHandler{
+		useCase: useCase,
+	}
+}
+
+func (h *FeedHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.FeedInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	feed, err := h.useCase.CreateFeed(c.Request.Context(), userID, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, feed)
+}
+
+func (h *FeedHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feeds, err := h.useCase.ListFeeds(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.FeedListResponse{Feeds: feeds})
+}
+
+func (h *FeedHandler) Revoke(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.RevokeFeed(c.Request.Context(), userID, id); err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BookmarkResponse{Message: "Feed berhasil dicabut"})
+}
+
+// View serves a feed to feed readers, which hold the token instead of an
+// account. Readers that send back the ETag or Last-Modified they got get a
+// 304 while nothing changed.
+func (h *FeedHandler) View(c *gin.Context) {
+	format := c.Param("format")
+	contentType, ok := feedTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrFeedFormat.Error()})
+		return
+	}
+
+	feed, err := h.useCase.GetFeed(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	etag := `"` + feed.ETag + "-" + format + `"`
+	c.Header("Cache-Control", "private, max-age="+feedMaxAge)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", feed.Updated.UTC().Format(http.TimeFormat))
+	c.Header("X-Robots-Tag", "noindex")
+
+	if notModified(c, etag, feed.Updated) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	feed.ID = requestBaseURL(c) + "/feeds/" + c.Param("token")
+	feed.SelfURL = feed.ID + "/" + format
+
+	var buf bytes.Buffer
+	if err := h.useCase.WriteFeed(&buf, format, feed); err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// notModified answers a conditional request. If-None-Match wins over
+// If-Modified-Since when both are sent.
+func notModified(c *gin.Context, etag string, updated time.Time) bool {
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !updated.Truncate(time.Second).After(since)
+}
+
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func newFeedRouter(uc *mock.FeedUseCaseMock, user *authmodels.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		if user != nil {
+			c.Set(authservices.CtxUserKey, user)
+		}
+	})
+	RegisterFeedEndpoints(api, uc)
+	RegisterPublicFeedEndpoints(r, uc)
+
+	return r
+}
+
+func TestCreateFeed_Success_201(t *testing.T) {
+	uc := new(mock.FeedUseCaseMock)
+	r := newFeedRouter(uc, &authmodels.User{ID: 1})
+
+	tagID := uint(4)
+	uc.On("CreateFeed", uint(1), models.FeedInput{Tag: "golang"}).Return(&models.Feed{ID: 2, Token: "abc", TagID: &tagID}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/feeds", bytes.NewBufferString(`{"tag":"golang"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+	assert.Contains(t, w.Body.String(), "\"token\":\"abc\"")
+}
+
+func TestViewFeed_Atom_200(t *testing.T) {
+	uc := new(mock.FeedUseCaseMock)
+	r := newFeedRouter(uc, nil)
+
+	updated := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	uc.On("GetFeed", "abc").Return(&models.FeedDocument{Title: "Public bookmarks", Updated: updated, ETag: "e1"}, nil)
+	uc.On("WriteFeed", testifymock.Anything, models.FeedFormatAtom, testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		feed := args.Get(2).(*models.FeedDocument)
+		io.WriteString(args.Get(0).(io.Writer), feed.SelfURL)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/feeds/abc/atom", nil)
+	req.Host = "example.com"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `"e1-atom"`, w.Header().Get("ETag"))
+	assert.Equal(t, "Mon, 01 Mar 2021 10:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age=")
+	assert.Equal(t, "https://example.com/feeds/abc/atom", w.Body.String())
+}
+
+func TestViewFeed_NotModified_304(t *testing.T) {
+	uc := new(mock.FeedUseCaseMock)
+	r := newFeedRouter(uc, nil)
+
+	updated := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	uc.On("GetFeed", "abc").Return(&models.FeedDocument{Updated: updated, ETag: "e1"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/feeds/abc/rss", nil)
+	req.Header.Set("If-None-Match", `"e0-rss", "e1-rss"`)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 304, w.Code)
+	assert.Zero(t, w.Body.Len())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/feeds/abc/rss", nil)
+	req.Header.Set("If-Modified-Since", "Mon, 01 Mar 2021 10:00:00 GMT")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 304, w.Code)
+
+	uc.AssertNotCalled(t, "WriteFeed", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestViewFeed_Failed(t *testing.T) {
+	uc := new(mock.FeedUseCaseMock)
+	r := newFeedRouter(uc, nil)
+
+	uc.On("GetFeed", "gone").Return((*models.FeedDocument)(nil), bookmark.ErrFeedNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/feeds/gone/atom", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 404, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/feeds/gone/json", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+type Handler struct {
+	useCase services.UseCase
+}
+
+func NewHandler(useCase services.UseCase) *Handler {
+	return &Handler{
+		useCase: useCase,
+	}
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.BookmarkInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	bm, err := h.useCase.CreateBookmark(c.Request.Context(), userID, *inp)
+	if err == bookmark.ErrBookmarkDuplicate && bm != nil {
+		c.JSON(http.StatusOK, bm)
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, bm)
+}
+
+func (h *Handler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.ListInput)
+	if err := c.ShouldBindQuery(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	bookmarks, err := h.useCase.ListBookmarks(c.Request.Context(), userID, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BookmarkListResponse{Bookmarks: bookmarks})
+}
+
+func (h *Handler) Get(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	bm, err := h.useCase.GetBookmark(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bm)
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.BookmarkInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	bm, err := h.useCase.UpdateBookmark(c.Request.Context(), userID, id, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bm)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteBookmark(c.Request.Context(), userID, id); err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BookmarkResponse{Message: "Bookmark berhasil dihapus"})
+}
+
+func (h *Handler) Content(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	content, err := h.useCase.GetContent(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, content)
+}
+
+// currentUserID reads the user stored by the auth middleware. It writes the
+// 401 response itself so handlers only need to return when it fails.
+func currentUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get(authservices.CtxUserKey)
+	user, ok := value.(*authmodels.User)
+	if !exists || !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, models.BookmarkResponse{Message: bookmark.ErrUnauthorized.Error()})
+		return 0, false
+	}
+
+	return user.ID, true
+}
+
+func currentUserAndID(c *gin.Context) (uint, uint, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	id, ok := paramID(c, "id")
+	if !ok {
+		return 0, 0, false
+	}
+
+	return userID, id, true
+}
+
+func paramID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func errorStatus(err error) int {
+	switch err {
+	case bookmark.ErrBookmarkNotFound, bookmark.ErrTagNotFound, bookmark.ErrContentNotFound, bookmark.ErrImportNotFound,
+		bookmark.ErrCollectionNotFound, bookmark.ErrShareNotFound, bookmark.ErrHighlightNotFound, bookmark.ErrFeedNotFound:
+		return http.StatusNotFound
+	case bookmark.ErrDataTidakLengkap, bookmark.ErrInvalidURL, bookmark.ErrBadRequest, bookmark.ErrInvalidTag,
+		bookmark.ErrImportFormat, bookmark.ErrImportEmpty, bookmark.ErrExportFormat, bookmark.ErrInvalidState,
+		bookmark.ErrInvalidCollection, bookmark.ErrCollectionCycle, bookmark.ErrCollectionDepth,
+		bookmark.ErrShareTarget, bookmark.ErrInvalidExpiry, bookmark.ErrInvalidLinkStatus, bookmark.ErrInvalidHighlight,
+		bookmark.ErrFeedTarget, bookmark.ErrFeedFormat:
+		return http.StatusBadRequest
+	case bookmark.ErrTagDuplicate, bookmark.ErrImportRunning, bookmark.ErrCollectionDuplicate, bookmark.ErrBookmarkDuplicate:
+		return http.StatusConflict
+	case bookmark.ErrFileTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case bookmark.ErrSharePassword:
+		return http.StatusUnauthorized
+	case bookmark.ErrShareExpired:
+		return http.StatusGone
+	case bookmark.ErrShareLocked:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,309 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(uc *mock.BookmarkUseCaseMock, user *authmodels.User) *gin.Engine {
+	return newBookmarkRouter(uc, new(mock.ExportUseCaseMock), user)
+}
+
+func newBookmarkRouter(uc *mock.BookmarkUseCaseMock, exportUC *mock.ExportUseCaseMock, user *authmodels.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		if user != nil {
+			c.Set(authservices.CtxUserKey, user)
+		}
+	})
+	RegisterHTTPEndpoints(api, uc, exportUC, logging.Discard())
+
+	return r
+}
+
+func TestCreate_Success_201(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	inp := models.BookmarkInput{URL: "https://example.com", Title: "Example"}
+	body, err := json.Marshal(inp)
+	assert.NoError(t, err)
+
+	uc.On("CreateBookmark", uint(1), inp).Return(&models.Bookmark{ID: 7, UserID: 1, URL: inp.URL, Title: inp.Title}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+	assert.Contains(t, w.Body.String(), "\"id\":7")
+	assert.NotContains(t, w.Body.String(), "user_id")
+}
+
+func TestCreate_Duplicate_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	inp := models.BookmarkInput{URL: "http://Example.com/?utm_source=x"}
+	body, err := json.Marshal(inp)
+	assert.NoError(t, err)
+
+	uc.On("CreateBookmark", uint(1), inp).Return(&models.Bookmark{ID: 7, URL: "https://example.com/", NormalizedURL: "example.com"}, bookmark.ErrBookmarkDuplicate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"id\":7")
+	assert.Contains(t, w.Body.String(), "\"normalized_url\":\"example.com\"")
+}
+
+func TestCreate_Failed_400(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	inp := models.BookmarkInput{URL: "bukan url"}
+	body, err := json.Marshal(inp)
+	assert.NoError(t, err)
+
+	uc.On("CreateBookmark", uint(1), inp).Return((*models.Bookmark)(nil), bookmark.ErrInvalidURL)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "{\"message\":\"url tidak valid\"}", w.Body.String())
+}
+
+func TestCreate_Failed_401(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks", bytes.NewBufferString("{}"))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+}
+
+func TestList_Success_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("ListBookmarks", uint(1), models.ListInput{Limit: 5, Offset: 10}).Return([]models.Bookmark{{ID: 7, URL: "https://example.com"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks?limit=5&offset=10", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"bookmarks\":[{\"id\":7")
+}
+
+func TestList_FilterLink_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("ListBookmarks", uint(1), models.ListInput{Link: models.LinkStatusBroken}).Return([]models.Bookmark{{ID: 7, LinkStatus: models.LinkStatusBroken, LinkCode: 404}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks?link=broken", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"link_status\":\"broken\",\"link_code\":404")
+}
+
+func TestGet_Failed_404(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 2})
+
+	uc.On("GetBookmark", uint(2), uint(7)).Return((*models.Bookmark)(nil), bookmark.ErrBookmarkNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/7", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "{\"message\":\"bookmark not found\"}", w.Body.String())
+}
+
+func TestGet_Failed_InvalidID(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/abc", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestUpdate_Success_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	inp := models.BookmarkInput{URL: "https://example.com", Title: "Baru"}
+	body, err := json.Marshal(inp)
+	assert.NoError(t, err)
+
+	uc.On("UpdateBookmark", uint(1), uint(7), inp).Return(&models.Bookmark{ID: 7, URL: inp.URL, Title: inp.Title}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/bookmarks/7", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"title\":\"Baru\"")
+}
+
+func TestUpdate_Duplicate_409(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("UpdateBookmark", uint(1), uint(7), models.BookmarkInput{URL: "https://example.com/b"}).Return((*models.Bookmark)(nil), bookmark.ErrBookmarkDuplicate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/bookmarks/7", bytes.NewBufferString(`{"url":"https://example.com/b"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 409, w.Code)
+	assert.Equal(t, "{\"message\":\"bookmark sudah ada\"}", w.Body.String())
+}
+
+func TestDelete_Success_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("DeleteBookmark", uint(1), uint(7)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/bookmarks/7", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "{\"message\":\"Bookmark berhasil dihapus\"}", w.Body.String())
+}
+
+func TestContent_Success_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("GetContent", uint(1), uint(7)).Return(&models.BookmarkContent{BookmarkID: 7, HTML: "<p>Hello</p>", Text: "Hello", WordCount: 1, ReadingTime: 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/7/content", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"text\":\"Hello\"")
+	assert.Contains(t, w.Body.String(), "\"reading_time\":1")
+}
+
+func TestContent_NotExtracted_404(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("GetContent", uint(1), uint(7)).Return((*models.BookmarkContent)(nil), bookmark.ErrContentNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/7/content", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "{\"message\":\"konten belum tersedia\"}", w.Body.String())
+}
+
+func TestExport_Success_200(t *testing.T) {
+	exportUC := new(mock.ExportUseCaseMock)
+	r := newBookmarkRouter(new(mock.BookmarkUseCaseMock), exportUC, &authmodels.User{ID: 1})
+
+	exportUC.On("ExportBookmarks", uint(1), "csv", testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		args.Get(2).(io.Writer).Write([]byte("url,title,notes,tags,created,updated\n"))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/export?format=csv", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="bookmarks-`)
+	assert.Equal(t, "url,title,notes,tags,created,updated\n", w.Body.String())
+}
+
+// Exports of large libraries outlast the write timeout of the server.
+func TestExport_OutlastsWriteTimeout(t *testing.T) {
+	exportUC := new(mock.ExportUseCaseMock)
+	r := newBookmarkRouter(new(mock.BookmarkUseCaseMock), exportUC, &authmodels.User{ID: 1})
+
+	exportUC.On("ExportBookmarks", uint(1), "csv", testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		w := args.Get(2).(io.Writer)
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("https://example.com,Example,,,,\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	})
+
+	ts := httptest.NewUnstartedServer(r)
+	ts.Config.WriteTimeout = 150 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/api/bookmarks/export?format=csv")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("https://example.com,Example,,,,\n", 3), string(body))
+}
+
+func TestExport_UnknownFormat_400(t *testing.T) {
+	exportUC := new(mock.ExportUseCaseMock)
+	r := newBookmarkRouter(new(mock.BookmarkUseCaseMock), exportUC, &authmodels.User{ID: 1})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/export?format=pdf", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	exportUC.AssertNotCalled(t, "ExportBookmarks", uint(1), "pdf", testifymock.Anything)
+}
+
+func TestExport_FailedBeforeWriting_500(t *testing.T) {
+	exportUC := new(mock.ExportUseCaseMock)
+	r := newBookmarkRouter(new(mock.BookmarkUseCaseMock), exportUC, &authmodels.User{ID: 1})
+
+	exportUC.On("ExportBookmarks", uint(1), "html", testifymock.Anything).Return(errors.New("some error"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/export", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+type HighlightHandler struct {
+	useCase services.HighlightUseCase
+	log     *slog.Logger
+}
+
+func NewHighlightHandler(useCase services.HighlightUseCase, log *slog.Logger) *HighlightHandler {
+	return &HighlightHandler{
+		useCase: useCase,
+		log:     log,
+	}
+}
+
+func (h *HighlightHandler) Create(c *gin.Context) {
+	userID, bookmarkID, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.HighlightInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	highlight, err := h.useCase.CreateHighlight(c.Request.Context(), userID, bookmarkID, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, highlight)
+}
+
+// ListBookmark lists the highlights of one bookmark, or downloads them as
+// Markdown with ?format=md.
+func (h *HighlightHandler) ListBookmark(c *gin.Context) {
+	userID, bookmarkID, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	switch c.Query("format") {
+	case "":
+	case models.ExportFormatMarkdown:
+		h.export(c, userID, bookmarkID)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrExportFormat.Error()})
+		return
+	}
+
+	highlights, err := h.useCase.ListBookmarkHighlights(c.Request.Context(), userID, bookmarkID)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.HighlightListResponse{Highlights: highlights})
+}
+
+// List lists all highlights of the user, newest first, or downloads every
+// one of them as Markdown with ?format=md.
+func (h *HighlightHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.HighlightListInput)
+	if err := c.ShouldBindQuery(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	switch inp.Format {
+	case "":
+	case models.ExportFormatMarkdown:
+		h.export(c, userID, 0)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrExportFormat.Error()})
+		return
+	}
+
+	highlights, err := h.useCase.ListHighlights(c.Request.Context(), userID, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.HighlightListResponse{Highlights: highlights})
+}
+
+func (h *HighlightHandler) Update(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.HighlightUpdateInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	highlight, err := h.useCase.UpdateHighlight(c.Request.Context(), userID, id, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, highlight)
+}
+
+func (h *HighlightHandler) Delete(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteHighlight(c.Request.Context(), userID, id); err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BookmarkResponse{Message: "Highlight berhasil dihapus"})
+}
+
+func (h *HighlightHandler) export(c *gin.Context, userID, bookmarkID uint) {
+	typ := exportTypes[models.ExportFormatMarkdown]
+	filename := "highlights-" + time.Now().Format("2006-01-02") + "." + typ.extension
+	c.Header("Content-Type", typ.contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if err := h.useCase.ExportHighlights(c.Request.Context(), userID, bookmarkID, c.Writer); err != nil {
+		if c.Writer.Written() {
+			h.log.ErrorContext(c.Request.Context(), "highlights export failed", "user", userID, "bookmark", bookmarkID, "err", err)
+			return
+		}
+
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func newHighlightRouter(uc *mock.HighlightUseCaseMock, user *authmodels.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		if user != nil {
+			c.Set(authservices.CtxUserKey, user)
+		}
+	})
+	RegisterHighlightEndpoints(api, uc, logging.Discard())
+
+	return r
+}
+
+func TestCreateHighlight_Success_201(t *testing.T) {
+	uc := new(mock.HighlightUseCaseMock)
+	r := newHighlightRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("CreateHighlight", uint(1), uint(7), models.HighlightInput{Start: 4, End: 9, Note: "nice"}).
+		Return(&models.Highlight{ID: 3, BookmarkID: 7, StartOffset: 4, EndOffset: 9, Quote: "quick", Note: "nice"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks/7/highlights", bytes.NewBufferString(`{"start":4,"end":9,"note":"nice"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+	assert.Contains(t, w.Body.String(), "\"quote\":\"quick\"")
+}
+
+func TestCreateHighlight_Invalid_400(t *testing.T) {
+	uc := new(mock.HighlightUseCaseMock)
+	r := newHighlightRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("CreateHighlight", uint(1), uint(7), models.HighlightInput{Start: 9, End: 4}).
+		Return((*models.Highlight)(nil), bookmark.ErrInvalidHighlight)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks/7/highlights", bytes.NewBufferString(`{"start":9,"end":4}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestListBookmarkHighlights_Success_200(t *testing.T) {
+	uc := new(mock.HighlightUseCaseMock)
+	r := newHighlightRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("ListBookmarkHighlights", uint(1), uint(7)).
+		Return([]models.Highlight{{ID: 3, Quote: "quick", Orphaned: true}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/bookmarks/7/highlights", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"orphaned\":true")
+}
+
+func TestExportHighlights_Markdown_200(t *testing.T) {
+	uc := new(mock.HighlightUseCaseMock)
+	r := newHighlightRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("ExportHighlights", uint(1), uint(0), testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		io.WriteString(args.Get(2).(io.Writer), "# Highlights\n")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/highlights?format=md", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "highlights-")
+	assert.Equal(t, "# Highlights\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/bookmarks/7/highlights?format=pdf", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestDeleteHighlight_NotFound_404(t *testing.T) {
+	uc := new(mock.HighlightUseCaseMock)
+	r := newHighlightRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("DeleteHighlight", uint(1), uint(3)).Return(bookmark.ErrHighlightNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/highlights/3", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+const maxImportSize = 32 << 20
+
+type ImportHandler struct {
+	useCase services.ImportUseCase
+}
+
+func NewImportHandler(useCase services.ImportUseCase) *ImportHandler {
+	return &ImportHandler{
+		useCase: useCase,
+	}
+}
+
+// Create takes a multipart upload with the export in the "file" field.
+func (h *ImportHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.ImportInput)
+	if err := c.ShouldBind(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrDataTidakLengkap.Error()})
+		return
+	}
+	if header.Size > maxImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, models.BookmarkResponse{Message: bookmark.ErrFileTooLarge.Error()})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	job, err := h.useCase.StartImport(c.Request.Context(), userID, *inp, data)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *ImportHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.useCase.ListImports(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ImportListResponse{Imports: jobs})
+}
+
+func (h *ImportHandler) Get(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.useCase.GetImport(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
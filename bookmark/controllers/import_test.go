@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newImportRouter(uc *mock.ImportUseCaseMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		c.Set(authservices.CtxUserKey, &authmodels.User{ID: 1})
+	})
+	RegisterImportEndpoints(api, uc)
+
+	return r
+}
+
+func newImportRequest(fields map[string]string, file []byte) *http.Request {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	for k, v := range fields {
+		w.WriteField(k, v)
+	}
+	if file != nil {
+		part, _ := w.CreateFormFile("file", "bookmarks.html")
+		part.Write(file)
+	}
+	w.Close()
+
+	req, _ := http.NewRequest("POST", "/api/imports", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestImport_Create_202(t *testing.T) {
+	uc := new(mock.ImportUseCaseMock)
+	r := newImportRouter(uc)
+
+	file := []byte("url\nhttps://go.dev/\n")
+	uc.On("StartImport", uint(1), models.ImportInput{Format: "csv", FolderMode: "none"}, file).
+		Return(&models.ImportJob{ID: 3, Format: "csv", Status: models.ImportStatusRunning, Total: 1}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(map[string]string{"format": "csv", "folder_mode": "none"}, file))
+
+	assert.Equal(t, 202, w.Code)
+	assert.Contains(t, w.Body.String(), "\"status\":\"running\"")
+	assert.Contains(t, w.Body.String(), "\"total\":1")
+}
+
+func TestImport_Create_MissingFile_400(t *testing.T) {
+	uc := new(mock.ImportUseCaseMock)
+	r := newImportRouter(uc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(map[string]string{"format": "csv"}, nil))
+
+	assert.Equal(t, 400, w.Code)
+	uc.AssertNotCalled(t, "StartImport")
+}
+
+func TestImport_Create_Running_409(t *testing.T) {
+	uc := new(mock.ImportUseCaseMock)
+	r := newImportRouter(uc)
+
+	file := []byte("url\nhttps://go.dev/\n")
+	uc.On("StartImport", uint(1), models.ImportInput{}, file).Return((*models.ImportJob)(nil), bookmark.ErrImportRunning)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newImportRequest(nil, file))
+
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestImport_Get_200(t *testing.T) {
+	uc := new(mock.ImportUseCaseMock)
+	r := newImportRouter(uc)
+
+	uc.On("GetImport", uint(1), uint(3)).Return(&models.ImportJob{
+		ID:     3,
+		Status: models.ImportStatusDone,
+		Errors: []models.ImportError{{Row: 4, URL: "ftp://example.com/", Error: "url tidak valid"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/imports/3", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"errors\":[{\"row\":4,\"url\":\"ftp://example.com/\",\"error\":\"url tidak valid\"}]")
+}
+
+func TestImport_Get_404(t *testing.T) {
+	uc := new(mock.ImportUseCaseMock)
+	r := newImportRouter(uc)
+
+	uc.On("GetImport", uint(1), uint(3)).Return((*models.ImportJob)(nil), bookmark.ErrImportNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/imports/3", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+type MetadataHandler struct {
+	useCase services.MetadataUseCase
+}
+
+func NewMetadataHandler(useCase services.MetadataUseCase) *MetadataHandler {
+	return &MetadataHandler{
+		useCase: useCase,
+	}
+}
+
+func (h *MetadataHandler) Refresh(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	bm, err := h.useCase.RefreshMetadata(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bm)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMetadataRouter(uc *mock.MetadataUseCaseMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		c.Set(authservices.CtxUserKey, &authmodels.User{ID: 1})
+	})
+	RegisterMetadataEndpoints(api, uc)
+
+	return r
+}
+
+func TestRefreshMetadata_Success_200(t *testing.T) {
+	uc := new(mock.MetadataUseCaseMock)
+	r := newMetadataRouter(uc)
+
+	uc.On("RefreshMetadata", uint(1), uint(7)).Return(&models.Bookmark{ID: 7, Title: "Example", SiteName: "example.com"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks/7/metadata", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"site_name\":\"example.com\"")
+}
+
+func TestRefreshMetadata_NotFound_404(t *testing.T) {
+	uc := new(mock.MetadataUseCaseMock)
+	r := newMetadataRouter(uc)
+
+	uc.On("RefreshMetadata", uint(1), uint(7)).Return((*models.Bookmark)(nil), bookmark.ErrBookmarkNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks/7/metadata", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
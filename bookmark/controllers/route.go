@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+// RegisterHTTPEndpoints mounts the bookmark API on a group that is already
+// guarded by the auth middleware.
+func RegisterHTTPEndpoints(router *gin.RouterGroup, uc services.UseCase, exportUC services.ExportUseCase, log *slog.Logger) {
+	h := NewHandler(uc)
+	e := NewExportHandler(exportUC, log)
+
+	bookmarkEndpoints := router.Group("/bookmarks")
+	{
+		bookmarkEndpoints.GET("", h.List)
+		bookmarkEndpoints.POST("", h.Create)
+		bookmarkEndpoints.PATCH("", h.BulkUpdateState)
+		bookmarkEndpoints.GET("/export", e.Export)
+		bookmarkEndpoints.GET("/:id", h.Get)
+		bookmarkEndpoints.PUT("/:id", h.Update)
+		bookmarkEndpoints.PATCH("/:id", h.UpdateState)
+		bookmarkEndpoints.DELETE("/:id", h.Delete)
+		bookmarkEndpoints.GET("/:id/content", h.Content)
+	}
+
+	router.GET("/queue", h.Queue)
+}
+
+func RegisterTagEndpoints(router *gin.RouterGroup, uc services.TagUseCase) {
+	h := NewTagHandler(uc)
+
+	router.POST("/bookmarks/:id/tags", h.AddTags)
+	router.DELETE("/bookmarks/:id/tags/:tag", h.RemoveTag)
+
+	tagEndpoints := router.Group("/tags")
+	{
+		tagEndpoints.GET("", h.List)
+		tagEndpoints.POST("/merge", h.Merge)
+		tagEndpoints.PUT("/:id", h.Rename)
+		tagEndpoints.DELETE("/:id", h.Delete)
+	}
+}
+
+func RegisterCollectionEndpoints(router *gin.RouterGroup, uc services.CollectionUseCase) {
+	h := NewCollectionHandler(uc)
+
+	router.PUT("/bookmarks/:id/collection", h.SetBookmarkCollection)
+
+	collectionEndpoints := router.Group("/collections")
+	{
+		collectionEndpoints.GET("", h.List)
+		collectionEndpoints.POST("", h.Create)
+		collectionEndpoints.GET("/:id", h.Get)
+		collectionEndpoints.PUT("/:id", h.Rename)
+		collectionEndpoints.POST("/:id/move", h.Move)
+		collectionEndpoints.DELETE("/:id", h.Delete)
+	}
+}
+
+func RegisterShareEndpoints(router *gin.RouterGroup, uc services.ShareUseCase) {
+	h := NewShareHandler(uc)
+
+	shareEndpoints := router.Group("/shares")
+	{
+		shareEndpoints.GET("", h.List)
+		shareEndpoints.POST("", h.Create)
+		shareEndpoints.DELETE("/:id", h.Revoke)
+	}
+}
+
+// RegisterPublicShareEndpoints mounts the share links themselves, outside
+// the auth middleware.
+func RegisterPublicShareEndpoints(router *gin.Engine, uc services.ShareUseCase) {
+	h := NewShareHandler(uc)
+
+	router.GET("/s/:token", h.View)
+}
+
+func RegisterFeedEndpoints(router *gin.RouterGroup, uc services.FeedUseCase) {
+	h := NewFeedHandler(uc)
+
+	feedEndpoints := router.Group("/feeds")
+	{
+		feedEndpoints.GET("", h.List)
+		feedEndpoints.POST("", h.Create)
+		feedEndpoints.DELETE("/:id", h.Revoke)
+	}
+}
+
+// RegisterPublicFeedEndpoints mounts the feeds themselves, outside the auth
+// middleware; the token in the path is what lets a reader in.
+func RegisterPublicFeedEndpoints(router *gin.Engine, uc services.FeedUseCase) {
+	h := NewFeedHandler(uc)
+
+	router.GET("/feeds/:token/:format", h.View)
+}
+
+func RegisterSearchEndpoints(router *gin.RouterGroup, uc services.SearchUseCase) {
+	h := NewSearchHandler(uc)
+
+	router.GET("/search", h.Search)
+}
+
+func RegisterMetadataEndpoints(router *gin.RouterGroup, uc services.MetadataUseCase) {
+	h := NewMetadataHandler(uc)
+
+	router.POST("/bookmarks/:id/metadata", h.Refresh)
+}
+
+func RegisterImportEndpoints(router *gin.RouterGroup, uc services.ImportUseCase) {
+	h := NewImportHandler(uc)
+
+	importEndpoints := router.Group("/imports")
+	{
+		importEndpoints.GET("", h.List)
+		importEndpoints.POST("", h.Create)
+		importEndpoints.GET("/:id", h.Get)
+	}
+}
+
+func RegisterHighlightEndpoints(router *gin.RouterGroup, uc services.HighlightUseCase, log *slog.Logger) {
+	h := NewHighlightHandler(uc, log)
+
+	router.GET("/bookmarks/:id/highlights", h.ListBookmark)
+	router.POST("/bookmarks/:id/highlights", h.Create)
+
+	highlightEndpoints := router.Group("/highlights")
+	{
+		highlightEndpoints.GET("", h.List)
+		highlightEndpoints.PUT("/:id", h.Update)
+		highlightEndpoints.DELETE("/:id", h.Delete)
+	}
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSearchRouter(uc *mock.SearchUseCaseMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		c.Set(authservices.CtxUserKey, &authmodels.User{ID: 1})
+	})
+	RegisterSearchEndpoints(api, uc)
+
+	return r
+}
+
+func TestSearch_Success_200(t *testing.T) {
+	uc := new(mock.SearchUseCaseMock)
+	r := newSearchRouter(uc)
+
+	uc.On("Search", uint(1), models.SearchInput{Q: "clean", Page: 2, Limit: 5}).Return(&models.SearchResponse{
+		Results: []models.SearchResult{{Bookmark: models.Bookmark{ID: 7}, Score: 1, Snippet: "<mark>clean</mark>"}},
+		Total:   6,
+		Page:    2,
+		Limit:   5,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/search?q=clean&page=2&limit=5", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"total\":6")
+	assert.Contains(t, w.Body.String(), "\"snippet\":\"\\u003cmark\\u003eclean\\u003c/mark\\u003e\"")
+}
+
+func TestSearch_Failed_400(t *testing.T) {
+	uc := new(mock.SearchUseCaseMock)
+	r := newSearchRouter(uc)
+
+	uc.On("Search", uint(1), models.SearchInput{}).Return((*models.SearchResponse)(nil), bookmark.ErrDataTidakLengkap)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/search", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+}
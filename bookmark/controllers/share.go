@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+const sharePasswordHeader = "X-Share-Password"
+
+type ShareHandler struct {
+	useCase services.ShareUseCase
+}
+
+func NewShareHandler(useCase services.ShareUseCase) *ShareHandler {
+	return &ShareHandler{
+		useCase: useCase,
+	}
+}
+
+func (h *ShareHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	inp := new(models.ShareInput)
+	if err := c.BindJSON(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	share, err := h.useCase.CreateShare(c.Request.Context(), userID, *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+func (h *ShareHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	shares, err := h.useCase.ListShares(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ShareListResponse{Shares: shares})
+}
+
+func (h *ShareHandler) Revoke(c *gin.Context) {
+	userID, id, ok := currentUserAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.RevokeShare(c.Request.Context(), userID, id); err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BookmarkResponse{Message: "Link berhasil dicabut"})
+}
+
+// View is the public side of a share link and needs no account. The
+// password, if the link has one, comes in the X-Share-Password header.
+func (h *ShareHandler) View(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+
+	inp := new(models.ShareViewInput)
+	if err := c.ShouldBindQuery(inp); err != nil {
+		c.JSON(http.StatusBadRequest, models.BookmarkResponse{Message: bookmark.ErrBadRequest.Error()})
+		return
+	}
+
+	share, err := h.useCase.ViewShare(c.Request.Context(), c.Param("token"), c.GetHeader(sharePasswordHeader), *inp)
+	if err != nil {
+		c.JSON(errorStatus(err), models.BookmarkResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, share)
+}
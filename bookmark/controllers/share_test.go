@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newShareRouter(uc *mock.ShareUseCaseMock, user *authmodels.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		if user != nil {
+			c.Set(authservices.CtxUserKey, user)
+		}
+	})
+	RegisterShareEndpoints(api, uc)
+	RegisterPublicShareEndpoints(r, uc)
+
+	return r
+}
+
+func TestCreateShare_Success_201(t *testing.T) {
+	uc := new(mock.ShareUseCaseMock)
+	r := newShareRouter(uc, &authmodels.User{ID: 1})
+
+	bookmarkID := uint(7)
+	uc.On("CreateShare", uint(1), models.ShareInput{BookmarkID: &bookmarkID, Password: "rahasia"}).
+		Return(&models.Share{ID: 3, Token: "abc", BookmarkID: &bookmarkID, Protected: true, PasswordSalt: "salt", PasswordHash: "hash"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/shares", bytes.NewBufferString(`{"bookmark_id":7,"password":"rahasia"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+	assert.Contains(t, w.Body.String(), "\"token\":\"abc\"")
+	assert.Contains(t, w.Body.String(), "\"protected\":true")
+	assert.NotContains(t, w.Body.String(), "hash")
+	assert.NotContains(t, w.Body.String(), "salt")
+}
+
+func TestRevokeShare_NotFound_404(t *testing.T) {
+	uc := new(mock.ShareUseCaseMock)
+	r := newShareRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("RevokeShare", uint(1), uint(3)).Return(bookmark.ErrShareNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/shares/3", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestViewShare_Public_200(t *testing.T) {
+	uc := new(mock.ShareUseCaseMock)
+	r := newShareRouter(uc, nil)
+
+	uc.On("ViewShare", "abc", "rahasia", models.ShareViewInput{Limit: 10}).
+		Return(&models.PublicShare{Bookmark: &models.PublicBookmark{URL: "https://go.dev/", Title: "Go"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/s/abc?limit=10", nil)
+	req.Header.Set("X-Share-Password", "rahasia")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "noindex", w.Header().Get("X-Robots-Tag"))
+	assert.Contains(t, w.Body.String(), "\"title\":\"Go\"")
+	assert.NotContains(t, w.Body.String(), "collection")
+}
+
+func TestViewShare_Errors(t *testing.T) {
+	uc := new(mock.ShareUseCaseMock)
+	r := newShareRouter(uc, nil)
+
+	uc.On("ViewShare", "protected", "", models.ShareViewInput{}).Return((*models.PublicShare)(nil), bookmark.ErrSharePassword)
+	uc.On("ViewShare", "locked", "", models.ShareViewInput{}).Return((*models.PublicShare)(nil), bookmark.ErrShareLocked)
+	uc.On("ViewShare", "old", "", models.ShareViewInput{}).Return((*models.PublicShare)(nil), bookmark.ErrShareExpired)
+
+	for path, code := range map[string]int{"/s/protected": 401, "/s/locked": 429, "/s/old": 410} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, code, w.Code, path)
+	}
+}
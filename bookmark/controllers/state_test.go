@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestUpdateState_Success_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("UpdateState", uint(1), uint(7), testifymock.MatchedBy(func(inp models.StateInput) bool {
+		return inp.Status != nil && *inp.Status == "read" && inp.Favorite == nil && inp.Progress != nil && *inp.Progress == 100
+	})).Return(&models.Bookmark{ID: 7, Status: models.StatusRead, Progress: 100}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/api/bookmarks/7", bytes.NewBufferString(`{"status":"read","progress":100}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"status\":\"read\"")
+}
+
+func TestUpdateState_Failed_400(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("UpdateState", uint(1), uint(7), testifymock.Anything).Return((*models.Bookmark)(nil), bookmark.ErrInvalidState)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/api/bookmarks/7", bytes.NewBufferString(`{"status":"gone"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "{\"message\":\"status bookmark tidak valid\"}", w.Body.String())
+}
+
+func TestBulkUpdateState_Success_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("BulkUpdateState", uint(1), testifymock.MatchedBy(func(inp models.BulkStateInput) bool {
+		return len(inp.IDs) == 2 && inp.Status != nil && *inp.Status == "archived"
+	})).Return(&models.BulkStateResponse{Updated: 2}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/api/bookmarks", bytes.NewBufferString(`{"ids":[7,8],"status":"archived"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "{\"updated\":2}", w.Body.String())
+}
+
+func TestBulkUpdateState_NotFound_404(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("BulkUpdateState", uint(1), testifymock.Anything).Return((*models.BulkStateResponse)(nil), bookmark.ErrBookmarkNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/api/bookmarks", bytes.NewBufferString(`{"ids":[7,9],"favorite":true}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestQueue_Success_200(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, &authmodels.User{ID: 1})
+
+	uc.On("Queue", uint(1), models.QueueInput{Order: "shortest", MaxTime: 10}).Return([]models.Bookmark{{ID: 7}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/queue?order=shortest&max_time=10", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "\"id\":7")
+}
+
+func TestQueue_Unauthorized_401(t *testing.T) {
+	uc := new(mock.BookmarkUseCaseMock)
+	r := newRouter(uc, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/queue", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	authmodels "github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	authservices "github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTagRouter(uc *mock.TagUseCaseMock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	api := r.Group("/api", func(c *gin.Context) {
+		c.Set(authservices.CtxUserKey, &authmodels.User{ID: 1})
+	})
+	RegisterTagEndpoints(api, uc)
+
+	return r
+}
+
+func TestAddTags_Success_200(t *testing.T) {
+	uc := new(mock.TagUseCaseMock)
+	r := newTagRouter(uc)
+
+	inp := models.TagsInput{Tags: []string{"golang"}}
+	body, err := json.Marshal(inp)
+	assert.NoError(t, err)
+
+	uc.On("AddTags", uint(1), uint(7), inp).Return([]models.Tag{{ID: 3, Name: "golang"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/bookmarks/7/tags", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "{\"tags\":[{\"id\":3,\"name\":\"golang\"}]}", w.Body.String())
+}
+
+func TestRemoveTag_Failed_404(t *testing.T) {
+	uc := new(mock.TagUseCaseMock)
+	r := newTagRouter(uc)
+
+	uc.On("RemoveTag", uint(1), uint(7), "golang").Return(bookmark.ErrTagNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/bookmarks/7/tags/golang", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "{\"message\":\"tag not found\"}", w.Body.String())
+}
+
+func TestListTags_Success_200(t *testing.T) {
+	uc := new(mock.TagUseCaseMock)
+	r := newTagRouter(uc)
+
+	uc.On("ListTags", uint(1)).Return([]models.TagCount{{ID: 3, Name: "golang", Count: 2}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/tags", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "{\"tags\":[{\"id\":3,\"name\":\"golang\",\"count\":2}]}", w.Body.String())
+}
+
+func TestRenameTag_Failed_409(t *testing.T) {
+	uc := new(mock.TagUseCaseMock)
+	r := newTagRouter(uc)
+
+	inp := models.RenameTagInput{Name: "golang"}
+	body, err := json.Marshal(inp)
+	assert.NoError(t, err)
+
+	uc.On("RenameTag", uint(1), uint(3), inp).Return((*models.Tag)(nil), bookmark.ErrTagDuplicate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/tags/3", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestMergeTags_Success_200(t *testing.T) {
+	uc := new(mock.TagUseCaseMock)
+	r := newTagRouter(uc)
+
+	inp := models.MergeTagsInput{Sources: []string{"go"}, Target: "golang"}
+	body, err := json.Marshal(inp)
+	assert.NoError(t, err)
+
+	uc.On("MergeTags", uint(1), inp).Return(&models.Tag{ID: 3, Name: "golang"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/tags/merge", bytes.NewBuffer(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "{\"id\":3,\"name\":\"golang\"}", w.Body.String())
+}
@@ -0,0 +1,44 @@
+package bookmark
+
+import "errors"
+
+var (
+	ErrBookmarkNotFound    = errors.New("bookmark not found")
+	ErrBadRequest          = errors.New("bad request bro")
+	ErrUnauthorized        = errors.New("user unauthorized")
+	ErrDataTidakLengkap    = errors.New("data tidak lengkap")
+	ErrInvalidURL          = errors.New("url tidak valid")
+	ErrTagNotFound         = errors.New("tag not found")
+	ErrTagDuplicate        = errors.New("nama tag sudah digunakan")
+	ErrInvalidTag          = errors.New("nama tag tidak valid")
+	ErrAddressBlocked      = errors.New("alamat tujuan tidak diizinkan")
+	ErrNotHTML             = errors.New("halaman bukan html")
+	ErrFetchFailed         = errors.New("gagal mengambil halaman")
+	ErrNoArticle           = errors.New("artikel tidak ditemukan")
+	ErrContentNotFound     = errors.New("konten belum tersedia")
+	ErrImportNotFound      = errors.New("import not found")
+	ErrImportFormat        = errors.New("format import tidak dikenali")
+	ErrImportEmpty         = errors.New("file import tidak berisi bookmark")
+	ErrFileTooLarge        = errors.New("file terlalu besar")
+	ErrImportRunning       = errors.New("import lain masih berjalan")
+	ErrExportFormat        = errors.New("format export tidak dikenali")
+	ErrInvalidState        = errors.New("status bookmark tidak valid")
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrCollectionDuplicate = errors.New("nama collection sudah digunakan")
+	ErrInvalidCollection   = errors.New("nama collection tidak valid")
+	ErrCollectionCycle     = errors.New("collection tidak bisa dipindah ke dalam dirinya sendiri")
+	ErrCollectionDepth     = errors.New("collection terlalu dalam")
+	ErrShareNotFound       = errors.New("share not found")
+	ErrShareExpired        = errors.New("link sudah kedaluwarsa")
+	ErrSharePassword       = errors.New("password salah")
+	ErrShareLocked         = errors.New("terlalu banyak password salah, coba lagi nanti")
+	ErrShareTarget         = errors.New("pilih satu bookmark atau collection")
+	ErrInvalidExpiry       = errors.New("waktu kedaluwarsa sudah lewat")
+	ErrInvalidLinkStatus   = errors.New("status link tidak valid")
+	ErrBookmarkDuplicate   = errors.New("bookmark sudah ada")
+	ErrHighlightNotFound   = errors.New("highlight not found")
+	ErrInvalidHighlight    = errors.New("highlight tidak valid")
+	ErrFeedNotFound        = errors.New("feed not found")
+	ErrFeedTarget          = errors.New("pilih satu tag atau collection saja")
+	ErrFeedFormat          = errors.New("format feed tidak dikenali")
+)
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+type Bookmark struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index;uniqueIndex:idx_bookmarks_user_url_hash" json:"-"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Notes     string    `json:"notes"`
+	Tags      []Tag     `gorm:"many2many:bookmark_tags" json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// What duplicates are told apart by, see urlnorm. URLHash keeps it
+	// unique per user and stays nil until it is known. CanonicalHash is the
+	// same for the canonical link of the page, once it is fetched.
+	NormalizedURL string  `json:"normalized_url"`
+	URLHash       *string `gorm:"size:64;uniqueIndex:idx_bookmarks_user_url_hash" json:"-"`
+	CanonicalHash *string `gorm:"size:64;index" json:"-"`
+
+	// Filled in from the page itself after the bookmark is saved.
+	Description  string     `gorm:"type:text" json:"description"`
+	CanonicalURL string     `json:"canonical_url"`
+	ImageURL     string     `json:"image_url"`
+	FaviconURL   string     `json:"favicon_url"`
+	SiteName     string     `json:"site_name"`
+	FetchedAt    *time.Time `json:"fetched_at"`
+	FetchError   string     `json:"fetch_error,omitempty"`
+	WordCount    int        `json:"word_count"`
+	ReadingTime  int        `json:"reading_time"`
+
+	// Reading state, changed through the state endpoints.
+	Status      string     `gorm:"size:16;default:unread;index" json:"status"`
+	Favorite    bool       `json:"favorite"`
+	Priority    int        `json:"priority"`
+	Progress    int        `json:"progress"`
+	ReadAt      *time.Time `json:"read_at"`
+	ArchivedAt  *time.Time `json:"archived_at"`
+	FavoritedAt *time.Time `json:"favorited_at"`
+
+	CollectionID *uint `gorm:"index" json:"collection_id"`
+
+	// Public bookmarks show up in the feeds of their owner.
+	Public bool `gorm:"index" json:"public"`
+
+	// Written by the link checker. LinkFailures counts the checks in a row
+	// that got no answer or a server error.
+	LinkStatus    string     `gorm:"size:16;index" json:"link_status"`
+	LinkCode      int        `json:"link_code"`
+	RedirectURL   string     `json:"redirect_url,omitempty"`
+	LinkError     string     `json:"link_error,omitempty"`
+	LinkFailures  int        `gorm:"not null;default:0" json:"-"`
+	LinkCheckedAt *time.Time `gorm:"index" json:"link_checked_at"`
+}
+
+type BookmarkInput struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Notes string `json:"notes"`
+
+	// Public is left as it is when nil.
+	Public *bool `json:"public"`
+}
+
+type ListInput struct {
+	Limit    int    `form:"limit"`
+	Offset   int    `form:"offset"`
+	Tags     string `form:"tags"`
+	TagMode  string `form:"tag_mode"`
+	Status   string `form:"status"`
+	Favorite bool   `form:"favorite"`
+	Link     string `form:"link"`
+	Public   bool   `form:"public"`
+
+	// Collection limits the list to one collection, and to everything below
+	// it as well when Recursive is set.
+	Collection uint `form:"collection"`
+	Recursive  bool `form:"recursive"`
+
+	// TagNames is the normalized form of Tags filled in by the usecase.
+	TagNames []string `form:"-"`
+}
+
+type BookmarkResponse struct {
+	Message string `json:"message"`
+}
+
+type BookmarkListResponse struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+const (
+	CollectionDeleteMove   = "move"
+	CollectionDeleteDelete = "delete"
+)
+
+const MaxCollectionDepth = 10
+
+// Collection is a folder of bookmarks. Path lists the ids from the root
+// down to the collection itself, like "/1/5/9/", so that a whole subtree
+// can be found with a prefix match.
+type Collection struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index:idx_collections_user_path" json:"-"`
+	ParentID  *uint     `gorm:"index" json:"parent_id"`
+	Name      string    `gorm:"size:100" json:"name"`
+	Path      string    `gorm:"size:255;index:idx_collections_user_path" json:"path"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CollectionNode struct {
+	Collection
+	BookmarkCount int64            `json:"bookmark_count"`
+	Children      []CollectionNode `json:"children"`
+}
+
+type CollectionInput struct {
+	Name     string `json:"name"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+type RenameCollectionInput struct {
+	Name string `json:"name"`
+}
+
+// MoveCollectionInput puts a collection under ParentID, or at the top when
+// it is left out or 0, at Position among its new siblings. Without a
+// position it goes last.
+type MoveCollectionInput struct {
+	ParentID *uint `json:"parent_id"`
+	Position *int  `json:"position"`
+}
+
+type BookmarkCollectionInput struct {
+	CollectionID *uint `json:"collection_id"`
+}
+
+type CollectionListResponse struct {
+	Collections []CollectionNode `json:"collections"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Article is the readable part of a page as found by the extractor.
+type Article struct {
+	HTML        string
+	Text        string
+	WordCount   int
+	ReadingTime int
+}
+
+// BookmarkContent is the stored reader-mode copy of a bookmarked page, so it
+// can still be read after the page goes away.
+type BookmarkContent struct {
+	BookmarkID  uint      `gorm:"primaryKey;autoIncrement:false" json:"bookmark_id"`
+	UserID      uint      `gorm:"index" json:"-"`
+	HTML        string    `gorm:"size:16777215" json:"html"`
+	Text        string    `gorm:"size:16777215" json:"text"`
+	WordCount   int       `json:"word_count"`
+	ReadingTime int       `json:"reading_time"`
+	ExtractedAt time.Time `json:"extracted_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+const (
+	FeedFormatAtom = "atom"
+	FeedFormatRSS  = "rss"
+
+	// FeedSize is how many of the newest bookmarks a feed carries.
+	FeedSize = 50
+)
+
+// Feed lets a feed reader follow the public bookmarks of a user, those of
+// them with one tag, or everything in one collection, through its Token.
+type Feed struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"index" json:"-"`
+	Token        string     `gorm:"size:64;uniqueIndex" json:"token"`
+	TagID        *uint      `gorm:"index" json:"tag_id"`
+	CollectionID *uint      `gorm:"index" json:"collection_id"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	// ETag and ChangedAt are what the feed served last, so that a bookmark
+	// leaving it can be dated.
+	ETag      string     `gorm:"column:etag;size:32;not null;default:''" json:"-"`
+	ChangedAt *time.Time `json:"-"`
+}
+
+type FeedInput struct {
+	Tag          string `json:"tag"`
+	CollectionID *uint  `json:"collection_id"`
+}
+
+type FeedListResponse struct {
+	Feeds []Feed `json:"feeds"`
+}
+
+// FeedDocument is a feed ready to be written out. Updated is when its
+// newest bookmark changed, or when a bookmark left it if that was later,
+// and ETag changes with anything in it.
+type FeedDocument struct {
+	ID        string
+	Title     string
+	SelfURL   string
+	Updated   time.Time
+	ETag      string
+	Bookmarks []FeedEntry
+}
+
+type FeedEntry struct {
+	ID          uint
+	URL         string
+	Title       string
+	Description string
+	SiteName    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
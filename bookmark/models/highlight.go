@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+const (
+	HighlightColorYellow = "yellow"
+	HighlightColorGreen  = "green"
+	HighlightColorBlue   = "blue"
+	HighlightColorPink   = "pink"
+	HighlightColorPurple = "purple"
+)
+
+const (
+	// Highlights and their notes are limited to this many characters.
+	MaxHighlightLength = 10000
+	MaxHighlightNote   = 10000
+	// HighlightContext is how much text around the quote is kept, in
+	// characters on each side.
+	HighlightContext = 32
+)
+
+// Highlight marks a passage in the reader copy of a bookmark. It is anchored
+// twice: by StartOffset and EndOffset, character offsets into the article
+// text, and by the quote with some of the text around it, which finds the
+// passage again when the article is extracted anew and the offsets shift.
+type Highlight struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"index" json:"-"`
+	BookmarkID  uint      `gorm:"index" json:"bookmark_id"`
+	StartOffset int       `json:"start"`
+	EndOffset   int       `json:"end"`
+	Quote       string    `gorm:"type:text" json:"quote"`
+	Prefix      string    `gorm:"size:255" json:"prefix"`
+	Suffix      string    `gorm:"size:255" json:"suffix"`
+	Note        string    `gorm:"type:text" json:"note"`
+	Color       string    `gorm:"size:16" json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Orphaned is set when the quote is no longer in the article.
+	Orphaned bool `gorm:"-" json:"orphaned"`
+}
+
+// HighlightInput picks a passage by its offsets in the article text, as
+// served by the content endpoint.
+type HighlightInput struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Note  string `json:"note"`
+	Color string `json:"color"`
+}
+
+// HighlightUpdateInput changes the note or color of a highlight. Fields that
+// are left out keep their value.
+type HighlightUpdateInput struct {
+	Note  *string `json:"note"`
+	Color *string `json:"color"`
+}
+
+type HighlightListInput struct {
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+	Format string `form:"format"`
+}
+
+type HighlightListResponse struct {
+	Highlights []Highlight `json:"highlights"`
+}
+
+// HighlightGroup is a bookmark with its highlights, in article order.
+type HighlightGroup struct {
+	Bookmark   Bookmark
+	Highlights []Highlight
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+const (
+	ImportFormatNetscape = "netscape"
+	ImportFormatPocket   = "pocket"
+	ImportFormatChrome   = "chrome"
+	ImportFormatFirefox  = "firefox"
+	ImportFormatCSV      = "csv"
+
+	ExportFormatHTML     = "html"
+	ExportFormatJSON     = "json"
+	ExportFormatCSV      = "csv"
+	ExportFormatMarkdown = "md"
+
+	ImportStatusRunning = "running"
+	ImportStatusDone    = "done"
+	ImportStatusFailed  = "failed"
+
+	// How folders of the imported file end up on the bookmarks.
+	FolderModeTags = "tags"
+	FolderModePath = "path"
+	FolderModeNone = "none"
+)
+
+// ImportItem is one bookmark read from an import file. Row is its position
+// in the file, for the error report.
+type ImportItem struct {
+	Row       int
+	URL       string
+	Title     string
+	Notes     string
+	Tags      []string
+	Folders   []string
+	CreatedAt *time.Time
+}
+
+type ImportJob struct {
+	ID         uint          `gorm:"primaryKey" json:"id"`
+	UserID     uint          `gorm:"index" json:"-"`
+	Format     string        `gorm:"size:20" json:"format"`
+	FolderMode string        `gorm:"size:20" json:"folder_mode"`
+	Status     string        `gorm:"size:20" json:"status"`
+	Total      int           `json:"total"`
+	Processed  int           `json:"processed"`
+	Imported   int           `json:"imported"`
+	Duplicates int           `json:"duplicates"`
+	Failed     int           `json:"failed"`
+	Error      string        `json:"error,omitempty"`
+	Errors     []ImportError `gorm:"foreignKey:JobID" json:"errors,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	FinishedAt *time.Time    `json:"finished_at"`
+}
+
+type ImportError struct {
+	ID    uint   `gorm:"primaryKey" json:"-"`
+	JobID uint   `gorm:"index" json:"-"`
+	Row   int    `gorm:"column:line" json:"row"`
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+type ImportInput struct {
+	Format     string `form:"format"`
+	FolderMode string `form:"folder_mode"`
+}
+
+type ImportListResponse struct {
+	Imports []ImportJob `json:"imports"`
+}
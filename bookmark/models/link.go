@@ -0,0 +1,18 @@
+package models
+
+const (
+	LinkStatusOK     = "ok"
+	LinkStatusMoved  = "moved"
+	LinkStatusBroken = "broken"
+)
+
+// LinkCheck is the answer a URL gave to the link checker. RedirectURL is
+// where the redirects ended, empty when there were none, and Permanent is
+// set when one of them was a 301 or 308. StatusCode is 0 when the request
+// itself failed, with the reason in Error.
+type LinkCheck struct {
+	StatusCode  int
+	RedirectURL string
+	Permanent   bool
+	Error       string
+}
@@ -0,0 +1,17 @@
+package models
+
+type PageMetadata struct {
+	Title        string
+	Description  string
+	CanonicalURL string
+	ImageURL     string
+	FaviconURL   string
+	SiteName     string
+}
+
+// FetchedPage is what the fetcher found on a page. Article is nil when no
+// readable content could be extracted.
+type FetchedPage struct {
+	Metadata *PageMetadata
+	Article  *Article
+}
@@ -0,0 +1,38 @@
+package models
+
+// SearchDocument is the text of a bookmark as seen by the search index.
+// Content is the text of the article extracted from its page.
+type SearchDocument struct {
+	BookmarkID uint   `gorm:"primaryKey;autoIncrement:false"`
+	UserID     uint   `gorm:"index"`
+	Title      string `gorm:"type:text"`
+	URL        string `gorm:"type:text"`
+	Notes      string `gorm:"type:text"`
+	Tags       string `gorm:"type:text"`
+	Content    string `gorm:"size:16777215"`
+}
+
+type SearchHit struct {
+	BookmarkID uint
+	Score      float64
+	Snippet    string
+}
+
+type SearchInput struct {
+	Q     string `form:"q"`
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+}
+
+type SearchResult struct {
+	Bookmark Bookmark `json:"bookmark"`
+	Score    float64  `json:"score"`
+	Snippet  string   `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+	Total   int64          `json:"total"`
+	Page    int            `json:"page"`
+	Limit   int            `json:"limit"`
+}
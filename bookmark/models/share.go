@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Share is a public read-only link to one bookmark or one collection,
+// reached through its Token. PasswordFailures counts the wrong passwords
+// since the last right one or lockout; LockedUntil refuses every password
+// until it passes.
+type Share struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	UserID           uint       `gorm:"index" json:"-"`
+	Token            string     `gorm:"size:64;uniqueIndex" json:"token"`
+	BookmarkID       *uint      `gorm:"index" json:"bookmark_id"`
+	CollectionID     *uint      `gorm:"index" json:"collection_id"`
+	Protected        bool       `json:"protected"`
+	PasswordSalt     string     `gorm:"size:32" json:"-"`
+	PasswordHash     string     `gorm:"size:64" json:"-"`
+	PasswordFailures int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil      *time.Time `json:"-"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	Views            int64      `json:"views"`
+	LastViewedAt     *time.Time `json:"last_viewed_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type ShareInput struct {
+	BookmarkID   *uint      `json:"bookmark_id"`
+	CollectionID *uint      `json:"collection_id"`
+	Password     string     `json:"password"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
+
+type ShareListResponse struct {
+	Shares []Share `json:"shares"`
+}
+
+type ShareViewInput struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// PublicBookmark is what a share shows of a bookmark; notes and tags stay
+// private.
+type PublicBookmark struct {
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"image_url"`
+	SiteName    string    `json:"site_name"`
+	ReadingTime int       `json:"reading_time"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PublicCollection struct {
+	Name      string           `json:"name"`
+	Bookmarks []PublicBookmark `json:"bookmarks"`
+}
+
+type PublicShare struct {
+	Bookmark   *PublicBookmark   `json:"bookmark,omitempty"`
+	Collection *PublicCollection `json:"collection,omitempty"`
+	ExpiresAt  *time.Time        `json:"expires_at"`
+}
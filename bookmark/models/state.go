@@ -0,0 +1,42 @@
+package models
+
+const (
+	StatusUnread   = "unread"
+	StatusRead     = "read"
+	StatusArchived = "archived"
+)
+
+const (
+	QueueOrderPriority = "priority"
+	QueueOrderOldest   = "oldest"
+	QueueOrderNewest   = "newest"
+	QueueOrderShortest = "shortest"
+	QueueOrderLongest  = "longest"
+)
+
+const MaxPriority = 3
+
+// StateInput changes the reading state of a bookmark. Fields that are left
+// out keep their value.
+type StateInput struct {
+	Status   *string `json:"status"`
+	Favorite *bool   `json:"favorite"`
+	Progress *int    `json:"progress"`
+	Priority *int    `json:"priority"`
+}
+
+type BulkStateInput struct {
+	IDs []uint `json:"ids"`
+	StateInput
+}
+
+type BulkStateResponse struct {
+	Updated int `json:"updated"`
+}
+
+type QueueInput struct {
+	Order   string `form:"order"`
+	MaxTime int    `form:"max_time"`
+	Limit   int    `form:"limit"`
+	Offset  int    `form:"offset"`
+}
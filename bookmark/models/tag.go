@@ -0,0 +1,45 @@
+package models
+
+const (
+	TagModeAny = "any"
+	TagModeAll = "all"
+)
+
+type Tag struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"uniqueIndex:idx_tags_user_name" json:"-"`
+	Name   string `gorm:"size:100;uniqueIndex:idx_tags_user_name" json:"name"`
+}
+
+// BookmarkTag is the join row behind Bookmark.Tags.
+type BookmarkTag struct {
+	BookmarkID uint `gorm:"primaryKey"`
+	TagID      uint `gorm:"primaryKey"`
+}
+
+func (BookmarkTag) TableName() string {
+	return "bookmark_tags"
+}
+
+type TagCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type TagsInput struct {
+	Tags []string `json:"tags"`
+}
+
+type RenameTagInput struct {
+	Name string `json:"name"`
+}
+
+type MergeTagsInput struct {
+	Sources []string `json:"sources"`
+	Target  string   `json:"target"`
+}
+
+type TagListResponse struct {
+	Tags []TagCount `json:"tags"`
+}
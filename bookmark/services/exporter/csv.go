@@ -0,0 +1,52 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+var csvHeader = []string{"url", "title", "notes", "tags", "created", "updated"}
+
+type csvEncoder struct {
+	w       *csv.Writer
+	started bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) begin() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvEncoder) Encode(bm *models.Bookmark) error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+
+	return e.w.Write([]string{
+		bm.URL,
+		bm.Title,
+		bm.Notes,
+		strings.Join(tagNames(bm), ","),
+		bm.CreatedAt.UTC().Format(time.RFC3339),
+		bm.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,46 @@
+package exporter
+
+import (
+	"io"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+type Exporter struct{}
+
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// NewEncoder returns an encoder writing format to w. Nothing is written
+// until the first bookmark or Close, so an unknown format leaves w untouched.
+func (e *Exporter) NewEncoder(format string, w io.Writer) (services.BookmarkEncoder, error) {
+	switch format {
+	case models.ExportFormatHTML:
+		return &netscapeEncoder{w: w}, nil
+	case models.ExportFormatJSON:
+		return &jsonEncoder{w: w}, nil
+	case models.ExportFormatCSV:
+		return newCSVEncoder(w), nil
+	case models.ExportFormatMarkdown:
+		return &markdownEncoder{w: w}, nil
+	}
+	return nil, bookmark.ErrExportFormat
+}
+
+func tagNames(bm *models.Bookmark) []string {
+	names := make([]string, 0, len(bm.Tags))
+	for _, tag := range bm.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func title(bm *models.Bookmark) string {
+	if bm.Title != "" {
+		return bm.Title
+	}
+	return bm.URL
+}
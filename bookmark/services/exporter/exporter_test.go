@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var created = time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+var testBookmarks = []models.Bookmark{
+	{
+		URL:       "https://go.dev/?a=1&b=2",
+		Title:     `Go <"fast">`,
+		Notes:     "first line\nsecond line",
+		Tags:      []models.Tag{{Name: "golang"}, {Name: "clean code"}},
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+	},
+	{
+		URL:       "https://en.wikipedia.org/wiki/Go_(game)",
+		CreatedAt: created,
+		UpdatedAt: created,
+	},
+}
+
+func encode(t *testing.T, format string, bookmarks []models.Bookmark) string {
+	var buf bytes.Buffer
+	enc, err := NewExporter().NewEncoder(format, &buf)
+	require.NoError(t, err)
+
+	for i := range bookmarks {
+		require.NoError(t, enc.Encode(&bookmarks[i]))
+	}
+	require.NoError(t, enc.Close())
+
+	return buf.String()
+}
+
+func TestExport_HTML_RoundTrip(t *testing.T) {
+	out := encode(t, models.ExportFormatHTML, testBookmarks)
+	assert.Contains(t, out, `<A HREF="https://go.dev/?a=1&amp;b=2" ADD_DATE="1614592800" LAST_MODIFIED="1614596400" TAGS="golang,clean code">Go &lt;&#34;fast&#34;&gt;</A>`)
+
+	_, items, err := importer.NewParser().Parse(models.ImportFormatNetscape, []byte(out))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "https://go.dev/?a=1&b=2", items[0].URL)
+	assert.Equal(t, `Go <"fast">`, items[0].Title)
+	assert.Equal(t, "first line\nsecond line", items[0].Notes)
+	assert.Equal(t, []string{"golang", "clean code"}, items[0].Tags)
+	assert.Equal(t, created, *items[0].CreatedAt)
+	assert.Equal(t, "https://en.wikipedia.org/wiki/Go_(game)", items[1].Title)
+}
+
+func TestExport_CSV_RoundTrip(t *testing.T) {
+	out := encode(t, models.ExportFormatCSV, testBookmarks)
+	assert.True(t, bytes.HasPrefix([]byte(out), []byte("url,title,notes,tags,created,updated\n")))
+
+	_, items, err := importer.NewParser().Parse(models.ImportFormatCSV, []byte(out))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "first line\nsecond line", items[0].Notes)
+	assert.Equal(t, []string{"golang", "clean code"}, items[0].Tags)
+	assert.Equal(t, created, *items[0].CreatedAt)
+	assert.Equal(t, "", items[1].Title)
+}
+
+func TestExport_JSON(t *testing.T) {
+	var decoded []jsonBookmark
+	require.NoError(t, json.Unmarshal([]byte(encode(t, models.ExportFormatJSON, testBookmarks)), &decoded))
+	require.Len(t, decoded, 2)
+	assert.Equal(t, []string{"golang", "clean code"}, decoded[0].Tags)
+	assert.Equal(t, []string{}, decoded[1].Tags)
+	assert.Equal(t, created, decoded[1].CreatedAt)
+
+	assert.Equal(t, "[]\n", encode(t, models.ExportFormatJSON, nil))
+}
+
+func TestExport_Markdown(t *testing.T) {
+	out := encode(t, models.ExportFormatMarkdown, testBookmarks)
+	assert.Equal(t, "# Bookmarks\n\n"+
+		"- [Go &lt;\"fast\">](https://go.dev/?a=1&b=2) - 2021-03-01\n"+
+		"  - Tags: `golang` `clean code`\n"+
+		"  > first line\n"+
+		"  > second line\n"+
+		"- [https://en.wikipedia.org/wiki/Go\\_(game)](<https://en.wikipedia.org/wiki/Go_(game)>) - 2021-03-01\n", out)
+}
+
+func TestExport_Empty(t *testing.T) {
+	_, _, err := importer.NewParser().Parse(models.ImportFormatNetscape, []byte(encode(t, models.ExportFormatHTML, nil)))
+	assert.Equal(t, bookmark.ErrImportEmpty, err)
+
+	assert.Equal(t, "url,title,notes,tags,created,updated\n", encode(t, models.ExportFormatCSV, nil))
+}
+
+func TestExport_UnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewExporter().NewEncoder("pdf", &buf)
+	assert.Equal(t, bookmark.ErrExportFormat, err)
+	assert.Zero(t, buf.Len())
+}
+
+func TestWriteHighlights(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewExporter().WriteHighlights(&buf, []models.HighlightGroup{
+		{
+			Bookmark: testBookmarks[0],
+			Highlights: []models.Highlight{
+				{Quote: "Go is *expressive*,\nconcise", Note: "Keep **this**"},
+				{Quote: "clean"},
+			},
+		},
+		{Bookmark: testBookmarks[1]},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "# Highlights\n"+
+		"\n## [Go &lt;\"fast\">](https://go.dev/?a=1&b=2)\n"+
+		"\n> Go is \\*expressive\\*,\n"+
+		"> concise\n"+
+		"\nKeep **this**\n"+
+		"\n> clean\n"+
+		"\n## [https://en.wikipedia.org/wiki/Go\\_(game)](<https://en.wikipedia.org/wiki/Go_(game)>)\n", buf.String())
+}
+
+func TestWriteFeed(t *testing.T) {
+	feed := &models.FeedDocument{
+		ID:      "https://example.com/feeds/abc",
+		Title:   "Public bookmarks",
+		SelfURL: "https://example.com/feeds/abc/atom",
+		Updated: created,
+		Bookmarks: []models.FeedEntry{
+			{ID: 7, URL: "https://go.dev/?a=1&b=2", Title: "Go <3", Description: "The Go site", CreatedAt: created, UpdatedAt: created},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewExporter().WriteFeed(&buf, models.FeedFormatAtom, feed))
+	atom := buf.String()
+	assert.Contains(t, atom, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, atom, `<link rel="self" type="application/atom+xml" href="https://example.com/feeds/abc/atom"></link>`)
+	assert.Contains(t, atom, "<id>https://example.com/feeds/abc#7</id>")
+	assert.Contains(t, atom, "<title>Go &lt;3</title>")
+	assert.Contains(t, atom, `<link href="https://go.dev/?a=1&amp;b=2"></link>`)
+	assert.Contains(t, atom, "<updated>2021-03-01T10:00:00Z</updated>")
+
+	buf.Reset()
+	require.NoError(t, NewExporter().WriteFeed(&buf, models.FeedFormatRSS, feed))
+	rss := buf.String()
+	assert.Contains(t, rss, `<rss version="2.0">`)
+	assert.Contains(t, rss, `<guid isPermaLink="false">https://example.com/feeds/abc#7</guid>`)
+	assert.Contains(t, rss, "<pubDate>Mon, 01 Mar 2021 10:00:00 +0000</pubDate>")
+
+	assert.Equal(t, bookmark.ErrFeedFormat, NewExporter().WriteFeed(&buf, "json", feed))
+}
@@ -0,0 +1,148 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Link      atomLink `xml:"link"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Summary   string   `xml:"summary,omitempty"`
+	Source    string   `xml:"source>title,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  string      `xml:"author>name"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"http://www.w3.org/2005/Atom link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// WriteFeed writes feed to w as Atom or RSS 2.0. Entries are told apart by
+// the feed ID and the bookmark ID, so they stay the same across edits.
+func (e *Exporter) WriteFeed(w io.Writer, format string, feed *models.FeedDocument) error {
+	var doc interface{}
+	switch format {
+	case models.FeedFormatAtom:
+		doc = atomDocument(feed)
+	case models.FeedFormatRSS:
+		doc = rssDocument(feed)
+	default:
+		return bookmark.ErrFeedFormat
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func atomDocument(feed *models.FeedDocument) *atomFeed {
+	doc := &atomFeed{
+		ID:      feed.ID,
+		Title:   feed.Title,
+		Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Author:  feed.Title,
+		Link:    atomLink{Rel: "self", Type: "application/atom+xml", Href: feed.SelfURL},
+		Entries: make([]atomEntry, 0, len(feed.Bookmarks)),
+	}
+	for _, entry := range feed.Bookmarks {
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:        entryID(feed, entry),
+			Title:     entryTitle(entry),
+			Link:      atomLink{Href: entry.URL},
+			Published: entry.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   entry.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary:   entry.Description,
+			Source:    entry.SiteName,
+		})
+	}
+	return doc
+}
+
+func rssDocument(feed *models.FeedDocument) *rssFeed {
+	doc := &rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.SelfURL,
+			Description:   feed.Title,
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+			Self:          atomLink{Rel: "self", Type: "application/rss+xml", Href: feed.SelfURL},
+			Items:         make([]rssItem, 0, len(feed.Bookmarks)),
+		},
+	}
+	for _, entry := range feed.Bookmarks {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       entryTitle(entry),
+			Link:        entry.URL,
+			Description: entry.Description,
+			GUID:        rssGUID{Value: entryID(feed, entry)},
+			PubDate:     entry.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return doc
+}
+
+func entryID(feed *models.FeedDocument, entry models.FeedEntry) string {
+	return fmt.Sprintf("%s#%d", feed.ID, entry.ID)
+}
+
+func entryTitle(entry models.FeedEntry) string {
+	if entry.Title != "" {
+		return entry.Title
+	}
+	return entry.URL
+}
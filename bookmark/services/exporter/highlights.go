@@ -0,0 +1,41 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+// WriteHighlights writes a Markdown document with a section per bookmark,
+// each highlight quoted and followed by its note. Notes are written as they
+// are, so Markdown in them is kept.
+func (e *Exporter) WriteHighlights(w io.Writer, groups []models.HighlightGroup) error {
+	if _, err := io.WriteString(w, "# Highlights\n"); err != nil {
+		return err
+	}
+
+	for i := range groups {
+		bm := &groups[i].Bookmark
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "\n## [%s](%s)\n", markdownEscaper.Replace(title(bm)), markdownURL(bm.URL))
+
+		for _, h := range groups[i].Highlights {
+			b.WriteString("\n")
+			for _, line := range strings.Split(strings.TrimSpace(h.Quote), "\n") {
+				b.WriteString(strings.TrimRight("> "+markdownEscaper.Replace(strings.TrimRight(line, "\r")), " ") + "\n")
+			}
+			if note := strings.TrimSpace(h.Note); note != "" {
+				b.WriteString("\n" + note + "\n")
+			}
+		}
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+type jsonBookmark struct {
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Notes     string    `json:"notes"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// jsonEncoder writes one array, an element at a time.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(bm *models.Bookmark) error {
+	b, err := json.Marshal(jsonBookmark{
+		URL:       bm.URL,
+		Title:     bm.Title,
+		Notes:     bm.Notes,
+		Tags:      tagNames(bm),
+		CreatedAt: bm.CreatedAt,
+		UpdatedAt: bm.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
@@ -0,0 +1,67 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;",
+)
+
+// markdownEncoder writes a list with one item per bookmark, tags and notes
+// nested under it.
+type markdownEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *markdownEncoder) begin() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	_, err := io.WriteString(e.w, "# Bookmarks\n\n")
+	return err
+}
+
+func (e *markdownEncoder) Encode(bm *models.Bookmark) error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "- [%s](%s) - %s\n", markdownEscaper.Replace(title(bm)), markdownURL(bm.URL), bm.CreatedAt.Format("2006-01-02"))
+
+	if names := tagNames(bm); len(names) > 0 {
+		b.WriteString("  - Tags:")
+		for _, name := range names {
+			b.WriteString(" `" + strings.Replace(name, "`", "'", -1) + "`")
+		}
+		b.WriteString("\n")
+	}
+
+	if notes := strings.TrimSpace(bm.Notes); notes != "" {
+		for _, line := range strings.Split(notes, "\n") {
+			b.WriteString(strings.TrimRight("  > "+strings.TrimRight(line, "\r"), " ") + "\n")
+		}
+	}
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *markdownEncoder) Close() error {
+	return e.begin()
+}
+
+// markdownURL keeps a link destination from ending the link early.
+func markdownURL(u string) string {
+	if strings.ContainsAny(u, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(u) + ">"
+	}
+	return u
+}
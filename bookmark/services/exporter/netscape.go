@@ -0,0 +1,64 @@
+package exporter
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+const netscapeHeader = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+`
+
+// netscapeEncoder writes the bookmark HTML every browser can import.
+type netscapeEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *netscapeEncoder) begin() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	_, err := io.WriteString(e.w, netscapeHeader)
+	return err
+}
+
+func (e *netscapeEncoder) Encode(bm *models.Bookmark) error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(e.w, "    <DT><A HREF=\"%s\" ADD_DATE=\"%d\" LAST_MODIFIED=\"%d\" TAGS=\"%s\">%s</A>\n",
+		html.EscapeString(bm.URL),
+		bm.CreatedAt.Unix(),
+		bm.UpdatedAt.Unix(),
+		html.EscapeString(strings.Join(tagNames(bm), ",")),
+		html.EscapeString(title(bm)))
+	if err != nil {
+		return err
+	}
+
+	if bm.Notes != "" {
+		_, err = fmt.Fprintf(e.w, "    <DD>%s\n", html.EscapeString(bm.Notes))
+	}
+	return err
+}
+
+func (e *netscapeEncoder) Close() error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "</DL><p>\n")
+	return err
+}
@@ -0,0 +1,150 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/readability"
+)
+
+type Config struct {
+	// Timeout bounds the whole request, redirects and body included.
+	Timeout      time.Duration
+	MaxBytes     int64
+	MaxRedirects int
+	UserAgent    string
+
+	// AllowPrivateNetworks disables the SSRF guard. Only tests and local
+	// development should ever set it.
+	AllowPrivateNetworks bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:      10 * time.Second,
+		MaxBytes:     2 << 20,
+		MaxRedirects: 5,
+		UserAgent:    "go-clean-architecture-sql/1.0 (+https://github.com/khuchuz/go-clean-architecture-sql)",
+	}
+}
+
+type Page struct {
+	URL         *url.URL
+	ContentType string
+	Body        []byte
+	Truncated   bool
+}
+
+type Fetcher struct {
+	client *http.Client
+	config Config
+}
+
+func NewFetcher(config Config) *Fetcher {
+	return &Fetcher{
+		config: config,
+		client: &http.Client{
+			Transport: newTransport(config),
+			Timeout:   config.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= config.MaxRedirects {
+					return fmt.Errorf("%w: too many redirects", bookmark.ErrFetchFailed)
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return bookmark.ErrAddressBlocked
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func newTransport(config Config) *http.Transport {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !config.AllowPrivateNetworks {
+		dialer.Control = guardDial
+	}
+
+	return &http.Transport{
+		// No proxy: it would connect on our behalf and bypass the guard.
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: config.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+}
+
+// Get downloads an HTML page, reading at most MaxBytes of it.
+func (f *Fetcher) Get(ctx context.Context, rawURL string) (*Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, bookmark.ErrInvalidURL
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", f.config.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, bookmark.ErrAddressBlocked) {
+			return nil, bookmark.ErrAddressBlocked
+		}
+		return nil, fmt.Errorf("%w: %v", bookmark.ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: status %d", bookmark.ErrFetchFailed, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", bookmark.ErrFetchFailed, err)
+	}
+
+	page := &Page{URL: resp.Request.URL, Body: body}
+	if int64(len(body)) > f.config.MaxBytes {
+		page.Body = body[:f.config.MaxBytes]
+		page.Truncated = true
+	}
+
+	page.ContentType = resp.Header.Get("Content-Type")
+	if page.ContentType == "" {
+		page.ContentType = http.DetectContentType(page.Body)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(page.ContentType); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, bookmark.ErrNotHTML
+	}
+
+	return page, nil
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*models.FetchedPage, error) {
+	page, err := f.Get(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.FetchedPage{Metadata: ExtractMetadata(page.URL, page.Body)}
+	if article, err := readability.Extract(page.URL, page.Body); err == nil {
+		result.Article = article
+	}
+
+	return result, nil
+}
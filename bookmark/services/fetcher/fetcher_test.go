@@ -0,0 +1,170 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPage = `<!doctype html>
+<html><head>
+<title> Plain   title </title>
+<meta name="description" content="Plain description">
+<meta property="og:title" content="OG title">
+<meta property="og:site_name" content="Example Blog">
+<meta property="og:image" content="/img/cover.png">
+<link rel="canonical" href="https://example.com/post?id=1">
+<link rel="shortcut icon" href="/static/favicon.png">
+</head><body><p>Hello</p></body></html>`
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.AllowPrivateNetworks = true
+	config.Timeout = 2 * time.Second
+	return config
+}
+
+func TestFetch_ExtractsMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("User-Agent"), "go-clean-architecture-sql")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(testPage))
+	}))
+	defer ts.Close()
+
+	page, err := NewFetcher(testConfig()).Fetch(context.Background(), ts.URL+"/post")
+	require.NoError(t, err)
+	meta := page.Metadata
+	assert.Equal(t, "OG title", meta.Title)
+	assert.Equal(t, "Plain description", meta.Description)
+	assert.Equal(t, "https://example.com/post?id=1", meta.CanonicalURL)
+	assert.Equal(t, ts.URL+"/img/cover.png", meta.ImageURL)
+	assert.Equal(t, ts.URL+"/static/favicon.png", meta.FaviconURL)
+	assert.Equal(t, "Example Blog", meta.SiteName)
+	assert.Equal(t, "Hello", page.Article.Text)
+}
+
+func TestFetch_FollowsRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>New</title><link rel="icon" href="icon.ico"></head></html>`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	page, err := NewFetcher(testConfig()).Fetch(context.Background(), ts.URL+"/old")
+	require.NoError(t, err)
+	meta := page.Metadata
+	assert.Equal(t, "New", meta.Title)
+	assert.Equal(t, ts.URL+"/new/icon.ico", meta.FaviconURL)
+}
+
+func TestFetch_TooManyRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"x", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	_, err := NewFetcher(testConfig()).Fetch(context.Background(), ts.URL+"/")
+	assert.True(t, errors.Is(err, bookmark.ErrFetchFailed), err)
+}
+
+func TestFetch_BlocksPrivateAddresses(t *testing.T) {
+	hit := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer ts.Close()
+
+	config := testConfig()
+	config.AllowPrivateNetworks = false
+
+	_, err := NewFetcher(config).Fetch(context.Background(), ts.URL)
+	assert.Equal(t, bookmark.ErrAddressBlocked, err)
+	assert.False(t, hit)
+}
+
+func TestFetch_CapsBodySize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><title>Big</title></head><body>"))
+		w.Write([]byte(strings.Repeat("x", 1<<20)))
+	}))
+	defer ts.Close()
+
+	config := testConfig()
+	config.MaxBytes = 1024
+
+	page, err := NewFetcher(config).Get(context.Background(), ts.URL)
+	require.NoError(t, err)
+	assert.Len(t, page.Body, 1024)
+	assert.True(t, page.Truncated)
+	assert.Equal(t, "Big", ExtractMetadata(page.URL, page.Body).Title)
+}
+
+func TestFetch_TimesOut(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	config := testConfig()
+	config.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := NewFetcher(config).Fetch(context.Background(), ts.URL)
+	assert.True(t, errors.Is(err, bookmark.ErrFetchFailed), err)
+	assert.True(t, time.Since(start) < 2*time.Second)
+}
+
+func TestFetch_RejectsNonHTML(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer ts.Close()
+
+	_, err := NewFetcher(testConfig()).Fetch(context.Background(), ts.URL)
+	assert.Equal(t, bookmark.ErrNotHTML, err)
+}
+
+func TestFetch_BadStatus(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	_, err := NewFetcher(testConfig()).Fetch(context.Background(), ts.URL)
+	assert.True(t, errors.Is(err, bookmark.ErrFetchFailed), err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestFetch_RejectsOtherSchemes(t *testing.T) {
+	_, err := NewFetcher(testConfig()).Fetch(context.Background(), "file:///etc/passwd")
+	assert.Equal(t, bookmark.ErrInvalidURL, err)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fc00::1", "fe80::1", "::ffff:127.0.0.1", "64:ff9b::a00:1"} {
+		assert.False(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"93.184.216.34", "8.8.8.8", "2606:4700:4700::1111"} {
+		assert.True(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+}
@@ -0,0 +1,110 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+// LinkChecker asks whether a URL still answers, without downloading it.
+type LinkChecker struct {
+	client *http.Client
+	config Config
+}
+
+func NewLinkChecker(config Config) *LinkChecker {
+	return &LinkChecker{
+		config: config,
+		client: &http.Client{
+			Transport: newTransport(config),
+			Timeout:   config.Timeout,
+			// Redirects are followed by Check itself to see each of them.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Check sends a HEAD request and follows the redirects it gets. Servers
+// that answer HEAD with an error are asked again with GET, since plenty of
+// them only implement GET.
+func (l *LinkChecker) Check(ctx context.Context, rawURL string) *models.LinkCheck {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &models.LinkCheck{Error: bookmark.ErrInvalidURL.Error()}
+	}
+
+	check, err := l.follow(ctx, http.MethodHead, u)
+	if err == nil && check.StatusCode >= 400 {
+		check, err = l.follow(ctx, http.MethodGet, u)
+	}
+	if err != nil {
+		return &models.LinkCheck{Error: err.Error()}
+	}
+
+	return check
+}
+
+func (l *LinkChecker) follow(ctx context.Context, method string, u *url.URL) (*models.LinkCheck, error) {
+	check := new(models.LinkCheck)
+	current := u
+
+	for redirects := 0; ; redirects++ {
+		resp, err := l.do(ctx, method, current)
+		if err != nil {
+			return nil, err
+		}
+		location := resp.Header.Get("Location")
+		check.StatusCode = resp.StatusCode
+
+		if resp.StatusCode < 300 || resp.StatusCode > 399 || location == "" {
+			break
+		}
+		if redirects >= l.config.MaxRedirects {
+			return nil, fmt.Errorf("%w: too many redirects", bookmark.ErrFetchFailed)
+		}
+
+		next, err := current.Parse(location)
+		if err != nil || (next.Scheme != "http" && next.Scheme != "https") {
+			return nil, bookmark.ErrAddressBlocked
+		}
+		if resp.StatusCode == http.StatusMovedPermanently || resp.StatusCode == http.StatusPermanentRedirect {
+			check.Permanent = true
+		}
+		current = next
+	}
+
+	if current != u {
+		check.RedirectURL = current.String()
+	}
+	return check, nil
+}
+
+func (l *LinkChecker) do(ctx context.Context, method string, u *url.URL) (*http.Response, error) {
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", l.config.UserAgent)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		if errors.Is(err, bookmark.ErrAddressBlocked) {
+			return nil, bookmark.ErrAddressBlocked
+		}
+		return nil, fmt.Errorf("%w: %v", bookmark.ErrFetchFailed, err)
+	}
+	// Only the status is wanted; drain a little so the connection is reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	resp.Body.Close()
+
+	return resp, nil
+}
@@ -0,0 +1,75 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck_OK(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+	}))
+	defer ts.Close()
+
+	check := NewLinkChecker(testConfig()).Check(context.Background(), ts.URL)
+	assert.Equal(t, 200, check.StatusCode)
+	assert.Empty(t, check.RedirectURL)
+	assert.False(t, check.Permanent)
+	assert.Empty(t, check.Error)
+}
+
+func TestCheck_FollowsRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/tmp", http.StatusFound)
+	})
+	mux.HandleFunc("/tmp", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	check := NewLinkChecker(testConfig()).Check(context.Background(), ts.URL+"/old")
+	assert.Equal(t, 200, check.StatusCode)
+	assert.Equal(t, ts.URL+"/new", check.RedirectURL)
+	assert.True(t, check.Permanent)
+}
+
+func TestCheck_RetriesHeadWithGet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer ts.Close()
+
+	check := NewLinkChecker(testConfig()).Check(context.Background(), ts.URL)
+	assert.Equal(t, 200, check.StatusCode)
+}
+
+func TestCheck_NotFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	check := NewLinkChecker(testConfig()).Check(context.Background(), ts.URL)
+	assert.Equal(t, 404, check.StatusCode)
+	assert.Empty(t, check.Error)
+}
+
+func TestCheck_BlocksPrivateAddresses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	config := testConfig()
+	config.AllowPrivateNetworks = false
+
+	check := NewLinkChecker(config).Check(context.Background(), ts.URL)
+	assert.Equal(t, 0, check.StatusCode)
+	assert.Equal(t, bookmark.ErrAddressBlocked.Error(), check.Error)
+}
@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ExtractMetadata reads the <head> of an HTML document. Open Graph values win
+// over their plain HTML counterparts, and every URL is resolved against base.
+func ExtractMetadata(base *url.URL, body []byte) *models.PageMetadata {
+	meta := new(models.PageMetadata)
+
+	var (
+		title, description, ogTitle, ogDescription string
+		favicon, touchIcon                         string
+	)
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	inTitle := false
+loop:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				break loop
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			tag := atom.Lookup(name)
+			if tag == atom.Body {
+				break loop
+			}
+			if tag == atom.Title {
+				inTitle = true
+				continue
+			}
+			if !hasAttr || (tag != atom.Meta && tag != atom.Link) {
+				continue
+			}
+
+			attrs := attributes(tokenizer)
+			if tag == atom.Meta {
+				key := strings.ToLower(attrs["property"])
+				if key == "" {
+					key = strings.ToLower(attrs["name"])
+				}
+				content := strings.TrimSpace(attrs["content"])
+				switch key {
+				case "og:title":
+					ogTitle = content
+				case "og:description":
+					ogDescription = content
+				case "description":
+					description = content
+				case "og:image", "og:image:url":
+					if meta.ImageURL == "" {
+						meta.ImageURL = resolve(base, content)
+					}
+				case "og:site_name":
+					meta.SiteName = content
+				case "og:url":
+					if meta.CanonicalURL == "" {
+						meta.CanonicalURL = resolve(base, content)
+					}
+				}
+				continue
+			}
+
+			for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
+				switch rel {
+				case "canonical":
+					// <link rel=canonical> is authoritative over og:url.
+					meta.CanonicalURL = resolve(base, attrs["href"])
+				case "icon":
+					if favicon == "" {
+						favicon = resolve(base, attrs["href"])
+					}
+				case "apple-touch-icon":
+					if touchIcon == "" {
+						touchIcon = resolve(base, attrs["href"])
+					}
+				}
+			}
+		}
+	}
+
+	meta.Title = firstNonEmpty(ogTitle, title)
+	meta.Description = firstNonEmpty(ogDescription, description)
+	meta.FaviconURL = firstNonEmpty(favicon, touchIcon, resolve(base, "/favicon.ico"))
+	if meta.SiteName == "" && base != nil {
+		meta.SiteName = strings.TrimPrefix(base.Hostname(), "www.")
+	}
+
+	return meta
+}
+
+func attributes(tokenizer *html.Tokenizer) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		key, val, more := tokenizer.TagAttr()
+		attrs[strings.ToLower(string(key))] = string(val)
+		if !more {
+			return attrs
+		}
+	}
+}
+
+// resolve turns ref into an absolute http(s) URL, dropping anything else
+// such as javascript: or data: URLs.
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == nil {
+		return ""
+	}
+
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.Join(strings.Fields(v), " "); v != "" {
+			return v
+		}
+	}
+	return ""
+}
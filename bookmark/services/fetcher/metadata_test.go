@@ -0,0 +1,41 @@
+package fetcher
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractMetadata_Fallbacks(t *testing.T) {
+	base, _ := url.Parse("https://www.example.com/a/b")
+
+	meta := ExtractMetadata(base, []byte(`<html><head><title>Only &amp; title</title></head><body><title>ignored</title></body></html>`))
+	assert.Equal(t, "Only & title", meta.Title)
+	assert.Equal(t, "", meta.Description)
+	assert.Equal(t, "https://www.example.com/favicon.ico", meta.FaviconURL)
+	assert.Equal(t, "example.com", meta.SiteName)
+}
+
+func TestExtractMetadata_CanonicalBeatsOGURL(t *testing.T) {
+	base, _ := url.Parse("https://example.com/post")
+
+	meta := ExtractMetadata(base, []byte(`<head>
+<meta property="og:url" content="https://example.com/og">
+<link rel="canonical" href="/canonical">
+<link rel="apple-touch-icon" href="/touch.png">
+</head>`))
+	assert.Equal(t, "https://example.com/canonical", meta.CanonicalURL)
+	assert.Equal(t, "https://example.com/touch.png", meta.FaviconURL)
+}
+
+func TestExtractMetadata_DropsUnsafeURLs(t *testing.T) {
+	base, _ := url.Parse("https://example.com/")
+
+	meta := ExtractMetadata(base, []byte(`<head>
+<meta property="og:image" content="javascript:alert(1)">
+<link rel="icon" href="data:image/png;base64,AAAA">
+</head>`))
+	assert.Equal(t, "", meta.ImageURL)
+	assert.Equal(t, "https://example.com/favicon.ico", meta.FaviconURL)
+}
@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+// Header names understood for each field, as written by Pocket, Raindrop,
+// Instapaper and friends.
+var csvColumns = map[string][]string{
+	"url":     {"url", "href", "link", "uri"},
+	"title":   {"title", "name"},
+	"notes":   {"notes", "note", "description", "excerpt", "comment"},
+	"tags":    {"tags", "tag", "labels"},
+	"folder":  {"folder", "folders", "collection", "path"},
+	"created": {"created", "created_at", "time_added", "date_added", "add_date", "timestamp"},
+}
+
+var csvTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseCSV reads a CSV file with a header row. Only a url column is needed.
+func parseCSV(data []byte) ([]models.ImportItem, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, bookmark.ErrImportFormat
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, aliases := range csvColumns {
+			if _, ok := columns[field]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, bookmark.ErrImportFormat
+	}
+
+	var items []models.ImportItem
+	// Rows are numbered the way a spreadsheet shows them, header first.
+	for row := 2; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, bookmark.ErrImportFormat
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		item := models.ImportItem{
+			Row:       row,
+			URL:       field("url"),
+			Title:     field("title"),
+			Notes:     field("notes"),
+			Tags:      splitTags(field("tags")),
+			CreatedAt: parseCSVTime(field("created")),
+		}
+		for _, folder := range strings.Split(field("folder"), "/") {
+			item.Folders = appendFolder(item.Folders, folder)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCSVTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	if t := parseUnixTime(s); t != nil {
+		return t
+	}
+
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
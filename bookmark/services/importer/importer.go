@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+type Parser struct{}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads the bookmarks of an export file. An empty format is detected
+// from the content; the format actually used is returned with the items.
+func (p *Parser) Parse(format string, data []byte) (string, []models.ImportItem, error) {
+	if format == "" {
+		format = Detect(data)
+	}
+
+	var items []models.ImportItem
+	var err error
+
+	switch format {
+	case models.ImportFormatNetscape:
+		items, err = parseNetscape(data)
+	case models.ImportFormatPocket:
+		// Pocket has exported both a Netscape style HTML file and a CSV.
+		if looksLikeHTML(data) {
+			items, err = parseNetscape(data)
+		} else {
+			items, err = parseCSV(data)
+		}
+	case models.ImportFormatChrome:
+		items, err = parseChrome(data)
+	case models.ImportFormatFirefox:
+		items, err = parseFirefox(data)
+	case models.ImportFormatCSV:
+		items, err = parseCSV(data)
+	default:
+		return "", nil, bookmark.ErrImportFormat
+	}
+
+	if err != nil {
+		return format, nil, err
+	}
+	if len(items) == 0 {
+		return format, nil, bookmark.ErrImportEmpty
+	}
+
+	return format, items, nil
+}
+
+// Detect guesses the format of an export file, falling back to CSV.
+func Detect(data []byte) string {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if bytes.Contains(trimmed, []byte(`"roots"`)) {
+			return models.ImportFormatChrome
+		}
+		return models.ImportFormatFirefox
+	}
+
+	if looksLikeHTML(trimmed) {
+		return models.ImportFormatNetscape
+	}
+
+	return models.ImportFormatCSV
+}
+
+func looksLikeHTML(data []byte) bool {
+	head := strings.ToLower(string(data[:min(len(data), 1024)]))
+	return strings.Contains(head, "<!doctype") || strings.Contains(head, "<html") ||
+		strings.Contains(head, "<dl") || strings.Contains(head, "<a ")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// splitTags splits a tag list on commas, or on pipes as Pocket writes them.
+func splitTags(s string) []string {
+	sep := ","
+	if strings.Contains(s, "|") {
+		sep = "|"
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(s, sep) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const netscapeExport = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file. -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1600000000" PERSONAL_TOOLBAR_FOLDER="true">Bookmarks bar</H3>
+    <DL><p>
+        <DT><A HREF="https://go.dev/" ADD_DATE="1600000001" TAGS="golang,lang">The Go   Programming Language</A>
+        <DD>Home of Go
+        <DT><H3>Reading</H3>
+        <DL><p>
+            <DT><H3>Architecture</H3>
+            <DL><p>
+                <DT><A HREF="https://blog.cleancoder.com/">Clean Coder Blog</A>
+            </DL><p>
+            <DT><A HREF="https://martinfowler.com/">Martin Fowler</A>
+        </DL><p>
+    </DL><p>
+    <DT><A HREF="javascript:alert(1)">Bookmarklet</A>
+</DL><p>
+`
+
+const pocketExport = `<!DOCTYPE html>
+<html><head><title>Pocket Export</title></head><body>
+<h1>Unread</h1>
+<ul>
+<li><a href="https://example.com/a" time_added="1600000000" tags="read,later">Article A</a></li>
+</ul>
+<h1>Read Archive</h1>
+<ul>
+<li><a href="https://example.com/b" time_added="1600000100" tags="">Article B</a></li>
+</ul>
+</body></html>`
+
+const chromeExport = `{
+   "checksum": "abc",
+   "roots": {
+      "other": {"children": [{"type": "url", "name": "Other", "url": "https://example.com/other", "date_added": "13245000000000000"}], "name": "Other bookmarks", "type": "folder"},
+      "bookmark_bar": {
+         "children": [
+            {"type": "url", "name": "Go", "url": "https://go.dev/", "date_added": "13245000000000000"},
+            {"type": "folder", "name": "Dev", "children": [
+               {"type": "folder", "name": "DB", "children": [{"type": "url", "name": "MySQL", "url": "https://mysql.com/"}]}
+            ]}
+         ],
+         "name": "Bookmarks bar",
+         "type": "folder"
+      },
+      "sync_transaction_version": "1"
+   },
+   "version": 1
+}`
+
+const firefoxExport = `{"guid":"root________","title":"","type":"text/x-moz-place-container","root":"placesRoot","children":[
+  {"title":"menu","type":"text/x-moz-place-container","root":"bookmarksMenuFolder","children":[
+    {"title":"Recent Tags","type":"text/x-moz-place","uri":"place:type=6&sort=14&maxResults=10"},
+    {"title":"Mozilla","type":"text/x-moz-place-container","children":[
+      {"title":"MDN","type":"text/x-moz-place","uri":"https://developer.mozilla.org/","tags":"docs,web","dateAdded":1600000000000000}
+    ]}
+  ]},
+  {"title":"toolbar","type":"text/x-moz-place-container","root":"toolbarFolder","children":[
+    {"title":"Go","type":"text/x-moz-place","uri":"https://go.dev/"}
+  ]}
+]}`
+
+const csvExport = "\xef\xbb\xbfURL,Title,Note,Tags,Folder,Created\n" +
+	"https://go.dev/,Go,\"The Go site, official\",golang|lang,Dev / Go,2020-09-13T12:26:40Z\n" +
+	",,,,,\n" +
+	"not a url,Broken,,,,\n" +
+	"https://example.com/,,,,,1600000000\n"
+
+func TestParse_Netscape(t *testing.T) {
+	format, items, err := NewParser().Parse("", []byte(netscapeExport))
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportFormatNetscape, format)
+	require.Len(t, items, 4)
+
+	assert.Equal(t, "https://go.dev/", items[0].URL)
+	assert.Equal(t, "The Go Programming Language", items[0].Title)
+	assert.Equal(t, "Home of Go", items[0].Notes)
+	assert.Equal(t, []string{"golang", "lang"}, items[0].Tags)
+	assert.Empty(t, items[0].Folders)
+	assert.Equal(t, time.Unix(1600000001, 0).UTC(), *items[0].CreatedAt)
+
+	assert.Equal(t, []string{"Reading", "Architecture"}, items[1].Folders)
+	assert.Equal(t, "", items[1].Notes)
+	assert.Equal(t, []string{"Reading"}, items[2].Folders)
+	assert.Equal(t, "Martin Fowler", items[2].Title)
+
+	assert.Equal(t, 4, items[3].Row)
+	assert.Equal(t, "javascript:alert(1)", items[3].URL)
+	assert.Empty(t, items[3].Folders)
+}
+
+func TestParse_Pocket(t *testing.T) {
+	_, items, err := NewParser().Parse(models.ImportFormatPocket, []byte(pocketExport))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Article A", items[0].Title)
+	assert.Equal(t, []string{"read", "later"}, items[0].Tags)
+	assert.Nil(t, items[1].Tags)
+
+	_, items, err = NewParser().Parse(models.ImportFormatPocket, []byte("title,url,time_added,tags,status\nA,https://example.com/a,1600000000,go|db,unread\n"))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, []string{"go", "db"}, items[0].Tags)
+}
+
+func TestParse_Chrome(t *testing.T) {
+	format, items, err := NewParser().Parse("", []byte(chromeExport))
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportFormatChrome, format)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, "https://go.dev/", items[0].URL)
+	assert.Equal(t, time.Date(2020, 9, 19, 14, 40, 0, 0, time.UTC), *items[0].CreatedAt)
+	assert.Equal(t, []string{"Dev", "DB"}, items[1].Folders)
+	assert.Equal(t, "https://example.com/other", items[2].URL)
+	assert.Empty(t, items[2].Folders)
+}
+
+func TestParse_Firefox(t *testing.T) {
+	format, items, err := NewParser().Parse("", []byte(firefoxExport))
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportFormatFirefox, format)
+	require.Len(t, items, 2)
+
+	assert.Equal(t, "https://developer.mozilla.org/", items[0].URL)
+	assert.Equal(t, []string{"docs", "web"}, items[0].Tags)
+	assert.Equal(t, []string{"Mozilla"}, items[0].Folders)
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), *items[0].CreatedAt)
+	assert.Empty(t, items[1].Folders)
+}
+
+func TestParse_CSV(t *testing.T) {
+	format, items, err := NewParser().Parse("", []byte(csvExport))
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportFormatCSV, format)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, 2, items[0].Row)
+	assert.Equal(t, "The Go site, official", items[0].Notes)
+	assert.Equal(t, []string{"golang", "lang"}, items[0].Tags)
+	assert.Equal(t, []string{"Dev", "Go"}, items[0].Folders)
+	assert.Equal(t, time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC), *items[0].CreatedAt)
+
+	assert.Equal(t, 4, items[1].Row)
+	assert.Equal(t, "not a url", items[1].URL)
+	assert.Equal(t, 5, items[2].Row)
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), *items[2].CreatedAt)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, _, err := NewParser().Parse("xml", []byte("<x/>"))
+	assert.Equal(t, bookmark.ErrImportFormat, err)
+
+	_, _, err = NewParser().Parse("", []byte("title,notes\nfoo,bar\n"))
+	assert.Equal(t, bookmark.ErrImportFormat, err)
+
+	_, _, err = NewParser().Parse(models.ImportFormatChrome, []byte(`{"roots": `))
+	assert.Equal(t, bookmark.ErrImportFormat, err)
+
+	_, _, err = NewParser().Parse("", []byte("<!DOCTYPE NETSCAPE-Bookmark-file-1><DL><p></DL>"))
+	assert.Equal(t, bookmark.ErrImportEmpty, err)
+}
@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+type chromeNode struct {
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	URL       string       `json:"url"`
+	DateAdded string       `json:"date_added"`
+	Children  []chromeNode `json:"children"`
+}
+
+type chromeFile struct {
+	Roots map[string]json.RawMessage `json:"roots"`
+}
+
+// Order in which Chrome shows its roots.
+var chromeRoots = map[string]int{"bookmark_bar": 0, "other": 1, "synced": 2}
+
+// Chrome counts microseconds from 1601-01-01, this many seconds before the
+// Unix epoch.
+const chromeEpochOffset = 11644473600
+
+// parseChrome reads the Bookmarks file Chrome, Edge and Brave keep in the
+// profile directory.
+func parseChrome(data []byte) ([]models.ImportItem, error) {
+	var file chromeFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Roots == nil {
+		return nil, bookmark.ErrImportFormat
+	}
+
+	names := make([]string, 0, len(file.Roots))
+	for name := range file.Roots {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		oi, ok := chromeRoots[names[i]]
+		if !ok {
+			oi = len(chromeRoots)
+		}
+		oj, ok := chromeRoots[names[j]]
+		if !ok {
+			oj = len(chromeRoots)
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
+
+	var items []models.ImportItem
+	var walk func(node chromeNode, folders []string)
+	walk = func(node chromeNode, folders []string) {
+		if node.Type == "url" {
+			items = append(items, models.ImportItem{
+				Row:       len(items) + 1,
+				URL:       strings.TrimSpace(node.URL),
+				Title:     strings.TrimSpace(node.Name),
+				Folders:   folders,
+				CreatedAt: parseChromeTime(node.DateAdded),
+			})
+			return
+		}
+		for _, child := range node.Children {
+			walk(child, appendFolder(folders, node.Name))
+		}
+	}
+
+	for _, name := range names {
+		var root chromeNode
+		// Roots also holds bookkeeping values that are not folders.
+		if err := json.Unmarshal(file.Roots[name], &root); err != nil || root.Type != "folder" {
+			continue
+		}
+		for _, child := range root.Children {
+			walk(child, nil)
+		}
+	}
+
+	return items, nil
+}
+
+func parseChromeTime(s string) *time.Time {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return nil
+	}
+
+	t := time.Unix(n/1e6-chromeEpochOffset, n%1e6*1e3).UTC()
+	return &t
+}
+
+type firefoxNode struct {
+	Type      string        `json:"type"`
+	Title     string        `json:"title"`
+	URI       string        `json:"uri"`
+	Tags      string        `json:"tags"`
+	DateAdded int64         `json:"dateAdded"`
+	Root      string        `json:"root"`
+	Children  []firefoxNode `json:"children"`
+}
+
+// parseFirefox reads a Firefox bookmarks backup (Library > Backup).
+func parseFirefox(data []byte) ([]models.ImportItem, error) {
+	var root firefoxNode
+	if err := json.Unmarshal(data, &root); err != nil || root.Type != "text/x-moz-place-container" {
+		return nil, bookmark.ErrImportFormat
+	}
+
+	var items []models.ImportItem
+	var walk func(node firefoxNode, folders []string)
+	walk = func(node firefoxNode, folders []string) {
+		switch node.Type {
+		case "text/x-moz-place":
+			// place: URIs are saved searches, not pages.
+			if node.URI == "" || strings.HasPrefix(node.URI, "place:") {
+				return
+			}
+			items = append(items, models.ImportItem{
+				Row:       len(items) + 1,
+				URL:       strings.TrimSpace(node.URI),
+				Title:     strings.TrimSpace(node.Title),
+				Tags:      splitTags(node.Tags),
+				Folders:   folders,
+				CreatedAt: parseUnixTime(strconv.FormatInt(node.DateAdded, 10)),
+			})
+		case "text/x-moz-place-container":
+			if node.Root == "" {
+				folders = appendFolder(folders, node.Title)
+			}
+			for _, child := range node.Children {
+				walk(child, folders)
+			}
+		}
+	}
+	walk(root, nil)
+
+	return items, nil
+}
+
+func appendFolder(folders []string, name string) []string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return folders
+	}
+
+	path := make([]string, len(folders), len(folders)+1)
+	copy(path, folders)
+	return append(path, name)
+}
@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"golang.org/x/net/html"
+)
+
+// parseNetscape reads the NETSCAPE-Bookmark-file-1 format every browser
+// exports. Its DT, DD and P elements are never closed, so the tokens are
+// walked directly instead of building a tree.
+func parseNetscape(data []byte) ([]models.ImportItem, error) {
+	z := html.NewTokenizer(bytes.NewReader(data))
+
+	var (
+		items   []models.ImportItem
+		folders []string // one entry per open DL, "" where it is not a named folder
+		pending string   // folder named by the last H3, opened by the next DL
+		text    strings.Builder
+		inLink  bool
+		inTitle bool
+		inNotes bool
+	)
+
+	path := func() []string {
+		var path []string
+		for _, f := range folders {
+			if f != "" {
+				path = append(path, f)
+			}
+		}
+		return path
+	}
+	endNotes := func() {
+		if inNotes && len(items) > 0 {
+			items[len(items)-1].Notes = strings.TrimSpace(text.String())
+		}
+		inNotes = false
+	}
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				endNotes()
+				return items, nil
+			}
+			return nil, bookmark.ErrImportFormat
+
+		case html.TextToken:
+			if inLink || inTitle || inNotes {
+				text.Write(z.Text())
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "a":
+				endNotes()
+				item := models.ImportItem{Row: len(items) + 1, Folders: path()}
+				for _, a := range tok.Attr {
+					switch a.Key {
+					case "href":
+						item.URL = strings.TrimSpace(a.Val)
+					case "tags":
+						item.Tags = splitTags(a.Val)
+					case "add_date", "time_added":
+						item.CreatedAt = parseUnixTime(a.Val)
+					}
+				}
+				items = append(items, item)
+				inLink = true
+				text.Reset()
+			case "h3":
+				endNotes()
+				inTitle = true
+				text.Reset()
+				for _, a := range tok.Attr {
+					// The toolbar and "other bookmarks" are where browsers keep
+					// everything, not folders the user made.
+					if a.Key == "personal_toolbar_folder" || a.Key == "unfiled_bookmarks_folder" {
+						inTitle = false
+					}
+				}
+				pending = ""
+			case "dd":
+				endNotes()
+				inNotes = true
+				text.Reset()
+			case "dl":
+				endNotes()
+				folders = append(folders, pending)
+				pending = ""
+			case "dt", "h1":
+				endNotes()
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "a":
+				if inLink && len(items) > 0 {
+					items[len(items)-1].Title = strings.Join(strings.Fields(text.String()), " ")
+				}
+				inLink = false
+			case "h3":
+				if inTitle {
+					pending = strings.Join(strings.Fields(text.String()), " ")
+				}
+				inTitle = false
+			case "dl":
+				endNotes()
+				if len(folders) > 0 {
+					folders = folders[:len(folders)-1]
+				}
+			}
+		}
+	}
+}
+
+func parseUnixTime(s string) *time.Time {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n <= 0 {
+		return nil
+	}
+
+	// Some exporters write milliseconds or microseconds.
+	switch {
+	case n > 1e15:
+		n /= 1e6
+	case n > 1e12:
+		n /= 1e3
+	}
+
+	t := time.Unix(n, 0).UTC()
+	return &t
+}
@@ -0,0 +1,308 @@
+package readability
+
+import (
+	"bytes"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const wordsPerMinute = 200
+
+// Class and id hints, after Mozilla's Readability.
+var (
+	unlikelyCandidate = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`)
+	maybeCandidate    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveHint      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeHint      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// Removed with everything inside them before scoring.
+var dropTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Object: true, atom.Embed: true, atom.Applet: true,
+	atom.Form: true, atom.Input: true, atom.Button: true, atom.Select: true, atom.Textarea: true,
+	atom.Svg: true, atom.Math: true, atom.Canvas: true, atom.Video: true, atom.Audio: true,
+	atom.Nav: true, atom.Aside: true, atom.Footer: true, atom.Link: true, atom.Meta: true,
+}
+
+var blockTags = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Blockquote: true, atom.Dd: true, atom.Div: true,
+	atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true, atom.H1: true,
+	atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true, atom.Header: true,
+	atom.Hr: true, atom.Li: true, atom.Main: true, atom.Ol: true, atom.P: true, atom.Pre: true,
+	atom.Section: true, atom.Table: true, atom.Tr: true, atom.Ul: true,
+}
+
+// Extract finds the main article of an HTML page, the way reader modes do,
+// and returns it as sanitized HTML and as plain text.
+func Extract(base *url.URL, body []byte) (*models.Article, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	root := findElement(doc, atom.Body)
+	if root == nil {
+		root = doc
+	}
+	prune(root)
+
+	r := &renderer{base: base}
+	for _, n := range articleNodes(root) {
+		r.render(n)
+	}
+
+	text := r.text.String()
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return nil, bookmark.ErrNoArticle
+	}
+
+	return &models.Article{
+		HTML:        strings.TrimSpace(r.html.String()),
+		Text:        text,
+		WordCount:   words,
+		ReadingTime: (words + wordsPerMinute - 1) / wordsPerMinute,
+	}, nil
+}
+
+func prune(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if removable(c) {
+			n.RemoveChild(c)
+		} else {
+			prune(c)
+		}
+		c = next
+	}
+}
+
+func removable(n *html.Node) bool {
+	switch n.Type {
+	case html.CommentNode, html.DoctypeNode:
+		return true
+	case html.ElementNode:
+	default:
+		return false
+	}
+
+	if dropTags[n.DataAtom] || hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if strings.Contains(strings.Replace(strings.ToLower(attr(n, "style")), " ", "", -1), "display:none") {
+		return true
+	}
+
+	switch n.DataAtom {
+	case atom.Body, atom.Article, atom.Main, atom.A, atom.Table, atom.Tbody, atom.Tr, atom.Td, atom.Th:
+		return false
+	}
+
+	hint := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyCandidate.MatchString(hint) && !maybeCandidate.MatchString(hint)
+}
+
+// articleNodes scores the containers of every paragraph and returns the best
+// one, along with siblings that look like they belong to the same article.
+func articleNodes(root *html.Node) []*html.Node {
+	scores := map[*html.Node]float64{}
+	var candidates []*html.Node
+
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	walk(root, func(n *html.Node) {
+		if !isParagraph(n) {
+			return
+		}
+
+		text := innerText(n)
+		length := utf8.RuneCountInString(text)
+		if length < 25 {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(length/100), 3)
+		addScore(n.Parent, score)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, score/2)
+		}
+	})
+
+	if len(candidates) == 0 {
+		return []*html.Node{root}
+	}
+
+	var top *html.Node
+	for _, c := range candidates {
+		scores[c] *= 1 - linkDensity(c)
+		if top == nil || scores[c] > scores[top] {
+			top = c
+		}
+	}
+
+	if top.Parent == nil || top == root {
+		return []*html.Node{top}
+	}
+
+	threshold := math.Max(10, scores[top]*0.2)
+	var nodes []*html.Node
+	for c := top.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c == top {
+			nodes = append(nodes, c)
+			continue
+		}
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if score, ok := scores[c]; ok && score >= threshold {
+			nodes = append(nodes, c)
+			continue
+		}
+		if c.DataAtom == atom.P {
+			text := innerText(c)
+			length := utf8.RuneCountInString(text)
+			density := linkDensity(c)
+			if length > 80 && density < 0.25 || length > 0 && density == 0 && strings.Contains(text, ". ") {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+
+	return nodes
+}
+
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.DataAtom {
+	case atom.Div:
+		score = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score = -5
+	}
+
+	for _, hint := range []string{attr(n, "class"), attr(n, "id")} {
+		if hint == "" {
+			continue
+		}
+		if negativeHint.MatchString(hint) {
+			score -= 25
+		}
+		if positiveHint.MatchString(hint) {
+			score += 25
+		}
+	}
+
+	return score
+}
+
+// isParagraph reports whether n holds running text: a paragraph, or a div
+// that is used like one.
+func isParagraph(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.Pre, atom.Td:
+		return true
+	case atom.Div:
+		return !hasBlockChild(n)
+	}
+	return false
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (blockTags[c.DataAtom] || hasBlockChild(c)) {
+			return true
+		}
+	}
+	return false
+}
+
+func linkDensity(n *html.Node) float64 {
+	length := utf8.RuneCountInString(innerText(n))
+	if length == 0 {
+		return 0
+	}
+
+	var links int
+	walk(n, func(c *html.Node) {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			links += utf8.RuneCountInString(innerText(c))
+		}
+	})
+
+	return math.Min(float64(links)/float64(length), 1)
+}
+
+func innerText(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+			b.WriteByte(' ')
+		}
+	})
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// walk calls fn for n and its descendants, parents first.
+func walk(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package readability
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const articlePage = `<!doctype html>
+<html><head><title>Post</title><script>var x = 1;</script></head>
+<body>
+<div id="header"><nav><a href="/">Home</a> <a href="/about">About</a></nav></div>
+<div class="sidebar"><p>Subscribe to our newsletter, it is great, really, trust us on this one.</p></div>
+<article class="post">
+  <h1>The Clean Architecture</h1>
+  <p onclick="alert(1)" style="color:red">Over the last several years we have seen a whole range of ideas regarding the architecture of systems.</p>
+  <p>Each of these architectures produce systems that are independent of frameworks, testable, independent of UI, and independent of the database. <a href="/deps" onmouseover="x()">Read more</a></p>
+  <img src="/img/onion.png" alt="onion &quot;layers&quot;" onerror="alert(1)">
+  <img src="javascript:alert(1)">
+  <pre><code>func main() {
+	run()
+}</code></pre>
+  <ul><li>Entities</li><li>Use cases</li></ul>
+  <p><a href="javascript:alert(1)">bad link</a> and <span class="x">plain words</span>, still part of the story, yes.</p>
+  <iframe src="https://ads.example.com"></iframe>
+</article>
+<div class="comments"><p>First! This comment section is full of long sentences, commas, and more commas.</p></div>
+<footer><p>Copyright, all rights reserved, forever and ever and ever.</p></footer>
+</body></html>`
+
+func TestExtract_Article(t *testing.T) {
+	base, _ := url.Parse("https://blog.example.com/2012/08/post.html")
+
+	article, err := Extract(base, []byte(articlePage))
+	require.NoError(t, err)
+
+	assert.Contains(t, article.HTML, "<h1>The Clean Architecture</h1>")
+	assert.Contains(t, article.HTML, `<p>Over the last several years`)
+	assert.Contains(t, article.HTML, `<a href="https://blog.example.com/deps">Read more</a>`)
+	assert.Contains(t, article.HTML, `<img src="https://blog.example.com/img/onion.png" alt="onion &#34;layers&#34;">`)
+	assert.Contains(t, article.HTML, "<pre><code>func main() {\n\trun()\n}</code></pre>")
+	assert.Contains(t, article.HTML, "<ul><li>Entities</li><li>Use cases</li></ul>")
+	assert.Contains(t, article.HTML, "<a>bad link</a> and plain words")
+
+	for _, unwanted := range []string{"onclick", "style=", "onerror", "onmouseover", "javascript:", "<script", "<iframe", "<span", "<article", "newsletter", "First!", "Copyright", "Home"} {
+		assert.NotContains(t, article.HTML, unwanted)
+	}
+
+	assert.True(t, strings.HasPrefix(article.Text, "The Clean Architecture\n\nOver the last several years"), article.Text)
+	assert.Contains(t, article.Text, "independent of the database. Read more\n\n")
+	assert.Contains(t, article.Text, "func main() {\n\trun()\n}")
+	assert.Contains(t, article.Text, "Entities\nUse cases")
+	assert.NotContains(t, article.Text, "  ")
+
+	assert.Equal(t, len(strings.Fields(article.Text)), article.WordCount)
+	assert.Equal(t, 1, article.ReadingTime)
+}
+
+func TestExtract_ReadingTime(t *testing.T) {
+	words := strings.Repeat("word, ", 450)
+	article, err := Extract(nil, []byte("<div><p>"+words+"</p><p>"+words+"</p></div>"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 900, article.WordCount)
+	assert.Equal(t, 5, article.ReadingTime)
+}
+
+func TestExtract_DivsAsParagraphs(t *testing.T) {
+	page := `<body><div class="content">
+<div>This div is written like a paragraph, with commas, and enough text to count.</div>
+<div>A second one follows it, also long enough, so both of them are kept here.</div>
+</div></body>`
+
+	article, err := Extract(nil, []byte(page))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(article.HTML, "<p>"))
+	assert.Equal(t, 2, strings.Count(article.Text, "\n\n")+1)
+}
+
+func TestExtract_NoArticle(t *testing.T) {
+	_, err := Extract(nil, []byte(`<html><head><title>Empty</title></head><body><script>x()</script></body></html>`))
+	assert.Equal(t, bookmark.ErrNoArticle, err)
+}
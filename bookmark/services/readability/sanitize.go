@@ -0,0 +1,206 @@
+package readability
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Elements kept in the output. Anything else is unwrapped: its children are
+// kept, the element and all of its attributes are not.
+var allowedTags = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Blockquote: true, atom.Br: true,
+	atom.Cite: true, atom.Code: true, atom.Dd: true, atom.Del: true, atom.Dl: true,
+	atom.Dt: true, atom.Em: true, atom.Figcaption: true, atom.Figure: true, atom.H1: true,
+	atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true, atom.Hr: true,
+	atom.I: true, atom.Img: true, atom.Ins: true, atom.Kbd: true, atom.Li: true, atom.Mark: true,
+	atom.Ol: true, atom.P: true, atom.Pre: true, atom.Q: true, atom.S: true, atom.Small: true,
+	atom.Strong: true, atom.Sub: true, atom.Sup: true, atom.Table: true, atom.Tbody: true,
+	atom.Td: true, atom.Tfoot: true, atom.Th: true, atom.Thead: true, atom.Tr: true,
+	atom.U: true, atom.Ul: true,
+}
+
+type renderer struct {
+	base *url.URL
+	html strings.Builder
+	text textWriter
+	pre  int
+}
+
+func (r *renderer) render(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.html.WriteString(html.EscapeString(n.Data))
+		r.text.write(n.Data, r.pre > 0)
+		return
+	case html.ElementNode:
+	default:
+		r.children(n)
+		return
+	}
+
+	tag := n.DataAtom
+	r.breakText(tag)
+
+	switch {
+	case tag == atom.Br:
+		r.html.WriteString("<br>")
+		r.text.newline(1)
+	case tag == atom.Hr:
+		r.html.WriteString("<hr>")
+	case tag == atom.Img:
+		r.image(n)
+	case allowedTags[tag]:
+		r.open(n)
+		if tag == atom.Pre {
+			r.pre++
+		}
+		r.children(n)
+		if tag == atom.Pre {
+			r.pre--
+		}
+		r.html.WriteString("</" + tag.String() + ">")
+	case tag == atom.Div && !hasBlockChild(n):
+		r.html.WriteString("<p>")
+		r.children(n)
+		r.html.WriteString("</p>")
+	default:
+		r.children(n)
+	}
+
+	r.breakText(tag)
+}
+
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.render(c)
+	}
+}
+
+func (r *renderer) breakText(tag atom.Atom) {
+	switch tag {
+	case atom.Li, atom.Tr, atom.Dt, atom.Dd:
+		r.text.newline(1)
+	default:
+		if blockTags[tag] {
+			r.text.newline(2)
+		}
+	}
+}
+
+func (r *renderer) open(n *html.Node) {
+	r.html.WriteString("<" + n.DataAtom.String())
+
+	switch n.DataAtom {
+	case atom.A:
+		if href := r.resolve(attr(n, "href"), "http", "https", "mailto"); href != "" {
+			r.html.WriteString(` href="` + html.EscapeString(href) + `"`)
+		}
+	case atom.Td, atom.Th:
+		for _, key := range []string{"colspan", "rowspan"} {
+			if v, err := strconv.Atoi(attr(n, key)); err == nil && v > 1 && v < 1000 {
+				r.html.WriteString(" " + key + `="` + strconv.Itoa(v) + `"`)
+			}
+		}
+	}
+
+	r.html.WriteString(">")
+}
+
+func (r *renderer) image(n *html.Node) {
+	src := r.resolve(attr(n, "src"), "http", "https")
+	if src == "" {
+		// Lazy loaded images keep the real source aside.
+		src = r.resolve(attr(n, "data-src"), "http", "https")
+	}
+	if src == "" {
+		return
+	}
+
+	r.html.WriteString(`<img src="` + html.EscapeString(src) + `"`)
+	if alt := attr(n, "alt"); alt != "" {
+		r.html.WriteString(` alt="` + html.EscapeString(alt) + `"`)
+	}
+	r.html.WriteString(">")
+}
+
+func (r *renderer) resolve(ref string, schemes ...string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if r.base != nil {
+		u = r.base.ResolveReference(u)
+	}
+
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return u.String()
+		}
+	}
+	return ""
+}
+
+// textWriter collapses whitespace the way a browser would and separates
+// blocks with blank lines.
+type textWriter struct {
+	b       strings.Builder
+	space   bool
+	pending int
+}
+
+func (t *textWriter) write(s string, pre bool) {
+	if pre {
+		t.emit(s)
+		return
+	}
+
+	if r, _ := utf8.DecodeRuneInString(s); unicode.IsSpace(r) {
+		t.space = true
+	}
+	for i, word := range strings.Fields(s) {
+		if i > 0 {
+			t.space = true
+		}
+		t.emit(word)
+	}
+	if r, _ := utf8.DecodeLastRuneInString(s); unicode.IsSpace(r) {
+		t.space = true
+	}
+}
+
+func (t *textWriter) emit(s string) {
+	if s == "" {
+		return
+	}
+
+	if t.b.Len() > 0 {
+		if t.pending > 0 {
+			t.b.WriteString(strings.Repeat("\n", t.pending))
+		} else if t.space {
+			t.b.WriteByte(' ')
+		}
+	}
+	t.space, t.pending = false, 0
+	t.b.WriteString(s)
+}
+
+func (t *textWriter) newline(n int) {
+	if n > t.pending {
+		t.pending = n
+	}
+}
+
+func (t *textWriter) String() string {
+	return t.b.String()
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+type BookmarkRepositorySQL interface {
+	SQLCreateBookmark(ctx context.Context, bookmark *models.Bookmark) error
+	SQLGetBookmark(ctx context.Context, userID, id uint) (*models.Bookmark, error)
+	SQLListBookmarks(ctx context.Context, userID uint, inp models.ListInput) ([]models.Bookmark, error)
+	SQLUpdateBookmark(ctx context.Context, bookmark *models.Bookmark) error
+	SQLDeleteBookmark(ctx context.Context, userID, id uint) error
+	SQLUpdateStates(ctx context.Context, bookmarks []models.Bookmark) error
+	SQLQueue(ctx context.Context, userID uint, inp models.QueueInput) ([]models.Bookmark, error)
+	SQLGetBookmarksByIDs(ctx context.Context, userID uint, ids []uint) ([]models.Bookmark, error)
+	SQLEachBookmark(ctx context.Context, batchSize int, fn func([]models.Bookmark) error) error
+	SQLUpdateMetadata(ctx context.Context, bookmark *models.Bookmark, content *models.BookmarkContent) error
+	SQLGetContent(ctx context.Context, userID, bookmarkID uint) (*models.BookmarkContent, error)
+	SQLGetContentTexts(ctx context.Context, bookmarkIDs []uint) (map[uint]string, error)
+	SQLListNormalizedURLs(ctx context.Context, userID uint) ([]string, error)
+	SQLGetBookmarkByURLHash(ctx context.Context, userID uint, hash string) (*models.Bookmark, error)
+	SQLSetNormalizedURL(ctx context.Context, bookmark *models.Bookmark) error
+	SQLEachUnnormalizedBookmark(ctx context.Context, batchSize int, fn func([]models.Bookmark) error) error
+	SQLEachUserBookmark(ctx context.Context, userID uint, batchSize int, fn func([]models.Bookmark) error) error
+	SQLListLinksToCheck(ctx context.Context, checkedBefore, retryBefore time.Time, limit int) ([]models.Bookmark, error)
+	SQLUpdateLinkStatus(ctx context.Context, bookmark *models.Bookmark) error
+}
+
+type TagRepositorySQL interface {
+	SQLAddTags(ctx context.Context, userID, bookmarkID uint, names []string) ([]models.Tag, error)
+	SQLRemoveTag(ctx context.Context, userID, bookmarkID uint, name string) error
+	SQLListTags(ctx context.Context, userID uint) ([]models.TagCount, error)
+	SQLGetTag(ctx context.Context, userID, id uint) (*models.Tag, error)
+	SQLGetTagByName(ctx context.Context, userID uint, name string) (*models.Tag, error)
+	SQLRenameTag(ctx context.Context, userID, id uint, name string) error
+	SQLMergeTags(ctx context.Context, userID uint, sources []string, target string) (*models.Tag, error)
+	SQLDeleteTag(ctx context.Context, userID, id uint) error
+	SQLListBookmarkIDsByTag(ctx context.Context, userID, tagID uint) ([]uint, error)
+}
+
+type CollectionRepositorySQL interface {
+	SQLCreateCollection(ctx context.Context, collection *models.Collection, parent *models.Collection) error
+	SQLGetCollection(ctx context.Context, userID, id uint) (*models.Collection, error)
+	SQLListCollections(ctx context.Context, userID uint) ([]models.Collection, error)
+	SQLCountCollectionBookmarks(ctx context.Context, userID uint) (map[uint]int64, error)
+	SQLRenameCollection(ctx context.Context, userID, id uint, name string) error
+	SQLMoveCollection(ctx context.Context, collection *models.Collection, parent *models.Collection, position int) error
+	SQLDeleteCollection(ctx context.Context, collection *models.Collection, mode string) ([]uint, error)
+	SQLSetBookmarkCollection(ctx context.Context, userID, bookmarkID uint, collectionID *uint) error
+}
+
+type ShareRepositorySQL interface {
+	SQLCreateShare(ctx context.Context, share *models.Share) error
+	SQLGetShareByToken(ctx context.Context, token string) (*models.Share, error)
+	SQLListShares(ctx context.Context, userID uint) ([]models.Share, error)
+	SQLRevokeShare(ctx context.Context, userID, id uint, at time.Time) error
+	SQLRecordView(ctx context.Context, id uint, at time.Time) error
+	SQLRecordPasswordFailure(ctx context.Context, id uint, attempts int, lockUntil time.Time) error
+	SQLResetPasswordFailures(ctx context.Context, id uint) error
+}
+
+type FeedRepositorySQL interface {
+	SQLCreateFeed(ctx context.Context, feed *models.Feed) error
+	SQLGetFeedByToken(ctx context.Context, token string) (*models.Feed, error)
+	SQLListFeeds(ctx context.Context, userID uint) ([]models.Feed, error)
+	SQLRevokeFeed(ctx context.Context, userID, id uint, at time.Time) error
+	SQLSetFeedChange(ctx context.Context, id uint, etag string, changedAt time.Time) error
+}
+
+type HighlightRepositorySQL interface {
+	SQLCreateHighlight(ctx context.Context, highlight *models.Highlight) error
+	SQLGetHighlight(ctx context.Context, userID, id uint) (*models.Highlight, error)
+	SQLListBookmarkHighlights(ctx context.Context, userID, bookmarkID uint) ([]models.Highlight, error)
+	SQLListHighlights(ctx context.Context, userID uint, limit, offset int) ([]models.Highlight, error)
+	SQLUpdateHighlight(ctx context.Context, highlight *models.Highlight) error
+	SQLUpdateHighlightAnchor(ctx context.Context, highlight *models.Highlight) error
+	SQLDeleteHighlight(ctx context.Context, userID, id uint) error
+}
+
+type ImportRepositorySQL interface {
+	SQLCreateImportJob(ctx context.Context, job *models.ImportJob) error
+	SQLUpdateImportJob(ctx context.Context, job *models.ImportJob, errs []models.ImportError) error
+	SQLGetImportJob(ctx context.Context, userID, id uint) (*models.ImportJob, error)
+	SQLListImportJobs(ctx context.Context, userID uint) ([]models.ImportJob, error)
+	SQLFailUnfinishedImports(ctx context.Context, message string) error
+}
+
+type SearchIndex interface {
+	Index(ctx context.Context, doc models.SearchDocument) error
+	Remove(ctx context.Context, userID, bookmarkID uint) error
+	Search(ctx context.Context, userID uint, query string, limit, offset int) ([]models.SearchHit, int64, error)
+	NeedsRebuild(ctx context.Context) (bool, error)
+}
+
+type PageFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*models.FetchedPage, error)
+}
+
+type LinkChecker interface {
+	Check(ctx context.Context, rawURL string) *models.LinkCheck
+}
+
+type ImportParser interface {
+	Parse(format string, data []byte) (string, []models.ImportItem, error)
+}
+
+type BookmarkEncoder interface {
+	Encode(bookmark *models.Bookmark) error
+	Close() error
+}
+
+type Exporter interface {
+	NewEncoder(format string, w io.Writer) (BookmarkEncoder, error)
+	WriteHighlights(w io.Writer, groups []models.HighlightGroup) error
+	WriteFeed(w io.Writer, format string, feed *models.FeedDocument) error
+}
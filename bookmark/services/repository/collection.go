@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+)
+
+type CollectionRepositorySQL struct {
+	DB *gorm.DB
+}
+
+func InitCollectionRepositorySQL(db *gorm.DB) *CollectionRepositorySQL {
+	return &CollectionRepositorySQL{DB: db}
+}
+
+// SQLCreateCollection adds collection last under parent, or at the top when
+// parent is nil.
+func (r *CollectionRepositorySQL) SQLCreateCollection(ctx context.Context, collection *models.Collection, parent *models.Collection) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := siblingsOf(tx, collection.UserID, collection.ParentID).Count(&count).Error; err != nil {
+			return err
+		}
+		collection.Position = int(count)
+
+		if err := tx.Create(collection).Error; err != nil {
+			return err
+		}
+
+		collection.Path = childPath(parent, collection.ID)
+		return tx.Model(collection).Update("path", collection.Path).Error
+	})
+}
+
+func (r *CollectionRepositorySQL) SQLGetCollection(ctx context.Context, userID, id uint) (*models.Collection, error) {
+	collection := new(models.Collection)
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", id).First(collection).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// SQLListCollections returns every collection of a user, siblings in order.
+func (r *CollectionRepositorySQL) SQLListCollections(ctx context.Context, userID uint) ([]models.Collection, error) {
+	var collections []models.Collection
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("position").Order("id").Find(&collections).Error
+	return collections, err
+}
+
+// SQLCountCollectionBookmarks counts the bookmarks directly in each
+// collection of a user.
+func (r *CollectionRepositorySQL) SQLCountCollectionBookmarks(ctx context.Context, userID uint) (map[uint]int64, error) {
+	var rows []struct {
+		CollectionID uint
+		Count        int64
+	}
+
+	err := r.DB.WithContext(ctx).Model(&models.Bookmark{}).
+		Select("collection_id, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Where("collection_id IS NOT NULL").
+		Group("collection_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *CollectionRepositorySQL) SQLRenameCollection(ctx context.Context, userID, id uint, name string) error {
+	result := r.DB.WithContext(ctx).Model(&models.Collection{}).Where("user_id = ?", userID).Where("id = ?", id).Update("name", name)
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SQLMoveCollection puts collection at position under parent, shifting the
+// siblings it leaves and joins, and rewrites the paths of its subtree.
+func (r *CollectionRepositorySQL) SQLMoveCollection(ctx context.Context, collection *models.Collection, parent *models.Collection, position int) error {
+	var parentID *uint
+	if parent != nil {
+		parentID = &parent.ID
+	}
+	path := childPath(parent, collection.ID)
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := siblingsOf(tx, collection.UserID, collection.ParentID).
+			Where("position > ?", collection.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+		if err != nil {
+			return err
+		}
+
+		err = siblingsOf(tx, collection.UserID, parentID).
+			Where("position >= ?", position).
+			Where("id <> ?", collection.ID).
+			Update("position", gorm.Expr("position + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Collection{}).
+			Where("user_id = ?", collection.UserID).
+			Where("id = ?", collection.ID).
+			Updates(map[string]interface{}{
+				"parent_id": parentID,
+				"position":  position,
+				"path":      path,
+			})
+		if err := result.Error; err != nil {
+			return err
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := rewritePaths(tx, collection, path); err != nil {
+			return err
+		}
+
+		collection.ParentID = parentID
+		collection.Position = position
+		collection.Path = path
+		return nil
+	})
+}
+
+// SQLDeleteCollection removes collection and its share links. With
+// CollectionDeleteMove its bookmarks and child collections move up to its
+// parent; with CollectionDeleteDelete the whole subtree goes, bookmarks
+// included, and the ids of the deleted bookmarks are returned.
+func (r *CollectionRepositorySQL) SQLDeleteCollection(ctx context.Context, collection *models.Collection, mode string) ([]uint, error) {
+	var bookmarkIDs []uint
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := siblingsOf(tx, collection.UserID, collection.ParentID).
+			Where("position > ?", collection.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+		if err != nil {
+			return err
+		}
+
+		if mode == models.CollectionDeleteDelete {
+			var ids []uint
+			err := tx.Model(&models.Collection{}).
+				Where("user_id = ?", collection.UserID).
+				Where("path LIKE ?", collection.Path+"%").
+				Pluck("id", &ids).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&models.Bookmark{}).
+				Where("user_id = ?", collection.UserID).
+				Where("collection_id IN ?", ids).
+				Pluck("id", &bookmarkIDs).Error
+			if err != nil {
+				return err
+			}
+
+			if len(bookmarkIDs) > 0 {
+				if err := deleteBookmarkData(tx, bookmarkIDs); err != nil {
+					return err
+				}
+				if err := tx.Where("id IN ?", bookmarkIDs).Delete(&models.Bookmark{}).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Where("collection_id IN ?", ids).Delete(&models.Share{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("collection_id IN ?", ids).Delete(&models.Feed{}).Error; err != nil {
+				return err
+			}
+
+			return tx.Where("user_id = ?", collection.UserID).Where("id IN ?", ids).Delete(&models.Collection{}).Error
+		}
+
+		err = tx.Model(&models.Bookmark{}).
+			Where("user_id = ?", collection.UserID).
+			Where("collection_id = ?", collection.ID).
+			Update("collection_id", collection.ParentID).Error
+		if err != nil {
+			return err
+		}
+
+		var siblings int64
+		err = siblingsOf(tx, collection.UserID, collection.ParentID).Where("id <> ?", collection.ID).Count(&siblings).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Collection{}).
+			Where("user_id = ?", collection.UserID).
+			Where("parent_id = ?", collection.ID).
+			Updates(map[string]interface{}{
+				"parent_id": collection.ParentID,
+				"position":  gorm.Expr("position + ?", siblings),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		parentPath := strings.TrimSuffix(collection.Path, strconv.FormatUint(uint64(collection.ID), 10)+"/")
+		if err := rewritePaths(tx, collection, parentPath); err != nil {
+			return err
+		}
+
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.Feed{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", collection.UserID).Where("id = ?", collection.ID).Delete(&models.Collection{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bookmarkIDs, nil
+}
+
+// SQLSetBookmarkCollection files a bookmark under a collection, or takes it
+// out of any when collectionID is nil.
+func (r *CollectionRepositorySQL) SQLSetBookmarkCollection(ctx context.Context, userID, bookmarkID uint, collectionID *uint) error {
+	result := r.DB.WithContext(ctx).Model(&models.Bookmark{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", bookmarkID).
+		Update("collection_id", collectionID)
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func siblingsOf(db *gorm.DB, userID uint, parentID *uint) *gorm.DB {
+	db = db.Model(&models.Collection{}).Where("user_id = ?", userID)
+	if parentID == nil {
+		return db.Where("parent_id IS NULL")
+	}
+	return db.Where("parent_id = ?", *parentID)
+}
+
+func childPath(parent *models.Collection, id uint) string {
+	path := "/"
+	if parent != nil {
+		path = parent.Path
+	}
+	return path + strconv.FormatUint(uint64(id), 10) + "/"
+}
+
+// rewritePaths swaps the path prefix of everything below collection. An id
+// appears only once in a path, so the old prefix cannot match anywhere but
+// at the start.
+func rewritePaths(tx *gorm.DB, collection *models.Collection, prefix string) error {
+	return tx.Model(&models.Collection{}).
+		Where("user_id = ?", collection.UserID).
+		Where("path LIKE ?", collection.Path+"%").
+		Where("id <> ?", collection.ID).
+		Update("path", gorm.Expr("REPLACE(path, ?, ?)", collection.Path, prefix)).Error
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func (s *Suite) TestSQLCreateCollection_Success() {
+	parentID := uint(5)
+	parent := &models.Collection{ID: 5, UserID: 1, Path: "/1/5/"}
+	collection := &models.Collection{UserID: 1, ParentID: &parentID, Name: "Go"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `collections` WHERE user_id = ? AND parent_id = ?")).
+		WithArgs(1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `collections` (`user_id`,`parent_id`,`name`,`path`,`position`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?)")).
+		WithArgs(1, 5, "Go", "", 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `path`=?,`updated_at`=? WHERE `id` = ?")).
+		WithArgs("/1/5/9/", sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.collectionRepositorySQL.SQLCreateCollection(context.Background(), collection, parent))
+	s.Equal(uint(9), collection.ID)
+	s.Equal("/1/5/9/", collection.Path)
+	s.Equal(2, collection.Position)
+}
+
+func (s *Suite) TestSQLCreateCollection_Root() {
+	collection := &models.Collection{UserID: 1, Name: "Go"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `collections` WHERE user_id = ? AND parent_id IS NULL")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `collections`")).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `path`=?")).
+		WithArgs("/3/", sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.collectionRepositorySQL.SQLCreateCollection(context.Background(), collection, nil))
+	s.Equal("/3/", collection.Path)
+}
+
+func (s *Suite) TestSQLCountCollectionBookmarks() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT collection_id, COUNT(*) AS count FROM `bookmarks` WHERE user_id = ? AND collection_id IS NOT NULL GROUP BY `collection_id`")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"collection_id", "count"}).AddRow(5, 3).AddRow(9, 1))
+
+	counts, err := s.collectionRepositorySQL.SQLCountCollectionBookmarks(context.Background(), 1)
+	require.NoError(s.T(), err)
+	s.Equal(map[uint]int64{5: 3, 9: 1}, counts)
+}
+
+func (s *Suite) TestSQLMoveCollection_Success() {
+	oldParent := uint(1)
+	collection := &models.Collection{ID: 5, UserID: 1, ParentID: &oldParent, Path: "/1/5/", Position: 1}
+	parent := &models.Collection{ID: 2, UserID: 1, Path: "/2/"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `position`=position - 1,`updated_at`=? WHERE user_id = ? AND parent_id = ? AND position > ?")).
+		WithArgs(sqlmock.AnyArg(), 1, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `position`=position + 1,`updated_at`=? WHERE user_id = ? AND parent_id = ? AND position >= ? AND id <> ?")).
+		WithArgs(sqlmock.AnyArg(), 1, 2, 0, 5).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `parent_id`=?,`path`=?,`position`=?,`updated_at`=? WHERE user_id = ? AND id = ?")).
+		WithArgs(2, "/2/5/", 0, sqlmock.AnyArg(), 1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `path`=REPLACE(path, ?, ?),`updated_at`=? WHERE user_id = ? AND path LIKE ? AND id <> ?")).
+		WithArgs("/1/5/", "/2/5/", sqlmock.AnyArg(), 1, "/1/5/%", 5).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.collectionRepositorySQL.SQLMoveCollection(context.Background(), collection, parent, 0))
+	s.Equal("/2/5/", collection.Path)
+	s.Equal(uint(2), *collection.ParentID)
+}
+
+func (s *Suite) TestSQLMoveCollection_Failed_ZeroRowAffected() {
+	collection := &models.Collection{ID: 5, UserID: 2, Path: "/5/"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `position`=position - 1")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `position`=position + 1")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `parent_id`=?")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	s.Equal(gorm.ErrRecordNotFound, s.collectionRepositorySQL.SQLMoveCollection(context.Background(), collection, nil, 0))
+}
+
+func (s *Suite) TestSQLDeleteCollection_MoveChildren() {
+	parentID := uint(1)
+	collection := &models.Collection{ID: 5, UserID: 1, ParentID: &parentID, Path: "/1/5/", Position: 0}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `position`=position - 1,`updated_at`=? WHERE user_id = ? AND parent_id = ? AND position > ?")).
+		WithArgs(sqlmock.AnyArg(), 1, 1, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET `collection_id`=?,`updated_at`=? WHERE user_id = ? AND collection_id = ?")).
+		WithArgs(1, sqlmock.AnyArg(), 1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `collections` WHERE user_id = ? AND parent_id = ? AND id <> ?")).
+		WithArgs(1, 1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `parent_id`=?,`position`=position + ?,`updated_at`=? WHERE user_id = ? AND parent_id = ?")).
+		WithArgs(1, 1, sqlmock.AnyArg(), 1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `path`=REPLACE(path, ?, ?)")).
+		WithArgs("/1/5/", "/1/", sqlmock.AnyArg(), 1, "/1/5/%", 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `shares` WHERE collection_id = ?")).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `feeds` WHERE collection_id = ?")).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `collections` WHERE user_id = ? AND id = ?")).
+		WithArgs(1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	deleted, err := s.collectionRepositorySQL.SQLDeleteCollection(context.Background(), collection, models.CollectionDeleteMove)
+	require.NoError(s.T(), err)
+	s.Empty(deleted)
+}
+
+func (s *Suite) TestSQLDeleteCollection_DeleteSubtree() {
+	collection := &models.Collection{ID: 5, UserID: 1, Path: "/5/", Position: 2}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `collections` SET `position`=position - 1,`updated_at`=? WHERE user_id = ? AND parent_id IS NULL AND position > ?")).
+		WithArgs(sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `collections` WHERE user_id = ? AND path LIKE ?")).
+		WithArgs(1, "/5/%").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(9))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `bookmarks` WHERE user_id = ? AND collection_id IN (?,?)")).
+		WithArgs(1, 5, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7).AddRow(8))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmark_tags` WHERE bookmark_id IN (?,?)")).
+		WithArgs(7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmark_contents` WHERE bookmark_id IN (?,?)")).
+		WithArgs(7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `shares` WHERE bookmark_id IN (?,?)")).
+		WithArgs(7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `highlights` WHERE bookmark_id IN (?,?)")).
+		WithArgs(7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmarks` WHERE id IN (?,?)")).
+		WithArgs(7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `shares` WHERE collection_id IN (?,?)")).
+		WithArgs(5, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `feeds` WHERE collection_id IN (?,?)")).
+		WithArgs(5, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `collections` WHERE user_id = ? AND id IN (?,?)")).
+		WithArgs(1, 5, 9).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectCommit()
+
+	deleted, err := s.collectionRepositorySQL.SQLDeleteCollection(context.Background(), collection, models.CollectionDeleteDelete)
+	require.NoError(s.T(), err)
+	s.Equal([]uint{7, 8}, deleted)
+}
+
+func (s *Suite) TestSQLSetBookmarkCollection_NotFound() {
+	collectionID := uint(5)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET `collection_id`=?,`updated_at`=? WHERE user_id = ? AND id = ?")).
+		WithArgs(5, sqlmock.AnyArg(), 2, 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	s.Equal(gorm.ErrRecordNotFound, s.collectionRepositorySQL.SQLSetBookmarkCollection(context.Background(), 2, 7, &collectionID))
+}
+
+func (s *Suite) TestSQLListBookmarks_CollectionSubtree() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `path` FROM `collections` WHERE user_id = ? AND id = ?")).
+		WithArgs(1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("/1/5/"))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND collection_id IN (SELECT `id` FROM `collections` WHERE user_id = ? AND path LIKE ?) ORDER BY created_at desc,id desc LIMIT 20")).
+		WithArgs(1, 1, "/1/5/%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, models.ListInput{Limit: 20, Collection: 5, Recursive: true})
+	require.NoError(s.T(), err)
+	s.Empty(res)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+)
+
+type FeedRepositorySQL struct {
+	DB *gorm.DB
+}
+
+func InitFeedRepositorySQL(db *gorm.DB) *FeedRepositorySQL {
+	return &FeedRepositorySQL{DB: db}
+}
+
+func (r *FeedRepositorySQL) SQLCreateFeed(ctx context.Context, feed *models.Feed) error {
+	return r.DB.WithContext(ctx).Create(feed).Error
+}
+
+func (r *FeedRepositorySQL) SQLGetFeedByToken(ctx context.Context, token string) (*models.Feed, error) {
+	feed := new(models.Feed)
+	err := r.DB.WithContext(ctx).Where("token = ?", token).First(feed).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (r *FeedRepositorySQL) SQLListFeeds(ctx context.Context, userID uint) ([]models.Feed, error) {
+	var feeds []models.Feed
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Order("id desc").Find(&feeds).Error
+	return feeds, err
+}
+
+// SQLRevokeFeed marks a feed revoked; revoking it again is not found.
+func (r *FeedRepositorySQL) SQLRevokeFeed(ctx context.Context, userID, id uint, at time.Time) error {
+	result := r.DB.WithContext(ctx).Model(&models.Feed{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", at)
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SQLSetFeedChange records what a feed served last and since when.
+func (r *FeedRepositorySQL) SQLSetFeedChange(ctx context.Context, id uint, etag string, changedAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Feed{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"etag": etag, "changed_at": changedAt}).Error
+}
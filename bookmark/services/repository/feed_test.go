@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func (s *Suite) TestSQLCreateFeed_Success() {
+	tagID := uint(4)
+	feed := &models.Feed{UserID: 1, Token: "abc", TagID: &tagID}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `feeds` (`user_id`,`token`,`tag_id`,`collection_id`,`revoked_at`,`created_at`,`etag`,`changed_at`) VALUES (?,?,?,?,?,?,?,?)")).
+		WithArgs(1, "abc", 4, nil, nil, sqlmock.AnyArg(), "", nil).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.feedRepositorySQL.SQLCreateFeed(context.Background(), feed))
+	s.Equal(uint(2), feed.ID)
+}
+
+func (s *Suite) TestSQLGetFeedByToken_Success() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `feeds` WHERE token = ? ORDER BY `feeds`.`id` LIMIT 1")).
+		WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token", "tag_id"}).AddRow(2, 1, "abc", 4))
+
+	feed, err := s.feedRepositorySQL.SQLGetFeedByToken(context.Background(), "abc")
+	require.NoError(s.T(), err)
+	s.Equal(uint(4), *feed.TagID)
+	s.Nil(feed.CollectionID)
+}
+
+func (s *Suite) TestSQLRevokeFeed() {
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `feeds` SET `revoked_at`=? WHERE user_id = ? AND id = ? AND revoked_at IS NULL")).
+		WithArgs(now, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	s.Equal(gorm.ErrRecordNotFound, s.feedRepositorySQL.SQLRevokeFeed(context.Background(), 1, 2, now))
+}
+
+func (s *Suite) TestSQLSetFeedChange() {
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `feeds` SET `changed_at`=?,`etag`=? WHERE id = ?")).
+		WithArgs(now, "e1", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.feedRepositorySQL.SQLSetFeedChange(context.Background(), 2, "e1", now))
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+)
+
+type HighlightRepositorySQL struct {
+	DB *gorm.DB
+}
+
+func InitHighlightRepositorySQL(db *gorm.DB) *HighlightRepositorySQL {
+	return &HighlightRepositorySQL{DB: db}
+}
+
+func (r *HighlightRepositorySQL) SQLCreateHighlight(ctx context.Context, highlight *models.Highlight) error {
+	return r.DB.WithContext(ctx).Create(highlight).Error
+}
+
+func (r *HighlightRepositorySQL) SQLGetHighlight(ctx context.Context, userID, id uint) (*models.Highlight, error) {
+	highlight := new(models.Highlight)
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", id).First(highlight).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return highlight, nil
+}
+
+// SQLListBookmarkHighlights lists the highlights of one bookmark in the
+// order they appear in the article.
+func (r *HighlightRepositorySQL) SQLListBookmarkHighlights(ctx context.Context, userID, bookmarkID uint) ([]models.Highlight, error) {
+	var highlights []models.Highlight
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Where("bookmark_id = ?", bookmarkID).
+		Order("start_offset").Order("id").
+		Find(&highlights).Error
+	return highlights, err
+}
+
+// SQLListHighlights lists the highlights of a user, newest first. A limit of
+// 0 lists all of them.
+func (r *HighlightRepositorySQL) SQLListHighlights(ctx context.Context, userID uint, limit, offset int) ([]models.Highlight, error) {
+	var highlights []models.Highlight
+
+	query := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Order("id desc")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	err := query.Find(&highlights).Error
+	return highlights, err
+}
+
+func (r *HighlightRepositorySQL) SQLUpdateHighlight(ctx context.Context, highlight *models.Highlight) error {
+	result := r.DB.WithContext(ctx).Model(highlight).
+		Where("user_id = ?", highlight.UserID).
+		Select("start_offset", "end_offset", "prefix", "suffix", "note", "color").
+		Updates(highlight)
+
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SQLUpdateHighlightAnchor stores where a highlight is in the article now.
+// Moving along with the article is not an edit, so updated_at stays.
+func (r *HighlightRepositorySQL) SQLUpdateHighlightAnchor(ctx context.Context, highlight *models.Highlight) error {
+	result := r.DB.WithContext(ctx).Model(highlight).
+		Where("user_id = ?", highlight.UserID).
+		UpdateColumns(map[string]interface{}{
+			"start_offset": highlight.StartOffset,
+			"end_offset":   highlight.EndOffset,
+			"prefix":       highlight.Prefix,
+			"suffix":       highlight.Suffix,
+		})
+
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *HighlightRepositorySQL) SQLDeleteHighlight(ctx context.Context, userID, id uint) error {
+	result := r.DB.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", id).Delete(&models.Highlight{})
+
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
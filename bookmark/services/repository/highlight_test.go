@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func (s *Suite) TestSQLCreateHighlight_Success() {
+	highlight := &models.Highlight{UserID: 1, BookmarkID: 7, StartOffset: 4, EndOffset: 9, Quote: "quick", Prefix: "The ", Suffix: " brown", Color: "yellow"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `highlights` (`user_id`,`bookmark_id`,`start_offset`,`end_offset`,`quote`,`prefix`,`suffix`,`note`,`color`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?)")).
+		WithArgs(1, 7, 4, 9, "quick", "The ", " brown", "", "yellow", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.highlightRepositorySQL.SQLCreateHighlight(context.Background(), highlight))
+	s.Equal(uint(3), highlight.ID)
+}
+
+func (s *Suite) TestSQLGetHighlight_NotFound() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `highlights` WHERE user_id = ? AND id = ? ORDER BY `highlights`.`id` LIMIT 1")).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	highlight, err := s.highlightRepositorySQL.SQLGetHighlight(context.Background(), 1, 3)
+	s.Nil(highlight)
+	s.Equal(gorm.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestSQLListBookmarkHighlights() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `highlights` WHERE user_id = ? AND bookmark_id = ? ORDER BY start_offset,id")).
+		WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "bookmark_id", "quote"}).AddRow(3, 7, "quick").AddRow(4, 7, "lazy"))
+
+	highlights, err := s.highlightRepositorySQL.SQLListBookmarkHighlights(context.Background(), 1, 7)
+	require.NoError(s.T(), err)
+	s.Len(highlights, 2)
+	s.Equal("lazy", highlights[1].Quote)
+}
+
+func (s *Suite) TestSQLListHighlights() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `highlights` WHERE user_id = ? ORDER BY created_at desc,id desc LIMIT 20 OFFSET 40")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	highlights, err := s.highlightRepositorySQL.SQLListHighlights(context.Background(), 1, 20, 40)
+	require.NoError(s.T(), err)
+	s.Len(highlights, 1)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `highlights` WHERE user_id = ? ORDER BY created_at desc,id desc")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = s.highlightRepositorySQL.SQLListHighlights(context.Background(), 1, 0, 0)
+	s.NoError(err)
+}
+
+func (s *Suite) TestSQLUpdateHighlight() {
+	highlight := &models.Highlight{ID: 3, UserID: 1, StartOffset: 10, EndOffset: 15, Quote: "quick", Prefix: "The very ", Suffix: " brown", Note: "nice", Color: "green"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `highlights` SET `start_offset`=?,`end_offset`=?,`prefix`=?,`suffix`=?,`note`=?,`color`=?,`updated_at`=? WHERE user_id = ? AND `id` = ?")).
+		WithArgs(10, 15, "The very ", " brown", "nice", "green", sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.NoError(s.highlightRepositorySQL.SQLUpdateHighlight(context.Background(), highlight))
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `highlights` SET")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+	s.Equal(gorm.ErrRecordNotFound, s.highlightRepositorySQL.SQLUpdateHighlight(context.Background(), highlight))
+}
+
+func (s *Suite) TestSQLUpdateHighlightAnchor() {
+	highlight := &models.Highlight{ID: 3, UserID: 1, StartOffset: 10, EndOffset: 15, Quote: "quick", Prefix: "The very ", Suffix: " brown", Note: "nice"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `highlights` SET `end_offset`=?,`prefix`=?,`start_offset`=?,`suffix`=? WHERE user_id = ? AND `id` = ?")).
+		WithArgs(15, "The very ", 10, " brown", 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.NoError(s.highlightRepositorySQL.SQLUpdateHighlightAnchor(context.Background(), highlight))
+}
+
+func (s *Suite) TestSQLDeleteHighlight() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `highlights` WHERE user_id = ? AND id = ?")).
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	s.Equal(gorm.ErrRecordNotFound, s.highlightRepositorySQL.SQLDeleteHighlight(context.Background(), 1, 3))
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+)
+
+type ImportRepositorySQL struct {
+	DB *gorm.DB
+}
+
+func InitImportRepositorySQL(db *gorm.DB) *ImportRepositorySQL {
+	return &ImportRepositorySQL{DB: db}
+}
+
+func (r *ImportRepositorySQL) SQLCreateImportJob(ctx context.Context, job *models.ImportJob) error {
+	return r.DB.WithContext(ctx).Omit("Errors").Create(job).Error
+}
+
+// SQLUpdateImportJob saves the progress of a job along with the row errors
+// found since the last update.
+func (r *ImportRepositorySQL) SQLUpdateImportJob(ctx context.Context, job *models.ImportJob, errs []models.ImportError) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(job).
+			Select("status", "processed", "imported", "duplicates", "failed", "error", "finished_at").
+			Updates(job).Error
+		if err != nil {
+			return err
+		}
+
+		if len(errs) == 0 {
+			return nil
+		}
+		for i := range errs {
+			errs[i].JobID = job.ID
+		}
+		return tx.Create(&errs).Error
+	})
+}
+
+func (r *ImportRepositorySQL) SQLGetImportJob(ctx context.Context, userID, id uint) (*models.ImportJob, error) {
+	job := new(models.ImportJob)
+	err := r.DB.WithContext(ctx).Preload("Errors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line, id")
+	}).Where("user_id = ?", userID).Where("id = ?", id).First(job).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *ImportRepositorySQL) SQLListImportJobs(ctx context.Context, userID uint) ([]models.ImportJob, error) {
+	var jobs []models.ImportJob
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id desc").Find(&jobs).Error
+	return jobs, err
+}
+
+// SQLFailUnfinishedImports marks jobs that were still running as failed. It
+// runs at startup, when nothing can be working on them anymore.
+func (r *ImportRepositorySQL) SQLFailUnfinishedImports(ctx context.Context, message string) error {
+	return r.DB.WithContext(ctx).Model(&models.ImportJob{}).
+		Where("status = ?", models.ImportStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      models.ImportStatusFailed,
+			"error":       message,
+			"finished_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func (s *Suite) TestSQLUpdateImportJob_WithErrors() {
+	now := time.Now()
+	job := &models.ImportJob{ID: 3, Status: models.ImportStatusDone, Processed: 2, Imported: 1, Failed: 1, FinishedAt: &now}
+	errs := []models.ImportError{{Row: 2, URL: "ftp://example.com/", Error: "url tidak valid"}}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `import_jobs` SET `status`=?,`processed`=?,`imported`=?,`duplicates`=?,`failed`=?,`error`=?,`updated_at`=?,`finished_at`=? WHERE `id` = ?")).
+		WithArgs(models.ImportStatusDone, 2, 1, 0, 1, "", sqlmock.AnyArg(), now, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `import_errors` (`job_id`,`line`,`url`,`error`) VALUES (?,?,?,?)")).
+		WithArgs(3, 2, "ftp://example.com/", "url tidak valid").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.importRepositorySQL.SQLUpdateImportJob(context.Background(), job, errs))
+}
+
+func (s *Suite) TestSQLGetImportJob_Success() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `import_jobs` WHERE user_id = ? AND id = ? ORDER BY `import_jobs`.`id` LIMIT 1")).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status", "total"}).AddRow(3, 1, models.ImportStatusDone, 2))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `import_errors` WHERE `import_errors`.`job_id` = ? ORDER BY line, id")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "job_id", "line", "url", "error"}).AddRow(1, 3, 2, "ftp://example.com/", "url tidak valid"))
+
+	job, err := s.importRepositorySQL.SQLGetImportJob(context.Background(), 1, 3)
+	require.NoError(s.T(), err)
+	s.Equal(2, job.Total)
+	s.Equal([]models.ImportError{{ID: 1, JobID: 3, Row: 2, URL: "ftp://example.com/", Error: "url tidak valid"}}, job.Errors)
+}
+
+func (s *Suite) TestSQLGetImportJob_NotFound() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `import_jobs`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.importRepositorySQL.SQLGetImportJob(context.Background(), 2, 3)
+	s.Equal(gorm.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestSQLFailUnfinishedImports() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `import_jobs` SET `error`=?,`finished_at`=?,`status`=?,`updated_at`=? WHERE status = ?")).
+		WithArgs("interrupted", sqlmock.AnyArg(), models.ImportStatusFailed, sqlmock.AnyArg(), models.ImportStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.importRepositorySQL.SQLFailUnfinishedImports(context.Background(), "interrupted"))
+}
@@ -0,0 +1,412 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type BookmarkStorageMock struct {
+	mock.Mock
+}
+
+func (s *BookmarkStorageMock) SQLCreateBookmark(ctx context.Context, bookmark *models.Bookmark) error {
+	args := s.Called(bookmark)
+
+	return args.Error(0)
+}
+
+func (s *BookmarkStorageMock) SQLGetBookmark(ctx context.Context, userID, id uint) (*models.Bookmark, error) {
+	args := s.Called(userID, id)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLListBookmarks(ctx context.Context, userID uint, inp models.ListInput) ([]models.Bookmark, error) {
+	args := s.Called(userID, inp)
+
+	return args.Get(0).([]models.Bookmark), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLUpdateBookmark(ctx context.Context, bookmark *models.Bookmark) error {
+	args := s.Called(bookmark)
+
+	return args.Error(0)
+}
+
+func (s *BookmarkStorageMock) SQLDeleteBookmark(ctx context.Context, userID, id uint) error {
+	args := s.Called(userID, id)
+
+	return args.Error(0)
+}
+
+func (s *BookmarkStorageMock) SQLUpdateStates(ctx context.Context, bookmarks []models.Bookmark) error {
+	args := s.Called(bookmarks)
+
+	return args.Error(0)
+}
+
+func (s *BookmarkStorageMock) SQLQueue(ctx context.Context, userID uint, inp models.QueueInput) ([]models.Bookmark, error) {
+	args := s.Called(userID, inp)
+
+	return args.Get(0).([]models.Bookmark), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLGetBookmarksByIDs(ctx context.Context, userID uint, ids []uint) ([]models.Bookmark, error) {
+	args := s.Called(userID, ids)
+
+	return args.Get(0).([]models.Bookmark), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLEachBookmark(ctx context.Context, batchSize int, fn func([]models.Bookmark) error) error {
+	args := s.Called(batchSize)
+
+	if batch, ok := args.Get(0).([]models.Bookmark); ok && len(batch) > 0 {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLUpdateMetadata(ctx context.Context, bookmark *models.Bookmark, content *models.BookmarkContent) error {
+	args := s.Called(bookmark, content)
+
+	return args.Error(0)
+}
+
+func (s *BookmarkStorageMock) SQLListNormalizedURLs(ctx context.Context, userID uint) ([]string, error) {
+	args := s.Called(userID)
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLGetBookmarkByURLHash(ctx context.Context, userID uint, hash string) (*models.Bookmark, error) {
+	args := s.Called(userID, hash)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLSetNormalizedURL(ctx context.Context, bookmark *models.Bookmark) error {
+	args := s.Called(bookmark)
+
+	return args.Error(0)
+}
+
+func (s *BookmarkStorageMock) SQLEachUnnormalizedBookmark(ctx context.Context, batchSize int, fn func([]models.Bookmark) error) error {
+	args := s.Called(batchSize)
+
+	if batch, ok := args.Get(0).([]models.Bookmark); ok && len(batch) > 0 {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLEachUserBookmark(ctx context.Context, userID uint, batchSize int, fn func([]models.Bookmark) error) error {
+	args := s.Called(userID, batchSize)
+
+	if batch, ok := args.Get(0).([]models.Bookmark); ok && len(batch) > 0 {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLListLinksToCheck(ctx context.Context, checkedBefore, retryBefore time.Time, limit int) ([]models.Bookmark, error) {
+	args := s.Called(checkedBefore, retryBefore, limit)
+
+	return args.Get(0).([]models.Bookmark), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLUpdateLinkStatus(ctx context.Context, bookmark *models.Bookmark) error {
+	args := s.Called(bookmark)
+
+	return args.Error(0)
+}
+
+func (s *BookmarkStorageMock) SQLGetContent(ctx context.Context, userID, bookmarkID uint) (*models.BookmarkContent, error) {
+	args := s.Called(userID, bookmarkID)
+
+	return args.Get(0).(*models.BookmarkContent), args.Error(1)
+}
+
+func (s *BookmarkStorageMock) SQLGetContentTexts(ctx context.Context, bookmarkIDs []uint) (map[uint]string, error) {
+	args := s.Called(bookmarkIDs)
+
+	return args.Get(0).(map[uint]string), args.Error(1)
+}
+
+type TagStorageMock struct {
+	mock.Mock
+}
+
+func (s *TagStorageMock) SQLAddTags(ctx context.Context, userID, bookmarkID uint, names []string) ([]models.Tag, error) {
+	args := s.Called(userID, bookmarkID, names)
+
+	return args.Get(0).([]models.Tag), args.Error(1)
+}
+
+func (s *TagStorageMock) SQLRemoveTag(ctx context.Context, userID, bookmarkID uint, name string) error {
+	args := s.Called(userID, bookmarkID, name)
+
+	return args.Error(0)
+}
+
+func (s *TagStorageMock) SQLListTags(ctx context.Context, userID uint) ([]models.TagCount, error) {
+	args := s.Called(userID)
+
+	return args.Get(0).([]models.TagCount), args.Error(1)
+}
+
+func (s *TagStorageMock) SQLGetTag(ctx context.Context, userID, id uint) (*models.Tag, error) {
+	args := s.Called(userID, id)
+
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (s *TagStorageMock) SQLGetTagByName(ctx context.Context, userID uint, name string) (*models.Tag, error) {
+	args := s.Called(userID, name)
+
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (s *TagStorageMock) SQLRenameTag(ctx context.Context, userID, id uint, name string) error {
+	args := s.Called(userID, id, name)
+
+	return args.Error(0)
+}
+
+func (s *TagStorageMock) SQLMergeTags(ctx context.Context, userID uint, sources []string, target string) (*models.Tag, error) {
+	args := s.Called(userID, sources, target)
+
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (s *TagStorageMock) SQLDeleteTag(ctx context.Context, userID, id uint) error {
+	args := s.Called(userID, id)
+
+	return args.Error(0)
+}
+
+func (s *TagStorageMock) SQLListBookmarkIDsByTag(ctx context.Context, userID, tagID uint) ([]uint, error) {
+	args := s.Called(userID, tagID)
+
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+type CollectionStorageMock struct {
+	mock.Mock
+}
+
+func (s *CollectionStorageMock) SQLCreateCollection(ctx context.Context, collection *models.Collection, parent *models.Collection) error {
+	args := s.Called(collection, parent)
+
+	return args.Error(0)
+}
+
+func (s *CollectionStorageMock) SQLGetCollection(ctx context.Context, userID, id uint) (*models.Collection, error) {
+	args := s.Called(userID, id)
+
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (s *CollectionStorageMock) SQLListCollections(ctx context.Context, userID uint) ([]models.Collection, error) {
+	args := s.Called(userID)
+
+	return args.Get(0).([]models.Collection), args.Error(1)
+}
+
+func (s *CollectionStorageMock) SQLCountCollectionBookmarks(ctx context.Context, userID uint) (map[uint]int64, error) {
+	args := s.Called(userID)
+
+	return args.Get(0).(map[uint]int64), args.Error(1)
+}
+
+func (s *CollectionStorageMock) SQLRenameCollection(ctx context.Context, userID, id uint, name string) error {
+	args := s.Called(userID, id, name)
+
+	return args.Error(0)
+}
+
+func (s *CollectionStorageMock) SQLMoveCollection(ctx context.Context, collection *models.Collection, parent *models.Collection, position int) error {
+	args := s.Called(collection, parent, position)
+
+	return args.Error(0)
+}
+
+func (s *CollectionStorageMock) SQLDeleteCollection(ctx context.Context, collection *models.Collection, mode string) ([]uint, error) {
+	args := s.Called(collection, mode)
+
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (s *CollectionStorageMock) SQLSetBookmarkCollection(ctx context.Context, userID, bookmarkID uint, collectionID *uint) error {
+	args := s.Called(userID, bookmarkID, collectionID)
+
+	return args.Error(0)
+}
+
+type ShareStorageMock struct {
+	mock.Mock
+}
+
+func (s *ShareStorageMock) SQLCreateShare(ctx context.Context, share *models.Share) error {
+	args := s.Called(share)
+
+	return args.Error(0)
+}
+
+func (s *ShareStorageMock) SQLGetShareByToken(ctx context.Context, token string) (*models.Share, error) {
+	args := s.Called(token)
+
+	return args.Get(0).(*models.Share), args.Error(1)
+}
+
+func (s *ShareStorageMock) SQLListShares(ctx context.Context, userID uint) ([]models.Share, error) {
+	args := s.Called(userID)
+
+	return args.Get(0).([]models.Share), args.Error(1)
+}
+
+func (s *ShareStorageMock) SQLRevokeShare(ctx context.Context, userID, id uint, at time.Time) error {
+	args := s.Called(userID, id, at)
+
+	return args.Error(0)
+}
+
+func (s *ShareStorageMock) SQLRecordView(ctx context.Context, id uint, at time.Time) error {
+	args := s.Called(id, at)
+
+	return args.Error(0)
+}
+
+func (s *ShareStorageMock) SQLRecordPasswordFailure(ctx context.Context, id uint, attempts int, lockUntil time.Time) error {
+	args := s.Called(id, attempts, lockUntil)
+
+	return args.Error(0)
+}
+
+func (s *ShareStorageMock) SQLResetPasswordFailures(ctx context.Context, id uint) error {
+	args := s.Called(id)
+
+	return args.Error(0)
+}
+
+type FeedStorageMock struct {
+	mock.Mock
+}
+
+func (s *FeedStorageMock) SQLCreateFeed(ctx context.Context, feed *models.Feed) error {
+	args := s.Called(feed)
+
+	return args.Error(0)
+}
+
+func (s *FeedStorageMock) SQLGetFeedByToken(ctx context.Context, token string) (*models.Feed, error) {
+	args := s.Called(token)
+
+	return args.Get(0).(*models.Feed), args.Error(1)
+}
+
+func (s *FeedStorageMock) SQLListFeeds(ctx context.Context, userID uint) ([]models.Feed, error) {
+	args := s.Called(userID)
+
+	return args.Get(0).([]models.Feed), args.Error(1)
+}
+
+func (s *FeedStorageMock) SQLRevokeFeed(ctx context.Context, userID, id uint, at time.Time) error {
+	args := s.Called(userID, id, at)
+
+	return args.Error(0)
+}
+
+func (s *FeedStorageMock) SQLSetFeedChange(ctx context.Context, id uint, etag string, changedAt time.Time) error {
+	args := s.Called(id, etag, changedAt)
+
+	return args.Error(0)
+}
+
+type ImportStorageMock struct {
+	mock.Mock
+}
+
+func (s *ImportStorageMock) SQLCreateImportJob(ctx context.Context, job *models.ImportJob) error {
+	args := s.Called(job)
+
+	return args.Error(0)
+}
+
+func (s *ImportStorageMock) SQLUpdateImportJob(ctx context.Context, job *models.ImportJob, errs []models.ImportError) error {
+	args := s.Called(job, errs)
+
+	return args.Error(0)
+}
+
+func (s *ImportStorageMock) SQLGetImportJob(ctx context.Context, userID, id uint) (*models.ImportJob, error) {
+	args := s.Called(userID, id)
+
+	return args.Get(0).(*models.ImportJob), args.Error(1)
+}
+
+func (s *ImportStorageMock) SQLListImportJobs(ctx context.Context, userID uint) ([]models.ImportJob, error) {
+	args := s.Called(userID)
+
+	return args.Get(0).([]models.ImportJob), args.Error(1)
+}
+
+func (s *ImportStorageMock) SQLFailUnfinishedImports(ctx context.Context, message string) error {
+	args := s.Called(message)
+
+	return args.Error(0)
+}
+
+type HighlightStorageMock struct {
+	mock.Mock
+}
+
+func (s *HighlightStorageMock) SQLCreateHighlight(ctx context.Context, highlight *models.Highlight) error {
+	args := s.Called(highlight)
+
+	return args.Error(0)
+}
+
+func (s *HighlightStorageMock) SQLGetHighlight(ctx context.Context, userID, id uint) (*models.Highlight, error) {
+	args := s.Called(userID, id)
+
+	return args.Get(0).(*models.Highlight), args.Error(1)
+}
+
+func (s *HighlightStorageMock) SQLListBookmarkHighlights(ctx context.Context, userID, bookmarkID uint) ([]models.Highlight, error) {
+	args := s.Called(userID, bookmarkID)
+
+	return args.Get(0).([]models.Highlight), args.Error(1)
+}
+
+func (s *HighlightStorageMock) SQLListHighlights(ctx context.Context, userID uint, limit, offset int) ([]models.Highlight, error) {
+	args := s.Called(userID, limit, offset)
+
+	return args.Get(0).([]models.Highlight), args.Error(1)
+}
+
+func (s *HighlightStorageMock) SQLUpdateHighlight(ctx context.Context, highlight *models.Highlight) error {
+	args := s.Called(highlight)
+
+	return args.Error(0)
+}
+
+func (s *HighlightStorageMock) SQLUpdateHighlightAnchor(ctx context.Context, highlight *models.Highlight) error {
+	args := s.Called(highlight)
+
+	return args.Error(0)
+}
+
+func (s *HighlightStorageMock) SQLDeleteHighlight(ctx context.Context, userID, id uint) error {
+	args := s.Called(userID, id)
+
+	return args.Error(0)
+}
@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var linkColumns = []string{"link_status", "link_code", "redirect_url", "link_error", "link_failures", "link_checked_at"}
+
+var stateColumns = []string{"status", "favorite", "priority", "progress", "read_at", "archived_at", "favorited_at"}
+
+// Bookmarks whose reading time is not known yet go after the others when
+// ordering by it.
+var queueOrders = map[string][]string{
+	models.QueueOrderPriority: {"priority desc", "created_at", "id"},
+	models.QueueOrderOldest:   {"created_at", "id"},
+	models.QueueOrderNewest:   {"created_at desc", "id desc"},
+	models.QueueOrderShortest: {"reading_time = 0", "reading_time", "created_at", "id"},
+	models.QueueOrderLongest:  {"reading_time desc", "created_at", "id"},
+}
+
+type BookmarkRepositorySQL struct {
+	DB *gorm.DB
+}
+
+func InitBookmarkRepositorySQL(db *gorm.DB) *BookmarkRepositorySQL {
+	return &BookmarkRepositorySQL{DB: db}
+}
+
+func (r *BookmarkRepositorySQL) SQLCreateBookmark(ctx context.Context, bookmark *models.Bookmark) error {
+	tx := r.DB.WithContext(ctx).Begin()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := tx.Create(bookmark).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *BookmarkRepositorySQL) SQLGetBookmark(ctx context.Context, userID, id uint) (*models.Bookmark, error) {
+	bookmark := new(models.Bookmark)
+	err := r.DB.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID).Where("id = ?", id).First(bookmark).Error
+	return bookmark, err
+}
+
+func (r *BookmarkRepositorySQL) SQLListBookmarks(ctx context.Context, userID uint, inp models.ListInput) ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+
+	query := r.DB.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID)
+	if len(inp.TagNames) > 0 {
+		tagged := r.DB.WithContext(ctx).Model(&models.BookmarkTag{}).
+			Select("bookmark_tags.bookmark_id").
+			Joins("JOIN tags ON tags.id = bookmark_tags.tag_id").
+			Where("tags.user_id = ?", userID).
+			Where("tags.name IN ?", inp.TagNames)
+		if inp.TagMode == models.TagModeAll {
+			tagged = tagged.Group("bookmark_tags.bookmark_id").Having("COUNT(DISTINCT tags.id) = ?", len(inp.TagNames))
+		}
+		query = query.Where("id IN (?)", tagged)
+	}
+	if inp.Status != "" {
+		query = query.Where("status = ?", inp.Status)
+	}
+	if inp.Favorite {
+		query = query.Where("favorite = ?", true)
+	}
+	if inp.Link != "" {
+		query = query.Where("link_status = ?", inp.Link)
+	}
+	if inp.Public {
+		query = query.Where("public = ?", true)
+	}
+	if inp.Collection != 0 {
+		if !inp.Recursive {
+			query = query.Where("collection_id = ?", inp.Collection)
+		} else {
+			var paths []string
+			err := r.DB.WithContext(ctx).Model(&models.Collection{}).Where("user_id = ?", userID).Where("id = ?", inp.Collection).Pluck("path", &paths).Error
+			if err != nil {
+				return nil, err
+			}
+			if len(paths) == 0 {
+				return []models.Bookmark{}, nil
+			}
+
+			subtree := r.DB.WithContext(ctx).Model(&models.Collection{}).
+				Select("id").
+				Where("user_id = ?", userID).
+				Where("path LIKE ?", paths[0]+"%")
+			query = query.Where("collection_id IN (?)", subtree)
+		}
+	}
+
+	err := query.
+		Order("created_at desc").Order("id desc").
+		Limit(inp.Limit).Offset(inp.Offset).
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+func (r *BookmarkRepositorySQL) SQLUpdateBookmark(ctx context.Context, bookmark *models.Bookmark) error {
+	tx := r.DB.WithContext(ctx).Begin()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	result := tx.Model(bookmark).Where("user_id = ?", bookmark.UserID).Select(append([]string{"url", "normalized_url", "url_hash", "canonical_hash", "title", "notes", "public"}, linkColumns...)).Updates(bookmark)
+
+	if err := result.Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	return tx.Commit().Error
+}
+
+// SQLUpdateStates writes the reading state of the given bookmarks, all of
+// them or none.
+func (r *BookmarkRepositorySQL) SQLUpdateStates(ctx context.Context, bookmarks []models.Bookmark) error {
+	tx := r.DB.WithContext(ctx).Begin()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	for i := range bookmarks {
+		bm := &bookmarks[i]
+		result := tx.Model(bm).Where("user_id = ?", bm.UserID).Select(stateColumns).Updates(bm)
+
+		if err := result.Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if result.RowsAffected != 1 {
+			tx.Rollback()
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// SQLQueue lists the unread bookmarks of a user in reading order.
+func (r *BookmarkRepositorySQL) SQLQueue(ctx context.Context, userID uint, inp models.QueueInput) ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+
+	query := r.DB.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID).Where("status = ?", models.StatusUnread)
+	if inp.MaxTime > 0 {
+		query = query.Where("reading_time > 0").Where("reading_time <= ?", inp.MaxTime)
+	}
+	for _, order := range queueOrders[inp.Order] {
+		query = query.Order(order)
+	}
+
+	err := query.Limit(inp.Limit).Offset(inp.Offset).Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+func (r *BookmarkRepositorySQL) SQLDeleteBookmark(ctx context.Context, userID, id uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	// What hangs off the bookmark goes first, as it points at the bookmark.
+	// Should the bookmark not be the user's, the rollback puts it back.
+	if err := deleteBookmarkData(tx, []uint{id}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	result := tx.Where("user_id = ?", userID).Where("id = ?", id).Delete(&models.Bookmark{})
+
+	if err := result.Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	return tx.Commit().Error
+}
+
+// deleteBookmarkData removes what hangs off the given bookmarks, before they
+// are deleted.
+func deleteBookmarkData(tx *gorm.DB, ids []uint) error {
+	if err := tx.Where("bookmark_id IN ?", ids).Delete(&models.BookmarkTag{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("bookmark_id IN ?", ids).Delete(&models.BookmarkContent{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("bookmark_id IN ?", ids).Delete(&models.Share{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("bookmark_id IN ?", ids).Delete(&models.Highlight{}).Error
+}
+
+func (r *BookmarkRepositorySQL) SQLGetBookmarksByIDs(ctx context.Context, userID uint, ids []uint) ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+	if len(ids) == 0 {
+		return bookmarks, nil
+	}
+
+	err := r.DB.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID).Where("id IN ?", ids).Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// SQLEachBookmark walks every bookmark of every user in id order, handing
+// them to fn batchSize at a time with their tags loaded.
+func (r *BookmarkRepositorySQL) SQLEachBookmark(ctx context.Context, batchSize int, fn func([]models.Bookmark) error) error {
+	var batch []models.Bookmark
+	return r.DB.WithContext(ctx).Preload("Tags").Order("id").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// SQLEachUserBookmark is SQLEachBookmark for the bookmarks of one user.
+func (r *BookmarkRepositorySQL) SQLEachUserBookmark(ctx context.Context, userID uint, batchSize int, fn func([]models.Bookmark) error) error {
+	var batch []models.Bookmark
+	return r.DB.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID).Order("id").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// SQLUpdateMetadata stores what was fetched for bookmark.URL, and the reader
+// copy when content is not nil. The title only fills an empty one, and
+// nothing is written if the URL changed meanwhile.
+func (r *BookmarkRepositorySQL) SQLUpdateMetadata(ctx context.Context, bookmark *models.Bookmark, content *models.BookmarkContent) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	result := tx.Model(&models.Bookmark{}).
+		Where("user_id = ?", bookmark.UserID).
+		Where("id = ?", bookmark.ID).
+		Where("url = ?", bookmark.URL).
+		Updates(map[string]interface{}{
+			"title":          gorm.Expr("CASE WHEN title = '' THEN ? ELSE title END", bookmark.Title),
+			"description":    bookmark.Description,
+			"canonical_url":  bookmark.CanonicalURL,
+			"canonical_hash": bookmark.CanonicalHash,
+			"image_url":      bookmark.ImageURL,
+			"favicon_url":    bookmark.FaviconURL,
+			"site_name":      bookmark.SiteName,
+			"fetched_at":     bookmark.FetchedAt,
+			"fetch_error":    bookmark.FetchError,
+			"word_count":     bookmark.WordCount,
+			"reading_time":   bookmark.ReadingTime,
+		})
+
+	if err := result.Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	if content != nil {
+		err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(content).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// SQLListLinksToCheck returns the bookmarks of every user whose link was
+// never checked or last checked before checkedBefore, or before retryBefore
+// when that check failed, those never checked first.
+func (r *BookmarkRepositorySQL) SQLListLinksToCheck(ctx context.Context, checkedBefore, retryBefore time.Time, limit int) ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+	err := r.DB.WithContext(ctx).Where("link_checked_at IS NULL OR link_checked_at < ? OR (link_failures > 0 AND link_checked_at < ?)", checkedBefore, retryBefore).
+		Order("link_checked_at IS NOT NULL").Order("link_checked_at").Order("id").
+		Limit(limit).
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// SQLUpdateLinkStatus stores the outcome of a link check, unless the URL
+// changed meanwhile.
+func (r *BookmarkRepositorySQL) SQLUpdateLinkStatus(ctx context.Context, bookmark *models.Bookmark) error {
+	result := r.DB.WithContext(ctx).Model(bookmark).
+		Where("user_id = ?", bookmark.UserID).
+		Where("url = ?", bookmark.URL).
+		Select(linkColumns).
+		UpdateColumns(bookmark)
+
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SQLGetBookmarkByURLHash finds the bookmark whose URL or canonical link
+// normalizes to hash.
+func (r *BookmarkRepositorySQL) SQLGetBookmarkByURLHash(ctx context.Context, userID uint, hash string) (*models.Bookmark, error) {
+	bookmark := new(models.Bookmark)
+	err := r.DB.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID).Where("url_hash = ? OR canonical_hash = ?", hash, hash).First(bookmark).Error
+	return bookmark, err
+}
+
+// SQLSetNormalizedURL stores the normalized URL of a bookmark, unless its
+// URL changed meanwhile.
+func (r *BookmarkRepositorySQL) SQLSetNormalizedURL(ctx context.Context, bookmark *models.Bookmark) error {
+	result := r.DB.WithContext(ctx).Model(bookmark).
+		Where("user_id = ?", bookmark.UserID).
+		Where("url = ?", bookmark.URL).
+		Select("normalized_url", "url_hash", "canonical_hash").
+		UpdateColumns(bookmark)
+
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SQLEachUnnormalizedBookmark is SQLEachBookmark for the bookmarks saved
+// before URLs were normalized.
+func (r *BookmarkRepositorySQL) SQLEachUnnormalizedBookmark(ctx context.Context, batchSize int, fn func([]models.Bookmark) error) error {
+	var batch []models.Bookmark
+	return r.DB.WithContext(ctx).Where("url_hash IS NULL").Order("id").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+func (r *BookmarkRepositorySQL) SQLListNormalizedURLs(ctx context.Context, userID uint) ([]string, error) {
+	var urls []string
+	err := r.DB.WithContext(ctx).Model(&models.Bookmark{}).Where("user_id = ?", userID).Where("normalized_url <> ?", "").Pluck("normalized_url", &urls).Error
+	return urls, err
+}
+
+func (r *BookmarkRepositorySQL) SQLGetContent(ctx context.Context, userID, bookmarkID uint) (*models.BookmarkContent, error) {
+	content := new(models.BookmarkContent)
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Where("bookmark_id = ?", bookmarkID).First(content).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// SQLGetContentTexts returns the article text of those of bookmarkIDs that
+// have content, by bookmark id. Callers check the owner of the bookmarks.
+func (r *BookmarkRepositorySQL) SQLGetContentTexts(ctx context.Context, bookmarkIDs []uint) (map[uint]string, error) {
+	texts := make(map[uint]string, len(bookmarkIDs))
+	if len(bookmarkIDs) == 0 {
+		return texts, nil
+	}
+
+	var contents []models.BookmarkContent
+	if err := r.DB.WithContext(ctx).Select("bookmark_id", "text").Where("bookmark_id IN ?", bookmarkIDs).Find(&contents).Error; err != nil {
+		return nil, err
+	}
+	for _, content := range contents {
+		texts[content.BookmarkID] = content.Text
+	}
+	return texts, nil
+}
@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB                      *gorm.DB
+	mock                    sqlmock.Sqlmock
+	bookmarkRepositorySQL   *BookmarkRepositorySQL
+	tagRepositorySQL        *TagRepositorySQL
+	importRepositorySQL     *ImportRepositorySQL
+	collectionRepositorySQL *CollectionRepositorySQL
+	shareRepositorySQL      *ShareRepositorySQL
+	highlightRepositorySQL  *HighlightRepositorySQL
+	feedRepositorySQL       *FeedRepositorySQL
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	assert.NoError(s.T(), err, "Failed to open gorm DB")
+	assert.NotNil(s.T(), db, "Mock DB is null")
+	assert.NotNil(s.T(), s.mock, "SQLMock is null")
+
+	s.DB, err = gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+
+	assert.NoError(s.T(), err)
+	s.bookmarkRepositorySQL = InitBookmarkRepositorySQL(s.DB)
+	s.tagRepositorySQL = InitTagRepositorySQL(s.DB)
+	s.importRepositorySQL = InitImportRepositorySQL(s.DB)
+	s.collectionRepositorySQL = InitCollectionRepositorySQL(s.DB)
+	s.shareRepositorySQL = InitShareRepositorySQL(s.DB)
+	s.highlightRepositorySQL = InitHighlightRepositorySQL(s.DB)
+	s.feedRepositorySQL = InitFeedRepositorySQL(s.DB)
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *Suite) TestSQLCreateBookmark_Success() {
+	hash := "3f0d"
+	bm := &models.Bookmark{
+		UserID:        1,
+		URL:           "https://example.com",
+		NormalizedURL: "example.com",
+		URLHash:       &hash,
+		Title:         "Example",
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `bookmarks` (`user_id`,`url`,`title`,`notes`,`created_at`,`updated_at`,`normalized_url`,`url_hash`,`canonical_hash`,`description`,`canonical_url`,`image_url`,`favicon_url`,`site_name`,`fetched_at`,`fetch_error`,`word_count`,`reading_time`,`status`,`favorite`,`priority`,`progress`,`read_at`,`archived_at`,`favorited_at`,`collection_id`,`public`,`link_status`,`link_code`,`redirect_url`,`link_error`,`link_failures`,`link_checked_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")).
+		WithArgs(bm.UserID, bm.URL, bm.Title, bm.Notes, sqlmock.AnyArg(), sqlmock.AnyArg(), "example.com", hash, nil, "", "", "", "", "", nil, "", 0, 0, models.StatusUnread, false, 0, 0, nil, nil, nil, nil, false, "", 0, "", "", 0, nil).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	s.mock.ExpectCommit()
+
+	err := s.bookmarkRepositorySQL.SQLCreateBookmark(context.Background(), bm)
+	s.NoError(err)
+	s.Equal(uint(7), bm.ID)
+}
+
+func (s *Suite) TestSQLCreateBookmark_Failed() {
+	bm := &models.Bookmark{UserID: 1, URL: "https://example.com"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `bookmarks`")).
+		WillReturnError(errors.New("some error"))
+	s.mock.ExpectRollback()
+
+	err := s.bookmarkRepositorySQL.SQLCreateBookmark(context.Background(), bm)
+	s.Error(err)
+}
+
+func (s *Suite) TestSQLGetBookmark_Success() {
+	now := time.Now()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND id = ?")).
+		WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url", "title", "notes", "created_at", "updated_at"}).
+			AddRow(7, 1, "https://example.com", "Example", "", now, now))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmark_tags` WHERE `bookmark_tags`.`bookmark_id` = ?")).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"bookmark_id", "tag_id"}).AddRow(7, 3))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tags` WHERE `tags`.`id` = ?")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "golang"))
+
+	res, err := s.bookmarkRepositorySQL.SQLGetBookmark(context.Background(), 1, 7)
+	require.NoError(s.T(), err)
+	s.Equal(uint(7), res.ID)
+	s.Equal("https://example.com", res.URL)
+	s.Equal([]models.Tag{{ID: 3, UserID: 1, Name: "golang"}}, res.Tags)
+}
+
+func (s *Suite) TestSQLGetBookmark_Failed_NotExist() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND id = ?")).
+		WithArgs(2, 7).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := s.bookmarkRepositorySQL.SQLGetBookmark(context.Background(), 2, 7)
+	s.Equal(gorm.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestSQLListBookmarks_Success() {
+	now := time.Now()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? ORDER BY created_at desc,id desc LIMIT 20 OFFSET 20")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url", "title", "notes", "created_at", "updated_at"}).
+			AddRow(8, 1, "https://example.com/b", "B", "", now, now).
+			AddRow(7, 1, "https://example.com/a", "A", "", now, now))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmark_tags` WHERE `bookmark_tags`.`bookmark_id` IN (?,?)")).
+		WithArgs(8, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"bookmark_id", "tag_id"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, models.ListInput{Limit: 20, Offset: 20})
+	require.NoError(s.T(), err)
+	s.Len(res, 2)
+}
+
+func (s *Suite) TestSQLListBookmarks_FilterAllTags() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND id IN (SELECT bookmark_tags.bookmark_id FROM `bookmark_tags` JOIN tags ON tags.id = bookmark_tags.tag_id WHERE tags.user_id = ? AND tags.name IN (?,?) GROUP BY `bookmark_tags`.`bookmark_id` HAVING COUNT(DISTINCT tags.id) = ?)")).
+		WithArgs(1, 1, "golang", "sql", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, models.ListInput{Limit: 20, TagNames: []string{"golang", "sql"}, TagMode: models.TagModeAll})
+	require.NoError(s.T(), err)
+	s.Empty(res)
+}
+
+func (s *Suite) TestSQLUpdateBookmark_Success() {
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com", Title: "Baru"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET `url`=?,`title`=?,`notes`=?,`updated_at`=?,`normalized_url`=?,`url_hash`=?,`canonical_hash`=?,`public`=?,`link_status`=?,`link_code`=?,`redirect_url`=?,`link_error`=?,`link_failures`=?,`link_checked_at`=? WHERE user_id = ? AND `id` = ?")).
+		WithArgs(bm.URL, bm.Title, bm.Notes, sqlmock.AnyArg(), "", nil, nil, false, "", 0, "", "", 0, nil, bm.UserID, bm.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.bookmarkRepositorySQL.SQLUpdateBookmark(context.Background(), bm))
+}
+
+func (s *Suite) TestSQLUpdateBookmark_Failed_ZeroRowAffected() {
+	bm := &models.Bookmark{ID: 7, UserID: 2, URL: "https://example.com"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	s.Equal(gorm.ErrRecordNotFound, s.bookmarkRepositorySQL.SQLUpdateBookmark(context.Background(), bm))
+}
+
+func (s *Suite) TestSQLListBookmarks_FilterState() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND status = ? AND favorite = ? ORDER BY created_at desc,id desc LIMIT 20")).
+		WithArgs(1, models.StatusArchived, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, models.ListInput{Limit: 20, Status: models.StatusArchived, Favorite: true})
+	require.NoError(s.T(), err)
+	s.Empty(res)
+}
+
+func (s *Suite) TestSQLListBookmarks_FilterLink() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND link_status = ? ORDER BY created_at desc,id desc LIMIT 20")).
+		WithArgs(1, models.LinkStatusBroken).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, models.ListInput{Limit: 20, Link: models.LinkStatusBroken})
+	require.NoError(s.T(), err)
+	s.Empty(res)
+}
+
+func (s *Suite) TestSQLGetBookmarkByURLHash_Success() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND (url_hash = ? OR canonical_hash = ?) ORDER BY `bookmarks`.`id` LIMIT 1")).
+		WithArgs(1, "3f0d", "3f0d").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}).AddRow(7, 1, "https://example.com"))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmark_tags` WHERE `bookmark_tags`.`bookmark_id` = ?")).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"bookmark_id", "tag_id"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLGetBookmarkByURLHash(context.Background(), 1, "3f0d")
+	require.NoError(s.T(), err)
+	s.Equal(uint(7), res.ID)
+}
+
+func (s *Suite) TestSQLSetNormalizedURL_Success() {
+	hash := "3f0d"
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: "https://Example.com/", NormalizedURL: "example.com", URLHash: &hash}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET `normalized_url`=?,`url_hash`=?,`canonical_hash`=? WHERE user_id = ? AND url = ? AND `id` = ?")).
+		WithArgs("example.com", hash, nil, 1, bm.URL, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.bookmarkRepositorySQL.SQLSetNormalizedURL(context.Background(), bm))
+}
+
+func (s *Suite) TestSQLListNormalizedURLs_Success() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `normalized_url` FROM `bookmarks` WHERE user_id = ? AND normalized_url <> ?")).
+		WithArgs(1, "").
+		WillReturnRows(sqlmock.NewRows([]string{"normalized_url"}).AddRow("example.com/a"))
+
+	res, err := s.bookmarkRepositorySQL.SQLListNormalizedURLs(context.Background(), 1)
+	require.NoError(s.T(), err)
+	s.Equal([]string{"example.com/a"}, res)
+}
+
+func (s *Suite) TestSQLListLinksToCheck_Success() {
+	before := time.Now().Add(-time.Hour)
+	retryBefore := time.Now().Add(-time.Minute)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE link_checked_at IS NULL OR link_checked_at < ? OR (link_failures > 0 AND link_checked_at < ?) ORDER BY link_checked_at IS NOT NULL,link_checked_at,id LIMIT 50")).
+		WithArgs(before, retryBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}).AddRow(7, 1, "https://example.com"))
+
+	res, err := s.bookmarkRepositorySQL.SQLListLinksToCheck(context.Background(), before, retryBefore, 50)
+	require.NoError(s.T(), err)
+	s.Len(res, 1)
+}
+
+func (s *Suite) TestSQLUpdateLinkStatus_Success() {
+	now := time.Now()
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com", LinkStatus: models.LinkStatusMoved, LinkCode: 200, RedirectURL: "https://example.org", LinkCheckedAt: &now}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET `link_status`=?,`link_code`=?,`redirect_url`=?,`link_error`=?,`link_failures`=?,`link_checked_at`=? WHERE user_id = ? AND url = ? AND `id` = ?")).
+		WithArgs(models.LinkStatusMoved, 200, "https://example.org", "", 0, now, 1, bm.URL, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.bookmarkRepositorySQL.SQLUpdateLinkStatus(context.Background(), bm))
+}
+
+func (s *Suite) TestSQLUpdateLinkStatus_Failed_URLChanged() {
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	s.Equal(gorm.ErrRecordNotFound, s.bookmarkRepositorySQL.SQLUpdateLinkStatus(context.Background(), bm))
+}
+
+func (s *Suite) TestSQLUpdateStates_Success() {
+	now := time.Now()
+	bookmarks := []models.Bookmark{
+		{ID: 7, UserID: 1, Status: models.StatusRead, Progress: 100, ReadAt: &now},
+		{ID: 8, UserID: 1, Status: models.StatusUnread, Favorite: true, Priority: 2, FavoritedAt: &now},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET `updated_at`=?,`status`=?,`favorite`=?,`priority`=?,`progress`=?,`read_at`=?,`archived_at`=?,`favorited_at`=? WHERE user_id = ? AND `id` = ?")).
+		WithArgs(sqlmock.AnyArg(), models.StatusRead, false, 0, 100, now, nil, nil, 1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET")).
+		WithArgs(sqlmock.AnyArg(), models.StatusUnread, true, 2, 0, nil, nil, now, 1, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.bookmarkRepositorySQL.SQLUpdateStates(context.Background(), bookmarks))
+}
+
+func (s *Suite) TestSQLUpdateStates_Failed_ZeroRowAffected() {
+	bookmarks := []models.Bookmark{{ID: 7, UserID: 1}, {ID: 8, UserID: 1}}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	s.Equal(gorm.ErrRecordNotFound, s.bookmarkRepositorySQL.SQLUpdateStates(context.Background(), bookmarks))
+}
+
+func (s *Suite) TestSQLQueue_Priority() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND status = ? ORDER BY priority desc,created_at,id LIMIT 20")).
+		WithArgs(1, models.StatusUnread).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLQueue(context.Background(), 1, models.QueueInput{Order: models.QueueOrderPriority, Limit: 20})
+	require.NoError(s.T(), err)
+	s.Empty(res)
+}
+
+func (s *Suite) TestSQLQueue_ShortestWithinTime() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmarks` WHERE user_id = ? AND status = ? AND reading_time > 0 AND reading_time <= ? ORDER BY reading_time = 0,reading_time,created_at,id LIMIT 10 OFFSET 10")).
+		WithArgs(1, models.StatusUnread, 15).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}))
+
+	res, err := s.bookmarkRepositorySQL.SQLQueue(context.Background(), 1, models.QueueInput{Order: models.QueueOrderShortest, MaxTime: 15, Limit: 10, Offset: 10})
+	require.NoError(s.T(), err)
+	s.Empty(res)
+}
+
+func (s *Suite) TestSQLUpdateMetadata_Success() {
+	now := time.Now()
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com", Title: "Example", SiteName: "example.com", FetchedAt: &now, WordCount: 3, ReadingTime: 1}
+	content := &models.BookmarkContent{BookmarkID: 7, UserID: 1, HTML: "<p>Hello there reader</p>", Text: "Hello there reader", WordCount: 3, ReadingTime: 1, ExtractedAt: now}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks` SET `canonical_hash`=?,`canonical_url`=?,`description`=?,`favicon_url`=?,`fetch_error`=?,`fetched_at`=?,`image_url`=?,`reading_time`=?,`site_name`=?,`title`=CASE WHEN title = '' THEN ? ELSE title END,`word_count`=?,`updated_at`=? WHERE user_id = ? AND id = ? AND url = ?")).
+		WithArgs(nil, "", "", "", "", now, "", 1, "example.com", "Example", 3, sqlmock.AnyArg(), bm.UserID, bm.ID, bm.URL).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `bookmark_contents` (`bookmark_id`,`user_id`,`html`,`text`,`word_count`,`reading_time`,`extracted_at`) VALUES (?,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE")).
+		WithArgs(7, 1, content.HTML, content.Text, 3, 1, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.bookmarkRepositorySQL.SQLUpdateMetadata(context.Background(), bm, content))
+}
+
+func (s *Suite) TestSQLUpdateMetadata_Success_WithoutContent() {
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com", FetchError: "gagal mengambil halaman"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.bookmarkRepositorySQL.SQLUpdateMetadata(context.Background(), bm, nil))
+}
+
+func (s *Suite) TestSQLUpdateMetadata_Failed_URLChanged() {
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com"}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `bookmarks`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	s.Equal(gorm.ErrRecordNotFound, s.bookmarkRepositorySQL.SQLUpdateMetadata(context.Background(), bm, &models.BookmarkContent{BookmarkID: 7}))
+}
+
+func (s *Suite) TestSQLGetContent_Success() {
+	rows := sqlmock.NewRows([]string{"bookmark_id", "user_id", "html", "text", "word_count", "reading_time"}).
+		AddRow(7, 1, "<p>Hello</p>", "Hello", 1, 1)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bookmark_contents` WHERE user_id = ? AND bookmark_id = ?")).
+		WithArgs(1, 7).
+		WillReturnRows(rows)
+
+	res, err := s.bookmarkRepositorySQL.SQLGetContent(context.Background(), 1, 7)
+	require.NoError(s.T(), err)
+	s.Equal("Hello", res.Text)
+}
+
+func (s *Suite) TestSQLGetContentTexts_Success() {
+	rows := sqlmock.NewRows([]string{"bookmark_id", "text"}).AddRow(7, "Hello")
+
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `bookmark_id`,`text` FROM `bookmark_contents` WHERE bookmark_id IN (?,?)")).
+		WithArgs(7, 8).
+		WillReturnRows(rows)
+
+	res, err := s.bookmarkRepositorySQL.SQLGetContentTexts(context.Background(), []uint{7, 8})
+	require.NoError(s.T(), err)
+	s.Equal(map[uint]string{7: "Hello"}, res)
+}
+
+func (s *Suite) TestSQLDeleteBookmark_Success() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmark_tags` WHERE bookmark_id IN (?)")).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmark_contents` WHERE bookmark_id IN (?)")).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `shares` WHERE bookmark_id IN (?)")).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `highlights` WHERE bookmark_id IN (?)")).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmarks` WHERE user_id = ? AND id = ?")).
+		WithArgs(1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.bookmarkRepositorySQL.SQLDeleteBookmark(context.Background(), 1, 7))
+}
+
+func (s *Suite) TestSQLDeleteBookmark_Failed_ZeroRowAffected() {
+	s.mock.ExpectBegin()
+	for _, table := range []string{"bookmark_tags", "bookmark_contents", "shares", "highlights"} {
+		s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `" + table + "` WHERE bookmark_id IN (?)")).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmarks` WHERE user_id = ? AND id = ?")).
+		WithArgs(2, 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	s.Equal(gorm.ErrRecordNotFound, s.bookmarkRepositorySQL.SQLDeleteBookmark(context.Background(), 2, 7))
+}
+
+func (s *Suite) TestSQLDeleteBookmark_Failed_atBegin() {
+	s.mock.ExpectBegin().WillReturnError(errors.New("some error"))
+
+	s.Error(s.bookmarkRepositorySQL.SQLDeleteBookmark(context.Background(), 1, 7))
+}
+
+func TestSuiteRepository(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
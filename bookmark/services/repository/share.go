@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+)
+
+type ShareRepositorySQL struct {
+	DB *gorm.DB
+}
+
+func InitShareRepositorySQL(db *gorm.DB) *ShareRepositorySQL {
+	return &ShareRepositorySQL{DB: db}
+}
+
+func (r *ShareRepositorySQL) SQLCreateShare(ctx context.Context, share *models.Share) error {
+	return r.DB.WithContext(ctx).Create(share).Error
+}
+
+func (r *ShareRepositorySQL) SQLGetShareByToken(ctx context.Context, token string) (*models.Share, error) {
+	share := new(models.Share)
+	err := r.DB.WithContext(ctx).Where("token = ?", token).First(share).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+func (r *ShareRepositorySQL) SQLListShares(ctx context.Context, userID uint) ([]models.Share, error) {
+	var shares []models.Share
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Order("id desc").Find(&shares).Error
+	return shares, err
+}
+
+// SQLRevokeShare marks a share revoked; revoking it again is not found.
+func (r *ShareRepositorySQL) SQLRevokeShare(ctx context.Context, userID, id uint, at time.Time) error {
+	result := r.DB.WithContext(ctx).Model(&models.Share{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", at)
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *ShareRepositorySQL) SQLRecordView(ctx context.Context, id uint, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Share{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"views":          gorm.Expr("views + 1"),
+		"last_viewed_at": at,
+	}).Error
+}
+
+// SQLRecordPasswordFailure counts a wrong password in the database, so
+// attempts racing each other are all counted. The one that makes attempts
+// locks the share until lockUntil and starts the count over. Both columns
+// are worked out from the old count, whichever order they are set in.
+func (r *ShareRepositorySQL) SQLRecordPasswordFailure(ctx context.Context, id uint, attempts int, lockUntil time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Share{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"locked_until":      gorm.Expr("CASE WHEN password_failures + 1 >= ? THEN ? ELSE locked_until END", attempts, lockUntil),
+		"password_failures": gorm.Expr("CASE WHEN password_failures + 1 >= ? THEN 0 ELSE password_failures + 1 END", attempts),
+	}).Error
+}
+
+func (r *ShareRepositorySQL) SQLResetPasswordFailures(ctx context.Context, id uint) error {
+	return r.DB.WithContext(ctx).Model(&models.Share{}).Where("id = ?", id).UpdateColumn("password_failures", 0).Error
+}
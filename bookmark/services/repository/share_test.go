@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func (s *Suite) TestSQLCreateShare_Success() {
+	bookmarkID := uint(7)
+	share := &models.Share{UserID: 1, Token: "abc", BookmarkID: &bookmarkID}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `shares` (`user_id`,`token`,`bookmark_id`,`collection_id`,`protected`,`password_salt`,`password_hash`,`password_failures`,`locked_until`,`expires_at`,`views`,`last_viewed_at`,`revoked_at`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)")).
+		WithArgs(1, "abc", 7, nil, false, "", "", 0, nil, nil, 0, nil, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.shareRepositorySQL.SQLCreateShare(context.Background(), share))
+	s.Equal(uint(3), share.ID)
+}
+
+func (s *Suite) TestSQLGetShareByToken_Success() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `shares` WHERE token = ? ORDER BY `shares`.`id` LIMIT 1")).
+		WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token", "collection_id"}).AddRow(3, 1, "abc", 5))
+
+	share, err := s.shareRepositorySQL.SQLGetShareByToken(context.Background(), "abc")
+	require.NoError(s.T(), err)
+	s.Equal(uint(5), *share.CollectionID)
+	s.Nil(share.BookmarkID)
+}
+
+func (s *Suite) TestSQLRevokeShare() {
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `shares` SET `revoked_at`=? WHERE user_id = ? AND id = ? AND revoked_at IS NULL")).
+		WithArgs(now, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.NoError(s.shareRepositorySQL.SQLRevokeShare(context.Background(), 1, 3, now))
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `shares` SET `revoked_at`=?")).
+		WithArgs(now, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+	s.Equal(gorm.ErrRecordNotFound, s.shareRepositorySQL.SQLRevokeShare(context.Background(), 1, 3, now))
+}
+
+func (s *Suite) TestSQLRecordView() {
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `shares` SET `last_viewed_at`=?,`views`=views + 1 WHERE id = ?")).
+		WithArgs(now, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.shareRepositorySQL.SQLRecordView(context.Background(), 3, now))
+}
+
+func (s *Suite) TestSQLRecordPasswordFailure() {
+	until := time.Now().Add(15 * time.Minute)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `shares` SET `locked_until`=CASE WHEN password_failures + 1 >= ? THEN ? ELSE locked_until END,`password_failures`=CASE WHEN password_failures + 1 >= ? THEN 0 ELSE password_failures + 1 END WHERE id = ?")).
+		WithArgs(5, until, 5, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.shareRepositorySQL.SQLRecordPasswordFailure(context.Background(), 3, 5, until))
+}
+
+func (s *Suite) TestSQLResetPasswordFailures() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `shares` SET `password_failures`=? WHERE id = ?")).
+		WithArgs(0, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.NoError(s.shareRepositorySQL.SQLResetPasswordFailures(context.Background(), 3))
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// SQLiteSuite runs the repositories against a real database: an in-memory
+// SQLite one, created afresh for every test.
+type SQLiteSuite struct {
+	suite.Suite
+	DB                      *gorm.DB
+	bookmarkRepositorySQL   *BookmarkRepositorySQL
+	tagRepositorySQL        *TagRepositorySQL
+	importRepositorySQL     *ImportRepositorySQL
+	collectionRepositorySQL *CollectionRepositorySQL
+	shareRepositorySQL      *ShareRepositorySQL
+	highlightRepositorySQL  *HighlightRepositorySQL
+	feedRepositorySQL       *FeedRepositorySQL
+}
+
+func (s *SQLiteSuite) SetupTest() {
+	db, err := database.SetupDatabase(context.Background(), config.DatabaseConfig{Driver: config.DriverSQLite, Name: ":memory:"}, nil, logging.Discard())
+	s.Require().NoError(err)
+	s.DB = db
+	s.bookmarkRepositorySQL = InitBookmarkRepositorySQL(s.DB)
+	s.tagRepositorySQL = InitTagRepositorySQL(s.DB)
+	s.importRepositorySQL = InitImportRepositorySQL(s.DB)
+	s.collectionRepositorySQL = InitCollectionRepositorySQL(s.DB)
+	s.shareRepositorySQL = InitShareRepositorySQL(s.DB)
+	s.highlightRepositorySQL = InitHighlightRepositorySQL(s.DB)
+	s.feedRepositorySQL = InitFeedRepositorySQL(s.DB)
+}
+
+func (s *SQLiteSuite) TearDownTest() {
+	sqlDB, err := s.DB.DB()
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), sqlDB.Close())
+}
+
+func TestSQLite(t *testing.T) {
+	suite.Run(t, new(SQLiteSuite))
+}
+
+func (s *SQLiteSuite) createBookmark(userID uint, url string) *models.Bookmark {
+	hash := url
+	bm := &models.Bookmark{UserID: userID, URL: url, Title: url, NormalizedURL: url, URLHash: &hash}
+	require.NoError(s.T(), s.bookmarkRepositorySQL.SQLCreateBookmark(context.Background(), bm))
+	return bm
+}
+
+func bookmarkIDs(bookmarks []models.Bookmark) []uint {
+	ids := make([]uint, 0, len(bookmarks))
+	for _, bm := range bookmarks {
+		ids = append(ids, bm.ID)
+	}
+	return ids
+}
+
+func (s *SQLiteSuite) Test_SQLite_Bookmark_CRUD() {
+	bm := s.createBookmark(1, "https://example.com/a")
+
+	got, err := s.bookmarkRepositorySQL.SQLGetBookmark(context.Background(), 1, bm.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "https://example.com/a", got.URL)
+	assert.Equal(s.T(), models.StatusUnread, got.Status)
+
+	_, err = s.bookmarkRepositorySQL.SQLGetBookmark(context.Background(), 2, bm.ID)
+	assert.True(s.T(), errors.Is(err, dberr.ErrNotFound))
+
+	got.Title = "Example"
+	got.Public = true
+	require.NoError(s.T(), s.bookmarkRepositorySQL.SQLUpdateBookmark(context.Background(), got))
+
+	got, err = s.bookmarkRepositorySQL.SQLGetBookmarkByURLHash(context.Background(), 1, "https://example.com/a")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "Example", got.Title)
+	assert.True(s.T(), got.Public)
+
+	_, err = s.tagRepositorySQL.SQLAddTags(context.Background(), 1, bm.ID, []string{"go"})
+	require.NoError(s.T(), err)
+	assert.True(s.T(), errors.Is(s.bookmarkRepositorySQL.SQLDeleteBookmark(context.Background(), 2, bm.ID), gorm.ErrRecordNotFound))
+	got, err = s.bookmarkRepositorySQL.SQLGetBookmark(context.Background(), 1, bm.ID)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), got.Tags, 1)
+
+	require.NoError(s.T(), s.bookmarkRepositorySQL.SQLDeleteBookmark(context.Background(), 1, bm.ID))
+	assert.True(s.T(), errors.Is(s.bookmarkRepositorySQL.SQLDeleteBookmark(context.Background(), 1, bm.ID), gorm.ErrRecordNotFound))
+}
+
+func (s *SQLiteSuite) Test_SQLite_Bookmark_DuplicateURLHash() {
+	s.createBookmark(1, "https://example.com/a")
+	s.createBookmark(2, "https://example.com/a")
+
+	hash := "https://example.com/a"
+	err := s.bookmarkRepositorySQL.SQLCreateBookmark(context.Background(), &models.Bookmark{UserID: 1, URL: hash, URLHash: &hash})
+	assert.True(s.T(), errors.Is(err, dberr.ErrDuplicateKey), err)
+}
+
+func (s *SQLiteSuite) Test_SQLite_ListBookmarks_Filters() {
+	a := s.createBookmark(1, "https://example.com/a")
+	b := s.createBookmark(1, "https://example.com/b")
+	c := s.createBookmark(1, "https://example.com/c")
+	s.createBookmark(2, "https://example.com/d")
+
+	_, err := s.tagRepositorySQL.SQLAddTags(context.Background(), 1, a.ID, []string{"go", "sql"})
+	require.NoError(s.T(), err)
+	_, err = s.tagRepositorySQL.SQLAddTags(context.Background(), 1, b.ID, []string{"go"})
+	require.NoError(s.T(), err)
+
+	c.Status = models.StatusArchived
+	c.Favorite = true
+	require.NoError(s.T(), s.bookmarkRepositorySQL.SQLUpdateStates(context.Background(), []models.Bookmark{*c}))
+
+	list := func(inp models.ListInput) []uint {
+		inp.Limit = 20
+		bookmarks, err := s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, inp)
+		require.NoError(s.T(), err)
+		return bookmarkIDs(bookmarks)
+	}
+
+	assert.Equal(s.T(), []uint{c.ID, b.ID, a.ID}, list(models.ListInput{}))
+	assert.Equal(s.T(), []uint{b.ID, a.ID}, list(models.ListInput{TagNames: []string{"go", "sql"}, TagMode: models.TagModeAny}))
+	assert.Equal(s.T(), []uint{a.ID}, list(models.ListInput{TagNames: []string{"go", "sql"}, TagMode: models.TagModeAll}))
+	assert.Equal(s.T(), []uint{c.ID}, list(models.ListInput{Status: models.StatusArchived}))
+	assert.Equal(s.T(), []uint{c.ID}, list(models.ListInput{Favorite: true}))
+}
+
+func (s *SQLiteSuite) Test_SQLite_Tags() {
+	a := s.createBookmark(1, "https://example.com/a")
+	b := s.createBookmark(1, "https://example.com/b")
+
+	_, err := s.tagRepositorySQL.SQLAddTags(context.Background(), 1, a.ID, []string{"go", "golang"})
+	require.NoError(s.T(), err)
+	_, err = s.tagRepositorySQL.SQLAddTags(context.Background(), 1, b.ID, []string{"golang"})
+	require.NoError(s.T(), err)
+	// Adding a tag twice changes nothing.
+	_, err = s.tagRepositorySQL.SQLAddTags(context.Background(), 1, b.ID, []string{"golang"})
+	require.NoError(s.T(), err)
+
+	golang, err := s.tagRepositorySQL.SQLGetTagByName(context.Background(), 1, "golang")
+	require.NoError(s.T(), err)
+
+	err = s.tagRepositorySQL.SQLRenameTag(context.Background(), 1, golang.ID, "go")
+	assert.True(s.T(), errors.Is(err, dberr.ErrDuplicateKey), err)
+
+	merged, err := s.tagRepositorySQL.SQLMergeTags(context.Background(), 1, []string{"golang"}, "go")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "go", merged.Name)
+
+	counts, err := s.tagRepositorySQL.SQLListTags(context.Background(), 1)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), counts, 1)
+	assert.Equal(s.T(), "go", counts[0].Name)
+	assert.Equal(s.T(), int64(2), counts[0].Count)
+
+	require.NoError(s.T(), s.tagRepositorySQL.SQLDeleteTag(context.Background(), 1, merged.ID))
+	got, err := s.bookmarkRepositorySQL.SQLGetBookmark(context.Background(), 1, a.ID)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), got.Tags)
+}
+
+func (s *SQLiteSuite) Test_SQLite_Collections() {
+	root := &models.Collection{UserID: 1, Name: "root"}
+	require.NoError(s.T(), s.collectionRepositorySQL.SQLCreateCollection(context.Background(), root, nil))
+	child := &models.Collection{UserID: 1, ParentID: &root.ID, Name: "child"}
+	require.NoError(s.T(), s.collectionRepositorySQL.SQLCreateCollection(context.Background(), child, root))
+	other := &models.Collection{UserID: 1, Name: "other"}
+	require.NoError(s.T(), s.collectionRepositorySQL.SQLCreateCollection(context.Background(), other, nil))
+
+	bm := s.createBookmark(1, "https://example.com/a")
+	require.NoError(s.T(), s.collectionRepositorySQL.SQLSetBookmarkCollection(context.Background(), 1, bm.ID, &child.ID))
+	_, err := s.tagRepositorySQL.SQLAddTags(context.Background(), 1, bm.ID, []string{"go"})
+	require.NoError(s.T(), err)
+
+	bookmarks, err := s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, models.ListInput{Collection: root.ID, Recursive: true, Limit: 20})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []uint{bm.ID}, bookmarkIDs(bookmarks))
+
+	require.NoError(s.T(), s.collectionRepositorySQL.SQLMoveCollection(context.Background(), child, other, 0))
+	moved, err := s.collectionRepositorySQL.SQLGetCollection(context.Background(), 1, child.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), other.Path+"2/", moved.Path)
+
+	bookmarks, err = s.bookmarkRepositorySQL.SQLListBookmarks(context.Background(), 1, models.ListInput{Collection: root.ID, Recursive: true, Limit: 20})
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), bookmarks)
+
+	_, err = s.collectionRepositorySQL.SQLDeleteCollection(context.Background(), other, models.CollectionDeleteDelete)
+	require.NoError(s.T(), err)
+	_, err = s.bookmarkRepositorySQL.SQLGetBookmark(context.Background(), 1, bm.ID)
+	assert.True(s.T(), errors.Is(err, dberr.ErrNotFound))
+}
+
+func (s *SQLiteSuite) Test_SQLite_Shares_And_Feeds() {
+	bm := s.createBookmark(1, "https://example.com/a")
+
+	share := &models.Share{UserID: 1, Token: "token", BookmarkID: &bm.ID}
+	require.NoError(s.T(), s.shareRepositorySQL.SQLCreateShare(context.Background(), share))
+	err := s.shareRepositorySQL.SQLCreateShare(context.Background(), &models.Share{UserID: 1, Token: "token", BookmarkID: &bm.ID})
+	assert.True(s.T(), errors.Is(err, dberr.ErrDuplicateKey), err)
+
+	now := time.Now()
+	require.NoError(s.T(), s.shareRepositorySQL.SQLRecordView(context.Background(), share.ID, now))
+	require.NoError(s.T(), s.shareRepositorySQL.SQLRecordView(context.Background(), share.ID, now))
+	got, err := s.shareRepositorySQL.SQLGetShareByToken(context.Background(), "token")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), got.Views)
+
+	// The third wrong password in a row locks the share and starts over.
+	until := now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		require.NoError(s.T(), s.shareRepositorySQL.SQLRecordPasswordFailure(context.Background(), share.ID, 3, until))
+	}
+	got, err = s.shareRepositorySQL.SQLGetShareByToken(context.Background(), "token")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, got.PasswordFailures)
+	assert.Nil(s.T(), got.LockedUntil)
+	require.NoError(s.T(), s.shareRepositorySQL.SQLRecordPasswordFailure(context.Background(), share.ID, 3, until))
+	got, err = s.shareRepositorySQL.SQLGetShareByToken(context.Background(), "token")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, got.PasswordFailures)
+	require.NotNil(s.T(), got.LockedUntil)
+	assert.WithinDuration(s.T(), until, *got.LockedUntil, time.Second)
+	require.NoError(s.T(), s.shareRepositorySQL.SQLRecordPasswordFailure(context.Background(), share.ID, 3, until))
+	require.NoError(s.T(), s.shareRepositorySQL.SQLResetPasswordFailures(context.Background(), share.ID))
+	got, err = s.shareRepositorySQL.SQLGetShareByToken(context.Background(), "token")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, got.PasswordFailures)
+
+	feed := &models.Feed{UserID: 1, Token: "feed"}
+	require.NoError(s.T(), s.feedRepositorySQL.SQLCreateFeed(context.Background(), feed))
+	require.NoError(s.T(), s.feedRepositorySQL.SQLRevokeFeed(context.Background(), 1, feed.ID, now))
+	assert.True(s.T(), errors.Is(s.feedRepositorySQL.SQLRevokeFeed(context.Background(), 1, feed.ID, now), gorm.ErrRecordNotFound))
+
+	// Deleting the bookmark takes its shares along.
+	require.NoError(s.T(), s.bookmarkRepositorySQL.SQLDeleteBookmark(context.Background(), 1, bm.ID))
+	_, err = s.shareRepositorySQL.SQLGetShareByToken(context.Background(), "token")
+	assert.True(s.T(), errors.Is(err, dberr.ErrNotFound))
+}
+
+func (s *SQLiteSuite) Test_SQLite_LinksToCheck() {
+	checked := s.createBookmark(1, "https://example.com/a")
+	unchecked := s.createBookmark(1, "https://example.com/b")
+
+	at := time.Now().Add(-48 * time.Hour)
+	checked.LinkStatus = models.LinkStatusOK
+	checked.LinkCode = 200
+	checked.LinkCheckedAt = &at
+	require.NoError(s.T(), s.bookmarkRepositorySQL.SQLUpdateLinkStatus(context.Background(), checked))
+
+	bookmarks, err := s.bookmarkRepositorySQL.SQLListLinksToCheck(context.Background(), time.Now().Add(-24*time.Hour), time.Now().Add(-24*time.Hour), 10)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []uint{unchecked.ID, checked.ID}, bookmarkIDs(bookmarks))
+
+	bookmarks, err = s.bookmarkRepositorySQL.SQLListLinksToCheck(context.Background(), time.Now().Add(-72*time.Hour), time.Now().Add(-24*time.Hour), 10)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []uint{unchecked.ID}, bookmarkIDs(bookmarks))
+
+	// A failed check is retried sooner.
+	checked.LinkFailures = 1
+	require.NoError(s.T(), s.bookmarkRepositorySQL.SQLUpdateLinkStatus(context.Background(), checked))
+	bookmarks, err = s.bookmarkRepositorySQL.SQLListLinksToCheck(context.Background(), time.Now().Add(-72*time.Hour), time.Now().Add(-24*time.Hour), 10)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []uint{unchecked.ID, checked.ID}, bookmarkIDs(bookmarks))
+}
+
+func (s *SQLiteSuite) Test_SQLite_Highlights_And_Imports() {
+	bm := s.createBookmark(1, "https://example.com/a")
+
+	highlight := &models.Highlight{UserID: 1, BookmarkID: bm.ID, Quote: "quote", StartOffset: 4, EndOffset: 9}
+	require.NoError(s.T(), s.highlightRepositorySQL.SQLCreateHighlight(context.Background(), highlight))
+	highlights, err := s.highlightRepositorySQL.SQLListBookmarkHighlights(context.Background(), 1, bm.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), highlights, 1)
+	assert.Equal(s.T(), "quote", highlights[0].Quote)
+
+	job := &models.ImportJob{UserID: 1, Status: models.ImportStatusRunning}
+	require.NoError(s.T(), s.importRepositorySQL.SQLCreateImportJob(context.Background(), job))
+	require.NoError(s.T(), s.importRepositorySQL.SQLFailUnfinishedImports(context.Background(), "interrupted"))
+	got, err := s.importRepositorySQL.SQLGetImportJob(context.Background(), 1, job.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), models.ImportStatusFailed, got.Status)
+	assert.Equal(s.T(), "interrupted", got.Error)
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepositorySQL struct {
+	DB *gorm.DB
+}
+
+func InitTagRepositorySQL(db *gorm.DB) *TagRepositorySQL {
+	return &TagRepositorySQL{DB: db}
+}
+
+func (r *TagRepositorySQL) SQLAddTags(ctx context.Context, userID, bookmarkID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var owned int64
+		if err := tx.Model(&models.Bookmark{}).Where("user_id = ?", userID).Where("id = ?", bookmarkID).Count(&owned).Error; err != nil {
+			return err
+		}
+		if owned == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var err error
+		tags, err = findOrCreateTags(tx, userID, names)
+		if err != nil {
+			return err
+		}
+
+		links := make([]models.BookmarkTag, 0, len(tags))
+		for _, tag := range tags {
+			links = append(links, models.BookmarkTag{BookmarkID: bookmarkID, TagID: tag.ID})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+
+	return tags, err
+}
+
+func (r *TagRepositorySQL) SQLRemoveTag(ctx context.Context, userID, bookmarkID uint, name string) error {
+	tag, err := r.SQLGetTagByName(ctx, userID, name)
+	if err != nil {
+		return err
+	}
+
+	result := r.DB.WithContext(ctx).Where("bookmark_id = ?", bookmarkID).Where("tag_id = ?", tag.ID).Delete(&models.BookmarkTag{})
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *TagRepositorySQL) SQLListTags(ctx context.Context, userID uint) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	err := r.DB.WithContext(ctx).Model(&models.Tag{}).
+		Select("tags.id, tags.name, COUNT(bookmark_tags.bookmark_id) AS count").
+		Joins("LEFT JOIN bookmark_tags ON bookmark_tags.tag_id = tags.id").
+		Where("tags.user_id = ?", userID).
+		Group("tags.id, tags.name").
+		Order("tags.name").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *TagRepositorySQL) SQLGetTag(ctx context.Context, userID, id uint) (*models.Tag, error) {
+	tag := new(models.Tag)
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", id).First(tag).Error
+	return tag, err
+}
+
+func (r *TagRepositorySQL) SQLGetTagByName(ctx context.Context, userID uint, name string) (*models.Tag, error) {
+	tag := new(models.Tag)
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Where("name = ?", name).First(tag).Error
+	return tag, err
+}
+
+func (r *TagRepositorySQL) SQLRenameTag(ctx context.Context, userID, id uint, name string) error {
+	result := r.DB.WithContext(ctx).Model(&models.Tag{}).Where("user_id = ?", userID).Where("id = ?", id).Update("name", name)
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SQLMergeTags moves every bookmark tagged with one of sources onto target,
+// creating target if needed, and deletes the source tags in one transaction.
+func (r *TagRepositorySQL) SQLMergeTags(ctx context.Context, userID uint, sources []string, target string) (*models.Tag, error) {
+	var merged models.Tag
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sourceIDs []uint
+		if err := tx.Model(&models.Tag{}).Where("user_id = ?", userID).Where("name IN ?", sources).Where("name <> ?", target).Pluck("id", &sourceIDs).Error; err != nil {
+			return err
+		}
+		if len(sourceIDs) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		tags, err := findOrCreateTags(tx, userID, []string{target})
+		if err != nil {
+			return err
+		}
+		merged = tags[0]
+
+		var bookmarkIDs []uint
+		if err := tx.Model(&models.BookmarkTag{}).Distinct("bookmark_id").Where("tag_id IN ?", sourceIDs).Pluck("bookmark_id", &bookmarkIDs).Error; err != nil {
+			return err
+		}
+
+		if len(bookmarkIDs) > 0 {
+			links := make([]models.BookmarkTag, 0, len(bookmarkIDs))
+			for _, id := range bookmarkIDs {
+				links = append(links, models.BookmarkTag{BookmarkID: id, TagID: merged.ID})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("tag_id IN ?", sourceIDs).Delete(&models.BookmarkTag{}).Error; err != nil {
+			return err
+		}
+		// Feeds of the merged tags follow the bookmarks.
+		if err := tx.Model(&models.Feed{}).Where("tag_id IN ?", sourceIDs).Update("tag_id", merged.ID).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", sourceIDs).Delete(&models.Tag{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &merged, nil
+}
+
+func (r *TagRepositorySQL) SQLDeleteTag(ctx context.Context, userID, id uint) error {
+	// The links go first, as they point at the tag. Should the tag not be
+	// the user's, the transaction puts them back.
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&models.BookmarkTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.Feed{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ?", userID).Where("id = ?", id).Delete(&models.Tag{})
+		if err := result.Error; err != nil {
+			return err
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *TagRepositorySQL) SQLListBookmarkIDsByTag(ctx context.Context, userID, tagID uint) ([]uint, error) {
+	var ids []uint
+	err := r.DB.WithContext(ctx).Model(&models.BookmarkTag{}).
+		Joins("JOIN tags ON tags.id = bookmark_tags.tag_id").
+		Where("tags.user_id = ?", userID).
+		Where("bookmark_tags.tag_id = ?", tagID).
+		Pluck("bookmark_tags.bookmark_id", &ids).Error
+	return ids, err
+}
+
+func findOrCreateTags(tx *gorm.DB, userID uint, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tag := models.Tag{UserID: userID, Name: name}
+		if err := tx.Where("user_id = ?", userID).Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
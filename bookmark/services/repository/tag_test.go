@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func (s *Suite) TestSQLAddTags_Success() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `bookmarks` WHERE user_id = ? AND id = ?")).
+		WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tags` WHERE user_id = ? AND name = ?")).
+		WithArgs(1, "golang").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "golang"))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tags` WHERE user_id = ? AND name = ?")).
+		WithArgs(1, "sql").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `tags` (`user_id`,`name`) VALUES (?,?)")).
+		WithArgs(1, "sql").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `bookmark_tags` (`bookmark_id`,`tag_id`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE")).
+		WithArgs(7, 3, 7, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	tags, err := s.tagRepositorySQL.SQLAddTags(context.Background(), 1, 7, []string{"golang", "sql"})
+	require.NoError(s.T(), err)
+	s.Equal([]models.Tag{{ID: 3, UserID: 1, Name: "golang"}, {ID: 4, UserID: 1, Name: "sql"}}, tags)
+}
+
+func (s *Suite) TestSQLAddTags_Failed_NotOwner() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `bookmarks` WHERE user_id = ? AND id = ?")).
+		WithArgs(2, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectRollback()
+
+	_, err := s.tagRepositorySQL.SQLAddTags(context.Background(), 2, 7, []string{"golang"})
+	s.Equal(gorm.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestSQLListTags_Success() {
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT tags.id, tags.name, COUNT(bookmark_tags.bookmark_id) AS count FROM `tags` LEFT JOIN bookmark_tags ON bookmark_tags.tag_id = tags.id WHERE tags.user_id = ? GROUP BY tags.id, tags.name ORDER BY tags.name")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).AddRow(3, "golang", 2).AddRow(4, "sql", 0))
+
+	res, err := s.tagRepositorySQL.SQLListTags(context.Background(), 1)
+	require.NoError(s.T(), err)
+	s.Equal([]models.TagCount{{ID: 3, Name: "golang", Count: 2}, {ID: 4, Name: "sql", Count: 0}}, res)
+}
+
+func (s *Suite) TestSQLMergeTags_Success() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `tags` WHERE user_id = ? AND name IN (?,?) AND name <> ?")).
+		WithArgs(1, "go", "golang", "golang").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tags` WHERE user_id = ? AND name = ?")).
+		WithArgs(1, "golang").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "golang"))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `bookmark_id` FROM `bookmark_tags` WHERE tag_id IN (?)")).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"bookmark_id"}).AddRow(7).AddRow(8))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `bookmark_tags` (`bookmark_id`,`tag_id`) VALUES (?,?),(?,?)")).
+		WithArgs(7, 3, 8, 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bookmark_tags` WHERE tag_id IN (?)")).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `feeds` SET `tag_id`=? WHERE tag_id IN (?)")).
+		WithArgs(3, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `tags` WHERE id IN (?)")).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	tag, err := s.tagRepositorySQL.SQLMergeTags(context.Background(), 1, []string{"go", "golang"}, "golang")
+	require.NoError(s.T(), err)
+	s.Equal(&models.Tag{ID: 3, UserID: 1, Name: "golang"}, tag)
+}
+
+func (s *Suite) TestSQLMergeTags_Failed_NoSources() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `tags` WHERE user_id = ? AND name IN (?) AND name <> ?")).
+		WithArgs(1, "go", "golang").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	s.mock.ExpectRollback()
+
+	_, err := s.tagRepositorySQL.SQLMergeTags(context.Background(), 1, []string{"go"}, "golang")
+	s.Equal(gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,29 @@
+package search
+
+import (
+	"context"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// documentTable keeps the documents of the database indexes in the
+// search_documents table; each index adds its own way of searching it.
+type documentTable struct {
+	DB *gorm.DB
+}
+
+func (d *documentTable) Index(ctx context.Context, doc models.SearchDocument) error {
+	return d.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&doc).Error
+}
+
+func (d *documentTable) Remove(ctx context.Context, userID, bookmarkID uint) error {
+	return d.DB.WithContext(ctx).Where("user_id = ?", userID).Where("bookmark_id = ?", bookmarkID).Delete(&models.SearchDocument{}).Error
+}
+
+func (d *documentTable) NeedsRebuild(ctx context.Context) (bool, error) {
+	var count int64
+	err := d.DB.WithContext(ctx).Model(&models.SearchDocument{}).Count(&count).Error
+	return count == 0, err
+}
@@ -0,0 +1,187 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+// BM25 tuning, see https://en.wikipedia.org/wiki/Okapi_BM25
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Matches in the title count more than matches in the notes, and those more
+// than matches somewhere in the article.
+var fieldWeights = struct {
+	title, tags, url, notes, content float64
+}{title: 3, tags: 2, url: 1.5, notes: 1, content: 0.5}
+
+type memoryDoc struct {
+	doc    models.SearchDocument
+	terms  map[string]float64
+	length float64
+}
+
+type userIndex struct {
+	docs     map[uint]*memoryDoc
+	postings map[string]map[uint]float64
+	totalLen float64
+}
+
+// MemoryIndex is an in-process inverted index ranked with BM25. It is used
+// with SQLite, which only ever has one server, and in tests, and has to be
+// rebuilt from the database on every start.
+type MemoryIndex struct {
+	mu    sync.RWMutex
+	users map[uint]*userIndex
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{users: make(map[uint]*userIndex)}
+}
+
+func (m *MemoryIndex) Index(ctx context.Context, doc models.SearchDocument) error {
+	entry := &memoryDoc{doc: doc, terms: make(map[string]float64)}
+	addTerms(entry, doc.Title, fieldWeights.title)
+	addTerms(entry, doc.Tags, fieldWeights.tags)
+	addTerms(entry, doc.URL, fieldWeights.url)
+	addTerms(entry, doc.Notes, fieldWeights.notes)
+	addTerms(entry, doc.Content, fieldWeights.content)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := m.users[doc.UserID]
+	if idx == nil {
+		idx = &userIndex{docs: make(map[uint]*memoryDoc), postings: make(map[string]map[uint]float64)}
+		m.users[doc.UserID] = idx
+	}
+
+	idx.remove(doc.BookmarkID)
+	idx.docs[doc.BookmarkID] = entry
+	idx.totalLen += entry.length
+	for term, tf := range entry.terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[uint]float64)
+		}
+		idx.postings[term][doc.BookmarkID] = tf
+	}
+
+	return nil
+}
+
+func (m *MemoryIndex) Remove(ctx context.Context, userID, bookmarkID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if idx := m.users[userID]; idx != nil {
+		idx.remove(bookmarkID)
+	}
+	return nil
+}
+
+// Search returns the documents containing every query term, best match first.
+func (m *MemoryIndex) Search(ctx context.Context, userID uint, query string, limit, offset int) ([]models.SearchHit, int64, error) {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	idx := m.users[userID]
+	if idx == nil || len(idx.docs) == 0 {
+		return nil, 0, nil
+	}
+
+	// Walk the rarest term first so the candidate set starts small.
+	ordered := append([]string(nil), terms...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return len(idx.postings[ordered[i]]) < len(idx.postings[ordered[j]])
+	})
+
+	n := float64(len(idx.docs))
+	avgLen := idx.totalLen / n
+	scores := make(map[uint]float64)
+	for i, term := range ordered {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			return nil, 0, nil
+		}
+		idf := math.Log(1 + (n-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+
+		next := make(map[uint]float64)
+		for id, tf := range postings {
+			prev, ok := scores[id]
+			if i > 0 && !ok {
+				continue
+			}
+			norm := tf + bm25K1*(1-bm25B+bm25B*idx.docs[id].length/avgLen)
+			next[id] = prev + idf*tf*(bm25K1+1)/norm
+		}
+		scores = next
+	}
+
+	hits := make([]models.SearchHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, models.SearchHit{BookmarkID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].BookmarkID > hits[j].BookmarkID
+	})
+
+	total := int64(len(hits))
+	if offset >= len(hits) {
+		return []models.SearchHit{}, total, nil
+	}
+	hits = hits[offset:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	for i := range hits {
+		hits[i].Snippet = Snippet(idx.docs[hits[i].BookmarkID].doc, terms)
+	}
+
+	return hits, total, nil
+}
+
+// NeedsRebuild is always true for an empty index since nothing survives a
+// restart.
+func (m *MemoryIndex) NeedsRebuild(ctx context.Context) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.users) == 0, nil
+}
+
+func (idx *userIndex) remove(bookmarkID uint) {
+	old := idx.docs[bookmarkID]
+	if old == nil {
+		return
+	}
+
+	for term := range old.terms {
+		delete(idx.postings[term], bookmarkID)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLen -= old.length
+	delete(idx.docs, bookmarkID)
+}
+
+func addTerms(entry *memoryDoc, text string, weight float64) {
+	for _, t := range tokenize(text) {
+		entry.terms[t.term] += weight
+		entry.length += weight
+	}
+}
@@ -0,0 +1,125 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndex(t *testing.T) *MemoryIndex {
+	index := NewMemoryIndex()
+	docs := []models.SearchDocument{
+		{BookmarkID: 1, UserID: 1, Title: "The Clean Architecture", URL: "https://blog.cleancoder.com/clean-architecture", Notes: "dependency rule points inwards"},
+		{BookmarkID: 2, UserID: 1, Title: "Go concurrency patterns", URL: "https://go.dev/talks/concurrency", Tags: "golang", Content: "Channels orchestrate; mutexes serialize."},
+		{BookmarkID: 3, UserID: 1, Title: "Notes on architecture", URL: "https://example.com/arch", Notes: "clean code is not clean architecture"},
+		{BookmarkID: 4, UserID: 2, Title: "Clean Architecture in Go", URL: "https://example.com/go-clean"},
+	}
+	for _, doc := range docs {
+		require.NoError(t, index.Index(context.Background(), doc))
+	}
+	return index
+}
+
+func TestMemoryIndex_RanksTitleMatchesFirst(t *testing.T) {
+	index := newTestIndex(t)
+
+	hits, total, err := index.Search(context.Background(), 1, "clean architecture", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, hits, 2)
+	assert.Equal(t, uint(1), hits[0].BookmarkID)
+	assert.Equal(t, uint(3), hits[1].BookmarkID)
+	assert.True(t, hits[0].Score > hits[1].Score)
+}
+
+func TestMemoryIndex_RequiresEveryTerm(t *testing.T) {
+	index := newTestIndex(t)
+
+	hits, total, err := index.Search(context.Background(), 1, "clean golang", 10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, hits)
+}
+
+func TestMemoryIndex_IsolatesUsers(t *testing.T) {
+	index := newTestIndex(t)
+
+	hits, _, err := index.Search(context.Background(), 2, "clean", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(4), hits[0].BookmarkID)
+
+	hits, _, err = index.Search(context.Background(), 3, "clean", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+}
+
+func TestMemoryIndex_SearchesTagsAndURL(t *testing.T) {
+	index := newTestIndex(t)
+
+	hits, _, err := index.Search(context.Background(), 1, "GoLang", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(2), hits[0].BookmarkID)
+
+	hits, _, err = index.Search(context.Background(), 1, "talks", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(2), hits[0].BookmarkID)
+}
+
+func TestMemoryIndex_SearchesContent(t *testing.T) {
+	index := newTestIndex(t)
+
+	hits, _, err := index.Search(context.Background(), 1, "mutexes", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(2), hits[0].BookmarkID)
+	assert.Equal(t, "Channels orchestrate; <mark>mutexes</mark> serialize.", hits[0].Snippet)
+}
+
+func TestMemoryIndex_Paginates(t *testing.T) {
+	index := newTestIndex(t)
+
+	hits, total, err := index.Search(context.Background(), 1, "architecture", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(3), hits[0].BookmarkID)
+
+	hits, total, err = index.Search(context.Background(), 1, "architecture", 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Empty(t, hits)
+}
+
+func TestMemoryIndex_ReindexAndRemove(t *testing.T) {
+	index := newTestIndex(t)
+
+	require.NoError(t, index.Index(context.Background(), models.SearchDocument{BookmarkID: 2, UserID: 1, Title: "Rust ownership"}))
+	hits, _, err := index.Search(context.Background(), 1, "concurrency", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+
+	require.NoError(t, index.Remove(context.Background(), 1, 1))
+	hits, _, err = index.Search(context.Background(), 1, "clean", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(3), hits[0].BookmarkID)
+}
+
+func TestMemoryIndex_NeedsRebuild(t *testing.T) {
+	index := NewMemoryIndex()
+
+	needed, err := index.NeedsRebuild(context.Background())
+	require.NoError(t, err)
+	assert.True(t, needed)
+
+	require.NoError(t, index.Index(context.Background(), models.SearchDocument{BookmarkID: 1, UserID: 1, Title: "x"}))
+	needed, err = index.NeedsRebuild(context.Background())
+	require.NoError(t, err)
+	assert.False(t, needed)
+}
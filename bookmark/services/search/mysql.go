@@ -0,0 +1,88 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// The columns of the FULLTEXT index made by the migrations; MATCH has
+	// to name them all.
+	fulltextColumns = "title, url, notes, tags, content"
+
+	// InnoDB ignores shorter words unless innodb_ft_min_token_size is lowered.
+	mysqlMinTokenSize = 3
+)
+
+// MySQLIndex stores documents in search_documents and ranks them with an
+// InnoDB FULLTEXT index. Both come from the migrations.
+type MySQLIndex struct {
+	documentTable
+}
+
+func NewMySQLIndex(db *gorm.DB) *MySQLIndex {
+	return &MySQLIndex{documentTable{DB: db}}
+}
+
+func (m *MySQLIndex) Search(ctx context.Context, userID uint, query string, limit, offset int) ([]models.SearchHit, int64, error) {
+	terms, boolean := booleanQuery(query)
+	if boolean == "" {
+		return nil, 0, nil
+	}
+
+	match := "MATCH(" + fulltextColumns + ") AGAINST (? IN BOOLEAN MODE)"
+
+	var total int64
+	if err := m.DB.WithContext(ctx).Model(&models.SearchDocument{}).
+		Where("user_id = ?", userID).
+		Where(match, boolean).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.SearchHit{}, 0, nil
+	}
+
+	var rows []struct {
+		models.SearchDocument
+		Score float64
+	}
+	if err := m.DB.WithContext(ctx).Model(&models.SearchDocument{}).
+		Select("*, "+match+" AS score", boolean).
+		Where("user_id = ?", userID).
+		Where(match, boolean).
+		Order("score DESC").Order("bookmark_id DESC").
+		Limit(limit).Offset(offset).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]models.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, models.SearchHit{
+			BookmarkID: row.BookmarkID,
+			Score:      row.Score,
+			Snippet:    Snippet(row.SearchDocument, terms),
+		})
+	}
+
+	return hits, total, nil
+}
+
+// booleanQuery requires every term. Terms only hold letters and digits, so
+// nothing from the user can be read as a boolean-mode operator.
+func booleanQuery(query string) ([]string, string) {
+	terms := Terms(query)
+
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if len([]rune(term)) >= mysqlMinTokenSize {
+			parts = append(parts, "+"+term)
+		}
+	}
+
+	return terms, strings.Join(parts, " ")
+}
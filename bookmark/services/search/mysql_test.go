@@ -0,0 +1,57 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func newMySQLIndex(t *testing.T) (*MySQLIndex, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	require.NoError(t, err)
+
+	return NewMySQLIndex(gdb), mock
+}
+
+func TestMySQLIndex_Search(t *testing.T) {
+	index, mock := newMySQLIndex(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `search_documents` WHERE user_id = ? AND MATCH(title, url, notes, tags, content) AGAINST (? IN BOOLEAN MODE)")).
+		WithArgs(1, "+clean +architecture").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT *, MATCH(title, url, notes, tags, content) AGAINST (? IN BOOLEAN MODE) AS score FROM `search_documents` WHERE user_id = ? AND MATCH(title, url, notes, tags, content) AGAINST (? IN BOOLEAN MODE) ORDER BY score DESC,bookmark_id DESC LIMIT 10")).
+		WithArgs("+clean +architecture", 1, "+clean +architecture").
+		WillReturnRows(sqlmock.NewRows([]string{"bookmark_id", "user_id", "title", "url", "notes", "tags", "content", "score"}).
+			AddRow(7, 1, "The Clean Architecture", "https://example.com", "", "", "", 1.5))
+
+	hits, total, err := index.Search(context.Background(), 1, "Clean architecture", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(7), hits[0].BookmarkID)
+	assert.Equal(t, 1.5, hits[0].Score)
+	assert.Equal(t, "The <mark>Clean</mark> <mark>Architecture</mark>", hits[0].Snippet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLIndex_Search_ShortTermsOnly(t *testing.T) {
+	index, mock := newMySQLIndex(t)
+
+	hits, total, err := index.Search(context.Background(), 1, "go", 10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, hits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// The columns to load, leaving out the tsvector, which is only searched.
+	postgresColumns = "bookmark_id, user_id, title, url, notes, tags, content"
+	postgresMatch   = "document @@ plainto_tsquery('simple', ?)"
+	postgresRank    = "ts_rank(document, plainto_tsquery('simple', ?))"
+)
+
+// PostgresIndex stores documents in search_documents and ranks them by the
+// weighted tsvector the table generates for each, which a GIN index
+// searches. Both come from the migrations.
+type PostgresIndex struct {
+	documentTable
+}
+
+func NewPostgresIndex(db *gorm.DB) *PostgresIndex {
+	return &PostgresIndex{documentTable{DB: db}}
+}
+
+// Search requires every term. The terms are handed to plainto_tsquery, which
+// reads no operators, joined by spaces, so the URL is matched word by word
+// as the table stores it.
+func (p *PostgresIndex) Search(ctx context.Context, userID uint, query string, limit, offset int) ([]models.SearchHit, int64, error) {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+	words := strings.Join(terms, " ")
+
+	var total int64
+	if err := p.DB.WithContext(ctx).Model(&models.SearchDocument{}).
+		Where("user_id = ?", userID).
+		Where(postgresMatch, words).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.SearchHit{}, 0, nil
+	}
+
+	var rows []struct {
+		models.SearchDocument
+		Score float64
+	}
+	if err := p.DB.WithContext(ctx).Model(&models.SearchDocument{}).
+		Select(postgresColumns+", "+postgresRank+" AS score", words).
+		Where("user_id = ?", userID).
+		Where(postgresMatch, words).
+		Order("score DESC").Order("bookmark_id DESC").
+		Limit(limit).Offset(offset).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]models.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, models.SearchHit{
+			BookmarkID: row.BookmarkID,
+			Score:      row.Score,
+			Snippet:    Snippet(row.SearchDocument, terms),
+		})
+	}
+
+	return hits, total, nil
+}
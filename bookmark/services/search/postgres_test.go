@@ -0,0 +1,58 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newPostgresIndex(t *testing.T) (*PostgresIndex, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	require.NoError(t, err)
+
+	return NewPostgresIndex(gdb), mock
+}
+
+func TestPostgresIndex_Search(t *testing.T) {
+	index, mock := newPostgresIndex(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "search_documents" WHERE user_id = $1 AND document @@ plainto_tsquery('simple', $2)`)).
+		WithArgs(1, "clean architecture go").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT bookmark_id, user_id, title, url, notes, tags, content, ts_rank(document, plainto_tsquery('simple', $1)) AS score FROM "search_documents" WHERE user_id = $2 AND document @@ plainto_tsquery('simple', $3) ORDER BY score DESC,bookmark_id DESC LIMIT 10`)).
+		WithArgs("clean architecture go", 1, "clean architecture go").
+		WillReturnRows(sqlmock.NewRows([]string{"bookmark_id", "user_id", "title", "url", "notes", "tags", "content", "score"}).
+			AddRow(7, 1, "Clean Architecture in Go", "https://example.com", "", "", "", 0.6))
+
+	hits, total, err := index.Search(context.Background(), 1, "Clean architecture & go!", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(7), hits[0].BookmarkID)
+	assert.Equal(t, 0.6, hits[0].Score)
+	assert.Equal(t, "<mark>Clean</mark> <mark>Architecture</mark> in <mark>Go</mark>", hits[0].Snippet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresIndex_Index(t *testing.T) {
+	index, mock := newPostgresIndex(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "search_documents" ("bookmark_id","user_id","title","url","notes","tags","content") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("bookmark_id") DO UPDATE SET`)).
+		WithArgs(7, 1, "Clean Architecture in Go", "https://example.com", "", "", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, index.Index(context.Background(), models.SearchDocument{BookmarkID: 7, UserID: 1, Title: "Clean Architecture in Go", URL: "https://example.com"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,24 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"gorm.io/gorm"
+)
+
+// NewIndex picks the index for the database of db: MySQL FULLTEXT, a
+// PostgreSQL tsvector or, for SQLite, which only ever has one server, the
+// in-memory index.
+func NewIndex(db *gorm.DB) (services.SearchIndex, error) {
+	switch driver := db.Dialector.Name(); driver {
+	case "mysql":
+		return NewMySQLIndex(db), nil
+	case "postgres":
+		return NewPostgresIndex(db), nil
+	case "sqlite":
+		return NewMemoryIndex(), nil
+	default:
+		return nil, fmt.Errorf("search: no index for driver %q", driver)
+	}
+}
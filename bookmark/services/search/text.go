@@ -0,0 +1,147 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+const snippetLength = 160
+
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into lowercased runs of letters and digits, keeping
+// the byte offsets so matches can be highlighted in the original text.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// Terms returns the distinct terms of a query in the order they appear.
+func Terms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(query) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// Snippet picks the first field of doc that mentions one of terms and returns
+// an HTML-escaped excerpt around the match with every hit wrapped in <mark>.
+func Snippet(doc models.SearchDocument, terms []string) string {
+	for _, text := range snippetFields(doc) {
+		if snippet, ok := highlight(text, terms, snippetLength); ok {
+			return snippet
+		}
+	}
+	return html.EscapeString(truncate(doc.Title, snippetLength))
+}
+
+func highlight(text string, terms []string, width int) (string, bool) {
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	tokens := tokenize(text)
+	first := -1
+	for i, t := range tokens {
+		if want[t.term] {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	// Centre the window on the first hit, then widen it to token boundaries.
+	from := tokens[first].start - width/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + width
+	if to > len(text) {
+		to = len(text)
+		from = to - width
+		if from < 0 {
+			from = 0
+		}
+	}
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+	for _, t := range tokens {
+		if t.start < from && t.end > from {
+			from = t.start
+		}
+		if t.start < to && t.end > to {
+			to = t.end
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, t := range tokens {
+		if t.start < from || t.end > to || !want[t.term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:t.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[t.start:t.end]))
+		b.WriteString("</mark>")
+		pos = t.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+
+	return strings.TrimSpace(b.String()), true
+}
+
+func snippetFields(doc models.SearchDocument) []string {
+	return []string{doc.Notes, doc.Title, doc.Tags, doc.URL, doc.Content}
+}
+
+func truncate(text string, width int) string {
+	if len(text) <= width {
+		return text
+	}
+	for width > 0 && !utf8.RuneStart(text[width]) {
+		width--
+	}
+	return text[:width] + "…"
+}
@@ -0,0 +1,43 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"clean", "architecture", "café", "2012"}, Terms("Clean  architecture, CLEAN café/2012!"))
+	assert.Empty(t, Terms(" -- "))
+}
+
+func TestSnippet_HighlightsAndEscapes(t *testing.T) {
+	doc := models.SearchDocument{Title: "A <b>bold</b> Title", Notes: "nothing relevant"}
+
+	assert.Equal(t, "A &lt;b&gt;<mark>bold</mark>&lt;/b&gt; Title", Snippet(doc, []string{"bold"}))
+}
+
+func TestSnippet_WindowsLongText(t *testing.T) {
+	notes := strings.Repeat("lorem ipsum ", 40) + "the needle is here " + strings.Repeat("dolor sit ", 40)
+	doc := models.SearchDocument{Title: "Haystack", Notes: notes}
+
+	snippet := Snippet(doc, []string{"needle"})
+	assert.Contains(t, snippet, "<mark>needle</mark>")
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.True(t, len(snippet) < len(notes))
+}
+
+func TestSnippet_FallsBackToTitle(t *testing.T) {
+	doc := models.SearchDocument{Title: "Just a title"}
+
+	assert.Equal(t, "Just a title", Snippet(doc, []string{"missing"}))
+}
+
+func TestBooleanQuery(t *testing.T) {
+	terms, q := booleanQuery(`clean +arch* "go" -rust ab`)
+	assert.Equal(t, []string{"clean", "arch", "go", "rust", "ab"}, terms)
+	assert.Equal(t, "+clean +arch +rust", q)
+}
@@ -0,0 +1,99 @@
+// Package urlnorm reduces URLs to a form in which two addresses of the same
+// page compare equal.
+package urlnorm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+)
+
+// Query parameters that only tell the site where a visitor came from.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"gbraid":  true,
+	"wbraid":  true,
+	"msclkid": true,
+	"yclid":   true,
+	"twclid":  true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"mkt_tok": true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"_ga":     true,
+	"_gl":     true,
+}
+
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// Normalize returns rawURL without its scheme, user info, default port,
+// fragment, tracking parameters and trailing slash, with the host in lower
+// case and the remaining query parameters sorted. http and https addresses
+// of a page normalize the same.
+func Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", bookmark.ErrInvalidURL
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if (scheme != "http" && scheme != "https") || u.Hostname() == "" {
+		return "", bookmark.ErrInvalidURL
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if port := u.Port(); port != "" && port != defaultPorts[scheme] {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	// Escaping the decoded path again settles how it was escaped, unless
+	// that would turn an escaped slash into a real one.
+	path := u.EscapedPath()
+	if !strings.Contains(strings.ToUpper(u.RawPath), "%2F") {
+		path = (&url.URL{Path: u.Path}).EscapedPath()
+	}
+	path = strings.TrimRight(path, "/")
+
+	query := u.RawQuery
+	if values, err := url.ParseQuery(u.RawQuery); err == nil {
+		for key := range values {
+			if isTrackingParam(key) {
+				values.Del(key)
+			}
+		}
+		query = values.Encode()
+	}
+
+	normalized := host + path
+	if query != "" {
+		normalized += "?" + query
+	}
+	return normalized, nil
+}
+
+// Hash is the fixed-size form of a normalized URL that the database keeps
+// unique per user.
+func Hash(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsSiteRoot tells whether a normalized URL is the front page of a site.
+func IsSiteRoot(normalized string) bool {
+	return !strings.ContainsAny(normalized, "/?")
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasPrefix(key, "utm_") || trackingParams[key]
+}
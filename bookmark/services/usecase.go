@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+type UseCase interface {
+	CreateBookmark(ctx context.Context, userID uint, inp models.BookmarkInput) (*models.Bookmark, error)
+	GetBookmark(ctx context.Context, userID, id uint) (*models.Bookmark, error)
+	ListBookmarks(ctx context.Context, userID uint, inp models.ListInput) ([]models.Bookmark, error)
+	UpdateBookmark(ctx context.Context, userID, id uint, inp models.BookmarkInput) (*models.Bookmark, error)
+	DeleteBookmark(ctx context.Context, userID, id uint) error
+	GetContent(ctx context.Context, userID, id uint) (*models.BookmarkContent, error)
+	UpdateState(ctx context.Context, userID, id uint, inp models.StateInput) (*models.Bookmark, error)
+	BulkUpdateState(ctx context.Context, userID uint, inp models.BulkStateInput) (*models.BulkStateResponse, error)
+	Queue(ctx context.Context, userID uint, inp models.QueueInput) ([]models.Bookmark, error)
+}
+
+type TagUseCase interface {
+	AddTags(ctx context.Context, userID, bookmarkID uint, inp models.TagsInput) ([]models.Tag, error)
+	RemoveTag(ctx context.Context, userID, bookmarkID uint, name string) error
+	ListTags(ctx context.Context, userID uint) ([]models.TagCount, error)
+	RenameTag(ctx context.Context, userID, id uint, inp models.RenameTagInput) (*models.Tag, error)
+	MergeTags(ctx context.Context, userID uint, inp models.MergeTagsInput) (*models.Tag, error)
+	DeleteTag(ctx context.Context, userID, id uint) error
+}
+
+type CollectionUseCase interface {
+	ListCollections(ctx context.Context, userID uint) ([]models.CollectionNode, error)
+	CreateCollection(ctx context.Context, userID uint, inp models.CollectionInput) (*models.Collection, error)
+	GetCollection(ctx context.Context, userID, id uint) (*models.Collection, error)
+	RenameCollection(ctx context.Context, userID, id uint, inp models.RenameCollectionInput) (*models.Collection, error)
+	MoveCollection(ctx context.Context, userID, id uint, inp models.MoveCollectionInput) (*models.Collection, error)
+	DeleteCollection(ctx context.Context, userID, id uint, mode string) error
+	SetBookmarkCollection(ctx context.Context, userID, bookmarkID uint, inp models.BookmarkCollectionInput) (*models.Bookmark, error)
+}
+
+type ShareUseCase interface {
+	CreateShare(ctx context.Context, userID uint, inp models.ShareInput) (*models.Share, error)
+	ListShares(ctx context.Context, userID uint) ([]models.Share, error)
+	RevokeShare(ctx context.Context, userID, id uint) error
+	ViewShare(ctx context.Context, token, password string, inp models.ShareViewInput) (*models.PublicShare, error)
+}
+
+type FeedUseCase interface {
+	CreateFeed(ctx context.Context, userID uint, inp models.FeedInput) (*models.Feed, error)
+	ListFeeds(ctx context.Context, userID uint) ([]models.Feed, error)
+	RevokeFeed(ctx context.Context, userID, id uint) error
+	GetFeed(ctx context.Context, token string) (*models.FeedDocument, error)
+	WriteFeed(w io.Writer, format string, feed *models.FeedDocument) error
+}
+
+type HighlightUseCase interface {
+	CreateHighlight(ctx context.Context, userID, bookmarkID uint, inp models.HighlightInput) (*models.Highlight, error)
+	ListBookmarkHighlights(ctx context.Context, userID, bookmarkID uint) ([]models.Highlight, error)
+	ListHighlights(ctx context.Context, userID uint, inp models.HighlightListInput) ([]models.Highlight, error)
+	UpdateHighlight(ctx context.Context, userID, id uint, inp models.HighlightUpdateInput) (*models.Highlight, error)
+	DeleteHighlight(ctx context.Context, userID, id uint) error
+	ExportHighlights(ctx context.Context, userID, bookmarkID uint, w io.Writer) error
+}
+
+type SearchUseCase interface {
+	Search(ctx context.Context, userID uint, inp models.SearchInput) (*models.SearchResponse, error)
+	RebuildIndex(ctx context.Context) error
+}
+
+type MetadataUseCase interface {
+	Enqueue(ctx context.Context, userID, bookmarkID uint)
+	RefreshMetadata(ctx context.Context, userID, bookmarkID uint) (*models.Bookmark, error)
+}
+
+type ImportUseCase interface {
+	StartImport(ctx context.Context, userID uint, inp models.ImportInput, data []byte) (*models.ImportJob, error)
+	GetImport(ctx context.Context, userID, id uint) (*models.ImportJob, error)
+	ListImports(ctx context.Context, userID uint) ([]models.ImportJob, error)
+}
+
+type ExportUseCase interface {
+	ExportBookmarks(ctx context.Context, userID uint, format string, w io.Writer) error
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"gorm.io/gorm"
+)
+
+const maxCollectionName = 100
+
+type CollectionUseCase struct {
+	collectionRepo services.CollectionRepositorySQL
+	bookmarkRepo   services.BookmarkRepositorySQL
+	index          services.SearchIndex
+	log            *slog.Logger
+}
+
+func NewCollectionUseCase(collectionRepo services.CollectionRepositorySQL, bookmarkRepo services.BookmarkRepositorySQL, index services.SearchIndex, log *slog.Logger) *CollectionUseCase {
+	return &CollectionUseCase{
+		collectionRepo: collectionRepo,
+		bookmarkRepo:   bookmarkRepo,
+		index:          index,
+		log:            log,
+	}
+}
+
+// ListCollections returns the collections of a user as a tree.
+func (c *CollectionUseCase) ListCollections(ctx context.Context, userID uint) ([]models.CollectionNode, error) {
+	collections, err := c.collectionRepo.SQLListCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := c.collectionRepo.SQLCountCollectionBookmarks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.Collection)
+	for _, collection := range collections {
+		children[parentKey(collection.ParentID)] = append(children[parentKey(collection.ParentID)], collection)
+	}
+
+	var build func(parent uint) []models.CollectionNode
+	build = func(parent uint) []models.CollectionNode {
+		nodes := make([]models.CollectionNode, 0, len(children[parent]))
+		for _, collection := range children[parent] {
+			nodes = append(nodes, models.CollectionNode{
+				Collection:    collection,
+				BookmarkCount: counts[collection.ID],
+				Children:      build(collection.ID),
+			})
+		}
+		return nodes
+	}
+
+	return build(0), nil
+}
+
+func (c *CollectionUseCase) CreateCollection(ctx context.Context, userID uint, inp models.CollectionInput) (*models.Collection, error) {
+	name, err := normalizeCollectionName(inp.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := c.collectionRepo.SQLListCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := rootIfZero(inp.ParentID)
+	var parent *models.Collection
+	if parentID != nil {
+		if parent = findCollection(collections, *parentID); parent == nil {
+			return nil, bookmark.ErrCollectionNotFound
+		}
+		if collectionDepth(parent) >= models.MaxCollectionDepth {
+			return nil, bookmark.ErrCollectionDepth
+		}
+	}
+	if nameTaken(collections, parentID, name, 0) {
+		return nil, bookmark.ErrCollectionDuplicate
+	}
+
+	collection := &models.Collection{UserID: userID, ParentID: parentID, Name: name}
+	if err := c.collectionRepo.SQLCreateCollection(ctx, collection, parent); err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+func (c *CollectionUseCase) GetCollection(ctx context.Context, userID, id uint) (*models.Collection, error) {
+	collection, err := c.collectionRepo.SQLGetCollection(ctx, userID, id)
+	if err != nil {
+		return nil, collectionNotFound(err)
+	}
+
+	return collection, nil
+}
+
+func (c *CollectionUseCase) RenameCollection(ctx context.Context, userID, id uint, inp models.RenameCollectionInput) (*models.Collection, error) {
+	name, err := normalizeCollectionName(inp.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := c.collectionRepo.SQLListCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := findCollection(collections, id)
+	if collection == nil {
+		return nil, bookmark.ErrCollectionNotFound
+	}
+	if nameTaken(collections, collection.ParentID, name, id) {
+		return nil, bookmark.ErrCollectionDuplicate
+	}
+
+	if err := c.collectionRepo.SQLRenameCollection(ctx, userID, id, name); err != nil {
+		return nil, collectionNotFound(err)
+	}
+
+	collection.Name = name
+	return collection, nil
+}
+
+// MoveCollection reparents and/or reorders a collection together with
+// everything below it.
+func (c *CollectionUseCase) MoveCollection(ctx context.Context, userID, id uint, inp models.MoveCollectionInput) (*models.Collection, error) {
+	if inp.Position != nil && *inp.Position < 0 {
+		return nil, bookmark.ErrBadRequest
+	}
+
+	collections, err := c.collectionRepo.SQLListCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := findCollection(collections, id)
+	if collection == nil {
+		return nil, bookmark.ErrCollectionNotFound
+	}
+
+	parentID := rootIfZero(inp.ParentID)
+	var parent *models.Collection
+	depth := 0
+	if parentID != nil {
+		if parent = findCollection(collections, *parentID); parent == nil {
+			return nil, bookmark.ErrCollectionNotFound
+		}
+		if strings.HasPrefix(parent.Path, collection.Path) {
+			return nil, bookmark.ErrCollectionCycle
+		}
+		depth = collectionDepth(parent)
+	}
+
+	// The deepest collection of the subtree has to stay within the limit.
+	height := 0
+	for i := range collections {
+		if strings.HasPrefix(collections[i].Path, collection.Path) {
+			if h := collectionDepth(&collections[i]) - collectionDepth(collection) + 1; h > height {
+				height = h
+			}
+		}
+	}
+	if depth+height > models.MaxCollectionDepth {
+		return nil, bookmark.ErrCollectionDepth
+	}
+
+	if nameTaken(collections, parentID, collection.Name, id) {
+		return nil, bookmark.ErrCollectionDuplicate
+	}
+
+	siblings := 0
+	for _, other := range collections {
+		if other.ID != id && parentKey(other.ParentID) == parentKey(parentID) {
+			siblings++
+		}
+	}
+	position := siblings
+	if inp.Position != nil && *inp.Position < siblings {
+		position = *inp.Position
+	}
+
+	if err := c.collectionRepo.SQLMoveCollection(ctx, collection, parent, position); err != nil {
+		return nil, collectionNotFound(err)
+	}
+
+	return collection, nil
+}
+
+// DeleteCollection removes a collection. By default its bookmarks and child
+// collections move up to its parent; with CollectionDeleteDelete they are
+// deleted along with it.
+func (c *CollectionUseCase) DeleteCollection(ctx context.Context, userID, id uint, mode string) error {
+	switch mode {
+	case "":
+		mode = models.CollectionDeleteMove
+	case models.CollectionDeleteMove, models.CollectionDeleteDelete:
+	default:
+		return bookmark.ErrBadRequest
+	}
+
+	collection, err := c.collectionRepo.SQLGetCollection(ctx, userID, id)
+	if err != nil {
+		return collectionNotFound(err)
+	}
+
+	deleted, err := c.collectionRepo.SQLDeleteCollection(ctx, collection, mode)
+	if err != nil {
+		return collectionNotFound(err)
+	}
+
+	for _, bookmarkID := range deleted {
+		if err := c.index.Remove(ctx, userID, bookmarkID); err != nil {
+			c.log.ErrorContext(ctx, "search: failed to remove bookmark", "bookmark", bookmarkID, "err", err)
+		}
+	}
+	return nil
+}
+
+// SetBookmarkCollection files a bookmark under a collection, or at the top
+// when the collection id is left out or 0.
+func (c *CollectionUseCase) SetBookmarkCollection(ctx context.Context, userID, bookmarkID uint, inp models.BookmarkCollectionInput) (*models.Bookmark, error) {
+	collectionID := rootIfZero(inp.CollectionID)
+	if collectionID != nil {
+		if _, err := c.collectionRepo.SQLGetCollection(ctx, userID, *collectionID); err != nil {
+			return nil, collectionNotFound(err)
+		}
+	}
+
+	if err := c.collectionRepo.SQLSetBookmarkCollection(ctx, userID, bookmarkID, collectionID); err != nil {
+		return nil, notFound(err)
+	}
+
+	bm, err := c.bookmarkRepo.SQLGetBookmark(ctx, userID, bookmarkID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return bm, nil
+}
+
+func normalizeCollectionName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || len(name) > maxCollectionName {
+		return "", bookmark.ErrInvalidCollection
+	}
+	return name, nil
+}
+
+// nameTaken reports whether a sibling other than except already has name.
+// Names are compared case-insensitively.
+func nameTaken(collections []models.Collection, parentID *uint, name string, except uint) bool {
+	for _, other := range collections {
+		if other.ID != except && parentKey(other.ParentID) == parentKey(parentID) && strings.EqualFold(other.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func findCollection(collections []models.Collection, id uint) *models.Collection {
+	for i := range collections {
+		if collections[i].ID == id {
+			return &collections[i]
+		}
+	}
+	return nil
+}
+
+// collectionDepth is 1 for a top-level collection.
+func collectionDepth(collection *models.Collection) int {
+	return strings.Count(collection.Path, "/") - 1
+}
+
+func parentKey(parentID *uint) uint {
+	if parentID == nil {
+		return 0
+	}
+	return *parentID
+}
+
+func rootIfZero(id *uint) *uint {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return id
+}
+
+func collectionNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bookmark.ErrCollectionNotFound
+	}
+	return err
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func uintPtr(i uint) *uint { return &i }
+
+// work
+// ├── go
+// │   └── talks
+// └── sql
+// home
+func testCollections() []models.Collection {
+	return []models.Collection{
+		{ID: 1, UserID: 1, Name: "work", Path: "/1/", Position: 0},
+		{ID: 4, UserID: 1, Name: "home", Path: "/4/", Position: 1},
+		{ID: 2, UserID: 1, ParentID: uintPtr(1), Name: "go", Path: "/1/2/", Position: 0},
+		{ID: 3, UserID: 1, ParentID: uintPtr(1), Name: "sql", Path: "/1/3/", Position: 1},
+		{ID: 5, UserID: 1, ParentID: uintPtr(2), Name: "talks", Path: "/1/2/5/", Position: 0},
+	}
+}
+
+func Test_ListCollections_Tree(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	uc := NewCollectionUseCase(repo, new(mock.BookmarkStorageMock), search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLListCollections", uint(1)).Return(testCollections(), nil)
+	repo.On("SQLCountCollectionBookmarks", uint(1)).Return(map[uint]int64{2: 3, 5: 1}, nil)
+
+	tree, err := uc.ListCollections(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "work", tree[0].Name)
+	assert.Equal(t, "home", tree[1].Name)
+	assert.Empty(t, tree[1].Children)
+	assert.Equal(t, "go", tree[0].Children[0].Name)
+	assert.Equal(t, int64(3), tree[0].Children[0].BookmarkCount)
+	assert.Equal(t, "talks", tree[0].Children[0].Children[0].Name)
+	assert.Equal(t, "sql", tree[0].Children[1].Name)
+}
+
+func Test_CreateCollection_Success(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	uc := NewCollectionUseCase(repo, new(mock.BookmarkStorageMock), search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLListCollections", uint(1)).Return(testCollections(), nil)
+	repo.On("SQLCreateCollection", &models.Collection{UserID: 1, ParentID: uintPtr(1), Name: "rust lang"}, testifymock.MatchedBy(func(parent *models.Collection) bool {
+		return parent.ID == 1
+	})).Return(nil)
+
+	collection, err := uc.CreateCollection(context.Background(), 1, models.CollectionInput{Name: "  rust   lang ", ParentID: uintPtr(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, "rust lang", collection.Name)
+	repo.AssertExpectations(t)
+}
+
+func Test_CreateCollection_Failed(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	uc := NewCollectionUseCase(repo, new(mock.BookmarkStorageMock), search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLListCollections", uint(1)).Return(testCollections(), nil)
+
+	_, err := uc.CreateCollection(context.Background(), 1, models.CollectionInput{Name: "  "})
+	assert.Equal(t, bookmark.ErrInvalidCollection, err)
+	_, err = uc.CreateCollection(context.Background(), 1, models.CollectionInput{Name: "Go", ParentID: uintPtr(1)})
+	assert.Equal(t, bookmark.ErrCollectionDuplicate, err)
+	_, err = uc.CreateCollection(context.Background(), 1, models.CollectionInput{Name: "go", ParentID: uintPtr(99)})
+	assert.Equal(t, bookmark.ErrCollectionNotFound, err)
+
+	// A top-level "go" is fine, it only clashes under work.
+	repo.On("SQLCreateCollection", testifymock.Anything, (*models.Collection)(nil)).Return(nil)
+	_, err = uc.CreateCollection(context.Background(), 1, models.CollectionInput{Name: "go", ParentID: uintPtr(0)})
+	assert.NoError(t, err)
+}
+
+func Test_CreateCollection_Depth(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	uc := NewCollectionUseCase(repo, new(mock.BookmarkStorageMock), search.NewMemoryIndex(), logging.Discard())
+
+	deep := models.Collection{ID: 10, UserID: 1, Name: "deep", Path: "/1/2/3/4/5/6/7/8/9/10/"}
+	repo.On("SQLListCollections", uint(1)).Return([]models.Collection{deep}, nil)
+
+	_, err := uc.CreateCollection(context.Background(), 1, models.CollectionInput{Name: "deeper", ParentID: uintPtr(10)})
+	assert.Equal(t, bookmark.ErrCollectionDepth, err)
+}
+
+func Test_MoveCollection_Success(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	uc := NewCollectionUseCase(repo, new(mock.BookmarkStorageMock), search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLListCollections", uint(1)).Return(testCollections(), nil)
+	// go moves under home, which has no children, so position 5 means last.
+	repo.On("SQLMoveCollection", testifymock.MatchedBy(func(c *models.Collection) bool { return c.ID == 2 }),
+		testifymock.MatchedBy(func(p *models.Collection) bool { return p != nil && p.ID == 4 }), 0).Return(nil)
+	// sql moves to the top, in front of work.
+	repo.On("SQLMoveCollection", testifymock.MatchedBy(func(c *models.Collection) bool { return c.ID == 3 }),
+		(*models.Collection)(nil), 0).Return(nil)
+
+	_, err := uc.MoveCollection(context.Background(), 1, 2, models.MoveCollectionInput{ParentID: uintPtr(4), Position: intPtr(5)})
+	assert.NoError(t, err)
+	_, err = uc.MoveCollection(context.Background(), 1, 3, models.MoveCollectionInput{Position: intPtr(0)})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func Test_MoveCollection_Failed(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	uc := NewCollectionUseCase(repo, new(mock.BookmarkStorageMock), search.NewMemoryIndex(), logging.Discard())
+
+	collections := append(testCollections(), models.Collection{ID: 6, UserID: 1, ParentID: uintPtr(4), Name: "SQL", Path: "/4/6/"})
+	repo.On("SQLListCollections", uint(1)).Return(collections, nil)
+
+	_, err := uc.MoveCollection(context.Background(), 1, 1, models.MoveCollectionInput{ParentID: uintPtr(5)})
+	assert.Equal(t, bookmark.ErrCollectionCycle, err)
+	_, err = uc.MoveCollection(context.Background(), 1, 2, models.MoveCollectionInput{ParentID: uintPtr(2)})
+	assert.Equal(t, bookmark.ErrCollectionCycle, err)
+	_, err = uc.MoveCollection(context.Background(), 1, 3, models.MoveCollectionInput{ParentID: uintPtr(4)})
+	assert.Equal(t, bookmark.ErrCollectionDuplicate, err)
+	_, err = uc.MoveCollection(context.Background(), 1, 99, models.MoveCollectionInput{})
+	assert.Equal(t, bookmark.ErrCollectionNotFound, err)
+	_, err = uc.MoveCollection(context.Background(), 1, 2, models.MoveCollectionInput{Position: intPtr(-1)})
+	assert.Equal(t, bookmark.ErrBadRequest, err)
+	repo.AssertNotCalled(t, "SQLMoveCollection", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func Test_DeleteCollection(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	index := search.NewMemoryIndex()
+	uc := NewCollectionUseCase(repo, new(mock.BookmarkStorageMock), index, logging.Discard())
+
+	index.Index(context.Background(), models.SearchDocument{UserID: 1, BookmarkID: 7, Title: "Go talks"})
+	collection := &models.Collection{ID: 2, UserID: 1, Path: "/1/2/"}
+	repo.On("SQLGetCollection", uint(1), uint(2)).Return(collection, nil)
+	repo.On("SQLGetCollection", uint(2), uint(2)).Return((*models.Collection)(nil), gorm.ErrRecordNotFound)
+	repo.On("SQLDeleteCollection", collection, models.CollectionDeleteMove).Return([]uint(nil), nil)
+	repo.On("SQLDeleteCollection", collection, models.CollectionDeleteDelete).Return([]uint{7}, nil)
+
+	assert.NoError(t, uc.DeleteCollection(context.Background(), 1, 2, ""))
+	hits, _, _ := index.Search(context.Background(), 1, "talks", 10, 0)
+	assert.Len(t, hits, 1)
+
+	assert.NoError(t, uc.DeleteCollection(context.Background(), 1, 2, models.CollectionDeleteDelete))
+	hits, _, _ = index.Search(context.Background(), 1, "talks", 10, 0)
+	assert.Empty(t, hits)
+
+	assert.Equal(t, bookmark.ErrBadRequest, uc.DeleteCollection(context.Background(), 1, 2, "shred"))
+	assert.Equal(t, bookmark.ErrCollectionNotFound, uc.DeleteCollection(context.Background(), 2, 2, ""))
+}
+
+func Test_SetBookmarkCollection(t *testing.T) {
+	repo := new(mock.CollectionStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewCollectionUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLGetCollection", uint(1), uint(2)).Return(&models.Collection{ID: 2}, nil)
+	repo.On("SQLGetCollection", uint(1), uint(9)).Return((*models.Collection)(nil), gorm.ErrRecordNotFound)
+	repo.On("SQLSetBookmarkCollection", uint(1), uint(7), uintPtr(2)).Return(nil)
+	repo.On("SQLSetBookmarkCollection", uint(1), uint(7), (*uint)(nil)).Return(nil)
+	repo.On("SQLSetBookmarkCollection", uint(1), uint(8), (*uint)(nil)).Return(gorm.ErrRecordNotFound)
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7, CollectionID: uintPtr(2)}, nil)
+
+	bm, err := uc.SetBookmarkCollection(context.Background(), 1, 7, models.BookmarkCollectionInput{CollectionID: uintPtr(2)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), *bm.CollectionID)
+
+	_, err = uc.SetBookmarkCollection(context.Background(), 1, 7, models.BookmarkCollectionInput{CollectionID: uintPtr(0)})
+	assert.NoError(t, err)
+
+	_, err = uc.SetBookmarkCollection(context.Background(), 1, 7, models.BookmarkCollectionInput{CollectionID: uintPtr(9)})
+	assert.Equal(t, bookmark.ErrCollectionNotFound, err)
+	_, err = uc.SetBookmarkCollection(context.Background(), 1, 8, models.BookmarkCollectionInput{})
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+}
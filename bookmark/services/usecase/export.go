@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+const exportBatchSize = 500
+
+type ExportUseCase struct {
+	bookmarkRepo services.BookmarkRepositorySQL
+	exporter     services.Exporter
+}
+
+func NewExportUseCase(bookmarkRepo services.BookmarkRepositorySQL, exporter services.Exporter) *ExportUseCase {
+	return &ExportUseCase{
+		bookmarkRepo: bookmarkRepo,
+		exporter:     exporter,
+	}
+}
+
+// ExportBookmarks writes every bookmark of the user to w, oldest first,
+// reading them exportBatchSize at a time.
+func (e *ExportUseCase) ExportBookmarks(ctx context.Context, userID uint, format string, w io.Writer) error {
+	enc, err := e.exporter.NewEncoder(format, w)
+	if err != nil {
+		return err
+	}
+
+	err = e.bookmarkRepo.SQLEachUserBookmark(ctx, userID, exportBatchSize, func(batch []models.Bookmark) error {
+		for i := range batch {
+			if err := enc.Encode(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
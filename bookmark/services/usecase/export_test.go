@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/exporter"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportBookmarks_Success(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewExportUseCase(repo, exporter.NewExporter())
+
+	repo.On("SQLEachUserBookmark", uint(1), exportBatchSize).Return([]models.Bookmark{
+		{URL: "https://go.dev/", Title: "Go", Tags: []models.Tag{{Name: "golang"}}},
+	}, nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, uc.ExportBookmarks(context.Background(), 1, models.ExportFormatCSV, &buf))
+	assert.Contains(t, buf.String(), "https://go.dev/,Go,,golang,")
+}
+
+func Test_ExportBookmarks_Failed(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewExportUseCase(repo, exporter.NewExporter())
+
+	var buf bytes.Buffer
+	assert.Equal(t, bookmark.ErrExportFormat, uc.ExportBookmarks(context.Background(), 1, "pdf", &buf))
+	repo.AssertNotCalled(t, "SQLEachUserBookmark", uint(1), exportBatchSize)
+
+	repo.On("SQLEachUserBookmark", uint(1), exportBatchSize).Return([]models.Bookmark{}, errors.New("some error"))
+	assert.Error(t, uc.ExportBookmarks(context.Background(), 1, models.ExportFormatJSON, &buf))
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"gorm.io/gorm"
+)
+
+type FeedUseCase struct {
+	feedRepo       services.FeedRepositorySQL
+	bookmarkRepo   services.BookmarkRepositorySQL
+	tagRepo        services.TagRepositorySQL
+	collectionRepo services.CollectionRepositorySQL
+	exporter       services.Exporter
+	log            *slog.Logger
+}
+
+func NewFeedUseCase(feedRepo services.FeedRepositorySQL, bookmarkRepo services.BookmarkRepositorySQL, tagRepo services.TagRepositorySQL, collectionRepo services.CollectionRepositorySQL, exporter services.Exporter, log *slog.Logger) *FeedUseCase {
+	return &FeedUseCase{
+		feedRepo:       feedRepo,
+		bookmarkRepo:   bookmarkRepo,
+		tagRepo:        tagRepo,
+		collectionRepo: collectionRepo,
+		exporter:       exporter,
+		log:            log,
+	}
+}
+
+// CreateFeed creates a feed of the user's public bookmarks, of those with
+// one tag, or of everything in one collection. A collection feed shares the
+// collection the way a share link does, public or not.
+func (f *FeedUseCase) CreateFeed(ctx context.Context, userID uint, inp models.FeedInput) (*models.Feed, error) {
+	collectionID := rootIfZero(inp.CollectionID)
+	if strings.TrimSpace(inp.Tag) != "" && collectionID != nil {
+		return nil, bookmark.ErrFeedTarget
+	}
+
+	feed := &models.Feed{UserID: userID, CollectionID: collectionID}
+	if strings.TrimSpace(inp.Tag) != "" {
+		name, err := normalizeTagName(inp.Tag)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := f.tagRepo.SQLGetTagByName(ctx, userID, name)
+		if err != nil {
+			return nil, tagNotFound(err)
+		}
+		feed.TagID = &tag.ID
+	} else if collectionID != nil {
+		if _, err := f.collectionRepo.SQLGetCollection(ctx, userID, *collectionID); err != nil {
+			return nil, collectionNotFound(err)
+		}
+	}
+
+	token, err := randomString(shareTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	feed.Token = token
+
+	if err := f.feedRepo.SQLCreateFeed(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (f *FeedUseCase) ListFeeds(ctx context.Context, userID uint) ([]models.Feed, error) {
+	return f.feedRepo.SQLListFeeds(ctx, userID)
+}
+
+func (f *FeedUseCase) RevokeFeed(ctx context.Context, userID, id uint) error {
+	if err := f.feedRepo.SQLRevokeFeed(ctx, userID, id, time.Now()); err != nil {
+		return feedNotFound(err)
+	}
+
+	return nil
+}
+
+// GetFeed loads the newest bookmarks of the feed behind token. Revoked
+// feeds, and those whose tag or collection is gone, look like unknown ones.
+func (f *FeedUseCase) GetFeed(ctx context.Context, token string) (*models.FeedDocument, error) {
+	feed, err := f.feedRepo.SQLGetFeedByToken(ctx, token)
+	if err != nil {
+		return nil, feedNotFound(err)
+	}
+	if feed.RevokedAt != nil {
+		return nil, bookmark.ErrFeedNotFound
+	}
+
+	doc := &models.FeedDocument{Updated: feed.CreatedAt}
+	inp := models.ListInput{Limit: models.FeedSize, TagMode: models.TagModeAny}
+	switch {
+	case feed.TagID != nil:
+		tag, err := f.tagRepo.SQLGetTag(ctx, feed.UserID, *feed.TagID)
+		if err != nil {
+			return nil, feedNotFound(err)
+		}
+		doc.Title = "Bookmarks tagged " + tag.Name
+		inp.Public = true
+		inp.TagNames = []string{tag.Name}
+	case feed.CollectionID != nil:
+		collection, err := f.collectionRepo.SQLGetCollection(ctx, feed.UserID, *feed.CollectionID)
+		if err != nil {
+			return nil, feedNotFound(err)
+		}
+		doc.Title = collection.Name
+		inp.Collection = collection.ID
+		inp.Recursive = true
+	default:
+		doc.Title = "Public bookmarks"
+		inp.Public = true
+	}
+
+	bookmarks, err := f.bookmarkRepo.SQLListBookmarks(ctx, feed.UserID, inp)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Bookmarks = make([]models.FeedEntry, 0, len(bookmarks))
+	for _, bm := range bookmarks {
+		doc.Bookmarks = append(doc.Bookmarks, models.FeedEntry{
+			ID:          bm.ID,
+			URL:         bm.URL,
+			Title:       bm.Title,
+			Description: bm.Description,
+			SiteName:    bm.SiteName,
+			CreatedAt:   bm.CreatedAt,
+			UpdatedAt:   bm.UpdatedAt,
+		})
+		if bm.UpdatedAt.After(doc.Updated) {
+			doc.Updated = bm.UpdatedAt
+		}
+	}
+	doc.ETag = feedETag(doc)
+	f.trackChange(ctx, feed, doc)
+
+	return doc, nil
+}
+
+// trackChange keeps Updated from going back in time. When a bookmark
+// leaves the feed, the newest one left may be older than what readers were
+// told last, so a change the bookmarks do not date counts from now.
+func (f *FeedUseCase) trackChange(ctx context.Context, feed *models.Feed, doc *models.FeedDocument) {
+	if feed.ChangedAt != nil && feed.ChangedAt.After(doc.Updated) {
+		doc.Updated = *feed.ChangedAt
+	}
+	if doc.ETag == feed.ETag {
+		return
+	}
+	if feed.ChangedAt != nil && !doc.Updated.After(*feed.ChangedAt) {
+		doc.Updated = time.Now()
+	}
+
+	if err := f.feedRepo.SQLSetFeedChange(ctx, feed.ID, doc.ETag, doc.Updated); err != nil {
+		f.log.ErrorContext(ctx, "feed: failed to save change", "feed", feed.ID, "err", err)
+	}
+}
+
+func (f *FeedUseCase) WriteFeed(w io.Writer, format string, feed *models.FeedDocument) error {
+	return f.exporter.WriteFeed(w, format, feed)
+}
+
+// feedETag sums up everything that ends up in a feed, so it changes when a
+// bookmark is added, edited or leaves the feed.
+func feedETag(doc *models.FeedDocument) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", doc.Title)
+	for _, entry := range doc.Bookmarks {
+		fmt.Fprintf(h, "%d\n%d\n%s\n%s\n%s\n%s\n", entry.ID, entry.UpdatedAt.UnixNano(), entry.URL, entry.Title, entry.Description, entry.SiteName)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func feedNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bookmark.ErrFeedNotFound
+	}
+	return err
+}
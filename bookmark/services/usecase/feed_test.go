@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/exporter"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newFeedUseCase() (*FeedUseCase, *mock.FeedStorageMock, *mock.BookmarkStorageMock, *mock.TagStorageMock, *mock.CollectionStorageMock) {
+	feedRepo := new(mock.FeedStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	tagRepo := new(mock.TagStorageMock)
+	collectionRepo := new(mock.CollectionStorageMock)
+	return NewFeedUseCase(feedRepo, bookmarkRepo, tagRepo, collectionRepo, exporter.NewExporter(), logging.Discard()), feedRepo, bookmarkRepo, tagRepo, collectionRepo
+}
+
+func Test_CreateFeed(t *testing.T) {
+	uc, feedRepo, _, tagRepo, _ := newFeedUseCase()
+
+	tagRepo.On("SQLGetTagByName", uint(1), "golang").Return(&models.Tag{ID: 4, Name: "golang"}, nil)
+	tagRepo.On("SQLGetTagByName", uint(1), "rust").Return((*models.Tag)(nil), gorm.ErrRecordNotFound)
+	feedRepo.On("SQLCreateFeed", testifymock.Anything).Return(nil)
+
+	feed, err := uc.CreateFeed(context.Background(), 1, models.FeedInput{Tag: " GoLang "})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), *feed.TagID)
+	assert.Len(t, feed.Token, 43)
+
+	_, err = uc.CreateFeed(context.Background(), 1, models.FeedInput{Tag: "rust"})
+	assert.Equal(t, bookmark.ErrTagNotFound, err)
+	_, err = uc.CreateFeed(context.Background(), 1, models.FeedInput{Tag: "golang", CollectionID: uintPtr(2)})
+	assert.Equal(t, bookmark.ErrFeedTarget, err)
+}
+
+func Test_GetFeed_Public(t *testing.T) {
+	uc, feedRepo, bookmarkRepo, _, _ := newFeedUseCase()
+
+	created := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	feedRepo.On("SQLGetFeedByToken", "abc").Return(&models.Feed{ID: 2, UserID: 1, CreatedAt: created}, nil)
+	bookmarkRepo.On("SQLListBookmarks", uint(1), models.ListInput{Limit: models.FeedSize, TagMode: models.TagModeAny, Public: true}).
+		Return([]models.Bookmark{
+			{ID: 8, URL: "https://go.dev/", Title: "Go", Notes: "private", CreatedAt: created, UpdatedAt: created.Add(2 * time.Hour)},
+			{ID: 7, URL: "https://example.com/", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+		}, nil)
+	feedRepo.On("SQLSetFeedChange", uint(2), testifymock.Anything, created.Add(2*time.Hour)).Return(nil)
+
+	feed, err := uc.GetFeed(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "Public bookmarks", feed.Title)
+	assert.Equal(t, created.Add(2*time.Hour), feed.Updated)
+	assert.Len(t, feed.Bookmarks, 2)
+	assert.Len(t, feed.ETag, 32)
+	feedRepo.AssertCalled(t, "SQLSetFeedChange", uint(2), feed.ETag, created.Add(2*time.Hour))
+
+	again, err := uc.GetFeed(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, feed.ETag, again.ETag)
+}
+
+func Test_GetFeed_UpdatedNeverGoesBack(t *testing.T) {
+	uc, feedRepo, bookmarkRepo, _, _ := newFeedUseCase()
+
+	created := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	served := created.Add(3 * time.Hour)
+	// The bookmark updated at served has since left the feed.
+	feedRepo.On("SQLGetFeedByToken", "abc").Return(&models.Feed{ID: 2, UserID: 1, CreatedAt: created, ETag: "old", ChangedAt: &served}, nil)
+	bookmarkRepo.On("SQLListBookmarks", uint(1), testifymock.Anything).
+		Return([]models.Bookmark{{ID: 7, URL: "https://example.com/", CreatedAt: created, UpdatedAt: created.Add(time.Hour)}}, nil)
+	feedRepo.On("SQLSetFeedChange", uint(2), testifymock.Anything, testifymock.Anything).Return(nil)
+
+	before := time.Now()
+	feed, err := uc.GetFeed(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.False(t, feed.Updated.Before(before), feed.Updated)
+	feedRepo.AssertCalled(t, "SQLSetFeedChange", uint(2), feed.ETag, feed.Updated)
+
+	// Served again unchanged, it keeps the date it was given.
+	unchanged := &models.Feed{ID: 3, UserID: 1, CreatedAt: created, ETag: feed.ETag, ChangedAt: &feed.Updated}
+	feedRepo.On("SQLGetFeedByToken", "same").Return(unchanged, nil)
+	again, err := uc.GetFeed(context.Background(), "same")
+	assert.NoError(t, err)
+	assert.Equal(t, feed.Updated, again.Updated)
+	feedRepo.AssertNotCalled(t, "SQLSetFeedChange", uint(3), testifymock.Anything, testifymock.Anything)
+}
+
+func Test_GetFeed_Tag(t *testing.T) {
+	uc, feedRepo, bookmarkRepo, tagRepo, _ := newFeedUseCase()
+
+	feedRepo.On("SQLGetFeedByToken", "abc").Return(&models.Feed{ID: 2, UserID: 1, TagID: uintPtr(4)}, nil)
+	tagRepo.On("SQLGetTag", uint(1), uint(4)).Return(&models.Tag{ID: 4, Name: "golang"}, nil)
+	bookmarkRepo.On("SQLListBookmarks", uint(1), models.ListInput{Limit: models.FeedSize, TagMode: models.TagModeAny, Public: true, TagNames: []string{"golang"}}).
+		Return([]models.Bookmark{}, nil)
+	feedRepo.On("SQLSetFeedChange", uint(2), testifymock.Anything, testifymock.Anything).Return(nil)
+
+	feed, err := uc.GetFeed(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bookmarks tagged golang", feed.Title)
+	assert.Empty(t, feed.Bookmarks)
+}
+
+func Test_GetFeed_Failed(t *testing.T) {
+	uc, feedRepo, _, _, collectionRepo := newFeedUseCase()
+
+	revoked := time.Now()
+	feedRepo.On("SQLGetFeedByToken", "revoked").Return(&models.Feed{ID: 2, UserID: 1, RevokedAt: &revoked}, nil)
+	feedRepo.On("SQLGetFeedByToken", "missing").Return((*models.Feed)(nil), gorm.ErrRecordNotFound)
+	feedRepo.On("SQLGetFeedByToken", "orphan").Return(&models.Feed{ID: 3, UserID: 1, CollectionID: uintPtr(5)}, nil)
+	collectionRepo.On("SQLGetCollection", uint(1), uint(5)).Return((*models.Collection)(nil), gorm.ErrRecordNotFound)
+
+	for _, token := range []string{"revoked", "missing", "orphan"} {
+		_, err := uc.GetFeed(context.Background(), token)
+		assert.Equal(t, bookmark.ErrFeedNotFound, err, token)
+	}
+}
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"gorm.io/gorm"
+)
+
+type HighlightUseCase struct {
+	highlightRepo services.HighlightRepositorySQL
+	bookmarkRepo  services.BookmarkRepositorySQL
+	exporter      services.Exporter
+}
+
+func NewHighlightUseCase(highlightRepo services.HighlightRepositorySQL, bookmarkRepo services.BookmarkRepositorySQL, exporter services.Exporter) *HighlightUseCase {
+	return &HighlightUseCase{
+		highlightRepo: highlightRepo,
+		bookmarkRepo:  bookmarkRepo,
+		exporter:      exporter,
+	}
+}
+
+func (h *HighlightUseCase) CreateHighlight(ctx context.Context, userID, bookmarkID uint, inp models.HighlightInput) (*models.Highlight, error) {
+	color, err := highlightColor(inp.Color)
+	if err != nil {
+		return nil, err
+	}
+	note, err := highlightNote(inp.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := h.content(ctx, userID, bookmarkID)
+	if err != nil {
+		return nil, err
+	}
+
+	text := []rune(content.Text)
+	if inp.Start < 0 || inp.End > len(text) || inp.Start >= inp.End || inp.End-inp.Start > models.MaxHighlightLength {
+		return nil, bookmark.ErrInvalidHighlight
+	}
+	quote := string(text[inp.Start:inp.End])
+	if strings.TrimSpace(quote) == "" {
+		return nil, bookmark.ErrInvalidHighlight
+	}
+
+	highlight := &models.Highlight{
+		UserID:     userID,
+		BookmarkID: bookmarkID,
+		Quote:      quote,
+		Note:       note,
+		Color:      color,
+	}
+	setAnchor(highlight, text, inp.Start)
+
+	if err := h.highlightRepo.SQLCreateHighlight(ctx, highlight); err != nil {
+		return nil, err
+	}
+
+	return highlight, nil
+}
+
+// ListBookmarkHighlights lists the highlights of a bookmark in article
+// order, anchoring them in the article as it is now. Those whose quote is
+// gone are kept where they were and flagged Orphaned. The new anchors are
+// only saved when the article is fetched again, by reanchorHighlights.
+func (h *HighlightUseCase) ListBookmarkHighlights(ctx context.Context, userID, bookmarkID uint) ([]models.Highlight, error) {
+	if _, err := h.bookmarkRepo.SQLGetBookmark(ctx, userID, bookmarkID); err != nil {
+		return nil, notFound(err)
+	}
+
+	highlights, err := h.highlightRepo.SQLListBookmarkHighlights(ctx, userID, bookmarkID)
+	if err != nil || len(highlights) == 0 {
+		return highlights, err
+	}
+
+	content, err := h.bookmarkRepo.SQLGetContent(ctx, userID, bookmarkID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var text []rune
+	if content != nil {
+		text = []rune(content.Text)
+	}
+	for i := range highlights {
+		reanchor(&highlights[i], text)
+	}
+
+	sort.SliceStable(highlights, func(i, j int) bool {
+		return highlights[i].StartOffset < highlights[j].StartOffset
+	})
+	return highlights, nil
+}
+
+func (h *HighlightUseCase) ListHighlights(ctx context.Context, userID uint, inp models.HighlightListInput) ([]models.Highlight, error) {
+	limit, offset := pageBounds(inp.Limit, inp.Offset)
+	return h.highlightRepo.SQLListHighlights(ctx, userID, limit, offset)
+}
+
+func (h *HighlightUseCase) UpdateHighlight(ctx context.Context, userID, id uint, inp models.HighlightUpdateInput) (*models.Highlight, error) {
+	highlight, err := h.highlightRepo.SQLGetHighlight(ctx, userID, id)
+	if err != nil {
+		return nil, highlightNotFound(err)
+	}
+
+	if inp.Note != nil {
+		if highlight.Note, err = highlightNote(*inp.Note); err != nil {
+			return nil, err
+		}
+	}
+	if inp.Color != nil {
+		if highlight.Color, err = highlightColor(*inp.Color); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := h.highlightRepo.SQLUpdateHighlight(ctx, highlight); err != nil {
+		return nil, highlightNotFound(err)
+	}
+
+	return highlight, nil
+}
+
+func (h *HighlightUseCase) DeleteHighlight(ctx context.Context, userID, id uint) error {
+	return highlightNotFound(h.highlightRepo.SQLDeleteHighlight(ctx, userID, id))
+}
+
+// ExportHighlights writes the highlights of one bookmark, or of all of them
+// when bookmarkID is 0, to w as Markdown. Bookmarks come most recently
+// highlighted first.
+func (h *HighlightUseCase) ExportHighlights(ctx context.Context, userID, bookmarkID uint, w io.Writer) error {
+	var (
+		highlights []models.Highlight
+		err        error
+	)
+	if bookmarkID != 0 {
+		highlights, err = h.ListBookmarkHighlights(ctx, userID, bookmarkID)
+	} else {
+		highlights, err = h.highlightRepo.SQLListHighlights(ctx, userID, 0, 0)
+	}
+	if err != nil {
+		return err
+	}
+
+	var ids []uint
+	byBookmark := make(map[uint][]models.Highlight)
+	for _, highlight := range highlights {
+		if _, ok := byBookmark[highlight.BookmarkID]; !ok {
+			ids = append(ids, highlight.BookmarkID)
+		}
+		byBookmark[highlight.BookmarkID] = append(byBookmark[highlight.BookmarkID], highlight)
+	}
+
+	bookmarks, err := h.bookmarkRepo.SQLGetBookmarksByIDs(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]models.Bookmark, len(bookmarks))
+	for _, bm := range bookmarks {
+		byID[bm.ID] = bm
+	}
+
+	groups := make([]models.HighlightGroup, 0, len(ids))
+	for _, id := range ids {
+		group := models.HighlightGroup{Bookmark: byID[id], Highlights: byBookmark[id]}
+		sort.SliceStable(group.Highlights, func(i, j int) bool {
+			return group.Highlights[i].StartOffset < group.Highlights[j].StartOffset
+		})
+		groups = append(groups, group)
+	}
+
+	return h.exporter.WriteHighlights(w, groups)
+}
+
+func (h *HighlightUseCase) content(ctx context.Context, userID, bookmarkID uint) (*models.BookmarkContent, error) {
+	if _, err := h.bookmarkRepo.SQLGetBookmark(ctx, userID, bookmarkID); err != nil {
+		return nil, notFound(err)
+	}
+
+	content, err := h.bookmarkRepo.SQLGetContent(ctx, userID, bookmarkID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, bookmark.ErrContentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// reanchorHighlights moves the highlights of a bookmark to where their
+// quotes are in its new article text, and saves those that moved.
+func reanchorHighlights(ctx context.Context, log *slog.Logger, repo services.HighlightRepositorySQL, userID, bookmarkID uint, text string) {
+	highlights, err := repo.SQLListBookmarkHighlights(ctx, userID, bookmarkID)
+	if err != nil {
+		log.ErrorContext(ctx, "highlight: failed to list highlights", "bookmark", bookmarkID, "err", err)
+		return
+	}
+
+	runes := []rune(text)
+	for i := range highlights {
+		if !reanchor(&highlights[i], runes) {
+			continue
+		}
+		if err := repo.SQLUpdateHighlightAnchor(ctx, &highlights[i]); err != nil {
+			log.ErrorContext(ctx, "highlight: failed to save new anchor", "highlight", highlights[i].ID, "err", err)
+		}
+	}
+}
+
+// reanchor moves a highlight to where its quote is in text now, and
+// reports whether it moved.
+func reanchor(highlight *models.Highlight, text []rune) bool {
+	start, ok := findAnchor(highlight, text)
+	if !ok {
+		highlight.Orphaned = true
+		return false
+	}
+	if start == highlight.StartOffset {
+		return false
+	}
+
+	setAnchor(highlight, text, start)
+	return true
+}
+
+// findAnchor returns where the quote of a highlight starts in text. When it
+// is no longer at the stored offsets, the occurrence with the most matching
+// context around it wins, then the one closest to where it was.
+func findAnchor(highlight *models.Highlight, text []rune) (int, bool) {
+	quote := []rune(highlight.Quote)
+	start, end := highlight.StartOffset, highlight.EndOffset
+	if start >= 0 && end <= len(text) && end-start == len(quote) && string(text[start:end]) == highlight.Quote {
+		return start, true
+	}
+
+	prefix, suffix := []rune(highlight.Prefix), []rune(highlight.Suffix)
+	best, bestScore := -1, -1
+	for _, i := range occurrences(string(text), highlight.Quote) {
+		score := commonSuffix(text[:i], prefix) + commonPrefix(text[i+len(quote):], suffix)
+		if score > bestScore || (score == bestScore && distance(i, start) < distance(best, start)) {
+			best, bestScore = i, score
+		}
+	}
+
+	return best, best >= 0
+}
+
+// setAnchor places a highlight at start in text and keeps the context
+// around it.
+func setAnchor(highlight *models.Highlight, text []rune, start int) {
+	end := start + utf8.RuneCountInString(highlight.Quote)
+	highlight.StartOffset = start
+	highlight.EndOffset = end
+
+	from := start - models.HighlightContext
+	if from < 0 {
+		from = 0
+	}
+	to := end + models.HighlightContext
+	if to > len(text) {
+		to = len(text)
+	}
+	highlight.Prefix = string(text[from:start])
+	highlight.Suffix = string(text[end:to])
+}
+
+// occurrences returns the character offsets at which quote starts in text.
+func occurrences(text, quote string) []int {
+	var offsets []int
+	if quote == "" {
+		return offsets
+	}
+
+	pos, runes := 0, 0
+	for {
+		i := strings.Index(text[pos:], quote)
+		if i < 0 {
+			return offsets
+		}
+		runes += utf8.RuneCountInString(text[pos : pos+i])
+		offsets = append(offsets, runes)
+
+		_, size := utf8.DecodeRuneInString(text[pos+i:])
+		pos += i + size
+		runes++
+	}
+}
+
+func commonPrefix(a, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func commonSuffix(a, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+func distance(a, b int) int {
+	if a < b {
+		return b - a
+	}
+	return a - b
+}
+
+func highlightColor(color string) (string, error) {
+	switch color = strings.ToLower(strings.TrimSpace(color)); color {
+	case "":
+		return models.HighlightColorYellow, nil
+	case models.HighlightColorYellow, models.HighlightColorGreen, models.HighlightColorBlue,
+		models.HighlightColorPink, models.HighlightColorPurple:
+		return color, nil
+	}
+	return "", bookmark.ErrInvalidHighlight
+}
+
+func highlightNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > models.MaxHighlightNote {
+		return "", bookmark.ErrInvalidHighlight
+	}
+	return note, nil
+}
+
+func highlightNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bookmark.ErrHighlightNotFound
+	}
+	return err
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/exporter"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const highlightText = "Héllo wörld. The quick brown fox jumps over the lazy dog."
+
+func newHighlightUseCase() (*HighlightUseCase, *mock.HighlightStorageMock, *mock.BookmarkStorageMock) {
+	highlightRepo := new(mock.HighlightStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	return NewHighlightUseCase(highlightRepo, bookmarkRepo, exporter.NewExporter()), highlightRepo, bookmarkRepo
+}
+
+func Test_CreateHighlight_Success(t *testing.T) {
+	uc, highlightRepo, bookmarkRepo := newHighlightUseCase()
+
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7}, nil)
+	bookmarkRepo.On("SQLGetContent", uint(1), uint(7)).Return(&models.BookmarkContent{Text: highlightText}, nil)
+	highlightRepo.On("SQLCreateHighlight", testifymock.Anything).Return(nil)
+
+	// Offsets count characters, not bytes.
+	highlight, err := uc.CreateHighlight(context.Background(), 1, 7, models.HighlightInput{Start: 17, End: 22, Note: " fast ", Color: "Green"})
+	assert.NoError(t, err)
+	assert.Equal(t, "quick", highlight.Quote)
+	assert.Equal(t, "Héllo wörld. The ", highlight.Prefix)
+	assert.Equal(t, " brown fox jumps over the lazy d", highlight.Suffix)
+	assert.Equal(t, "fast", highlight.Note)
+	assert.Equal(t, models.HighlightColorGreen, highlight.Color)
+
+	highlight, err = uc.CreateHighlight(context.Background(), 1, 7, models.HighlightInput{Start: 0, End: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "Héllo", highlight.Quote)
+	assert.Empty(t, highlight.Prefix)
+	assert.Equal(t, models.HighlightColorYellow, highlight.Color)
+}
+
+func Test_CreateHighlight_Failed(t *testing.T) {
+	uc, highlightRepo, bookmarkRepo := newHighlightUseCase()
+
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7}, nil)
+	bookmarkRepo.On("SQLGetContent", uint(1), uint(7)).Return(&models.BookmarkContent{Text: highlightText}, nil)
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(8)).Return(&models.Bookmark{ID: 8}, nil)
+	bookmarkRepo.On("SQLGetContent", uint(1), uint(8)).Return((*models.BookmarkContent)(nil), gorm.ErrRecordNotFound)
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(9)).Return((*models.Bookmark)(nil), gorm.ErrRecordNotFound)
+
+	for _, inp := range []models.HighlightInput{
+		{Start: 5, End: 5},
+		{Start: -1, End: 5},
+		{Start: 50, End: 100},
+		{Start: 12, End: 13},
+		{Start: 0, End: 5, Color: "orange"},
+		{Start: 0, End: 5, Note: strings.Repeat("a", models.MaxHighlightNote+1)},
+	} {
+		_, err := uc.CreateHighlight(context.Background(), 1, 7, inp)
+		assert.Equal(t, bookmark.ErrInvalidHighlight, err, "%+v", inp)
+	}
+
+	_, err := uc.CreateHighlight(context.Background(), 1, 8, models.HighlightInput{Start: 0, End: 5})
+	assert.Equal(t, bookmark.ErrContentNotFound, err)
+	_, err = uc.CreateHighlight(context.Background(), 1, 9, models.HighlightInput{Start: 0, End: 5})
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+
+	highlightRepo.AssertNotCalled(t, "SQLCreateHighlight", testifymock.Anything)
+}
+
+func Test_ListBookmarkHighlights_Reanchor(t *testing.T) {
+	uc, highlightRepo, bookmarkRepo := newHighlightUseCase()
+
+	// The article gained an intro and a second "the" before the old one.
+	text := "Updated: the cat. " + highlightText
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7}, nil)
+	bookmarkRepo.On("SQLGetContent", uint(1), uint(7)).Return(&models.BookmarkContent{Text: text}, nil)
+	highlightRepo.On("SQLListBookmarkHighlights", uint(1), uint(7)).Return([]models.Highlight{
+		{ID: 1, StartOffset: 18, EndOffset: 23, Quote: "Héllo"},
+		{ID: 2, StartOffset: 44, EndOffset: 47, Quote: "the", Prefix: "fox jumps over ", Suffix: " lazy dog."},
+		{ID: 3, StartOffset: 0, EndOffset: 7, Quote: "removed"},
+	}, nil)
+
+	highlights, err := uc.ListBookmarkHighlights(context.Background(), 1, 7)
+	assert.NoError(t, err)
+	assert.Len(t, highlights, 3)
+
+	assert.Equal(t, uint(3), highlights[0].ID)
+	assert.True(t, highlights[0].Orphaned)
+
+	assert.Equal(t, uint(1), highlights[1].ID)
+	assert.Equal(t, 18, highlights[1].StartOffset)
+	assert.False(t, highlights[1].Orphaned)
+
+	assert.Equal(t, uint(2), highlights[2].ID)
+	assert.Equal(t, 62, highlights[2].StartOffset)
+	assert.Equal(t, 65, highlights[2].EndOffset)
+	assert.Equal(t, "the", string([]rune(text)[62:65]))
+	assert.Equal(t, " lazy dog.", highlights[2].Suffix)
+
+	// Reading the highlights writes nothing.
+	highlightRepo.AssertNotCalled(t, "SQLUpdateHighlight", testifymock.Anything)
+	highlightRepo.AssertNotCalled(t, "SQLUpdateHighlightAnchor", testifymock.Anything)
+}
+
+func Test_UpdateHighlight(t *testing.T) {
+	uc, highlightRepo, _ := newHighlightUseCase()
+
+	highlightRepo.On("SQLGetHighlight", uint(1), uint(3)).Return(&models.Highlight{ID: 3, UserID: 1, Note: "old", Color: "yellow"}, nil)
+	highlightRepo.On("SQLGetHighlight", uint(1), uint(4)).Return((*models.Highlight)(nil), gorm.ErrRecordNotFound)
+	highlightRepo.On("SQLUpdateHighlight", testifymock.Anything).Return(nil)
+
+	highlight, err := uc.UpdateHighlight(context.Background(), 1, 3, models.HighlightUpdateInput{Color: strPtr("blue")})
+	assert.NoError(t, err)
+	assert.Equal(t, "old", highlight.Note)
+	assert.Equal(t, models.HighlightColorBlue, highlight.Color)
+
+	_, err = uc.UpdateHighlight(context.Background(), 1, 3, models.HighlightUpdateInput{Color: strPtr("black")})
+	assert.Equal(t, bookmark.ErrInvalidHighlight, err)
+	_, err = uc.UpdateHighlight(context.Background(), 1, 4, models.HighlightUpdateInput{Note: strPtr("new")})
+	assert.Equal(t, bookmark.ErrHighlightNotFound, err)
+}
+
+func Test_ExportHighlights(t *testing.T) {
+	uc, highlightRepo, bookmarkRepo := newHighlightUseCase()
+
+	highlightRepo.On("SQLListHighlights", uint(1), 0, 0).Return([]models.Highlight{
+		{BookmarkID: 8, StartOffset: 20, Quote: "second"},
+		{BookmarkID: 7, StartOffset: 0, Quote: "other"},
+		{BookmarkID: 8, StartOffset: 5, Quote: "first", Note: "a *note*"},
+	}, nil)
+	bookmarkRepo.On("SQLGetBookmarksByIDs", uint(1), []uint{8, 7}).Return([]models.Bookmark{
+		{ID: 7, URL: "https://example.com/b", Title: "B"},
+		{ID: 8, URL: "https://example.com/a", Title: "A"},
+	}, nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, uc.ExportHighlights(context.Background(), 1, 0, &buf))
+	assert.Equal(t, "# Highlights\n"+
+		"\n## [A](https://example.com/a)\n"+
+		"\n> first\n\na *note*\n"+
+		"\n> second\n"+
+		"\n## [B](https://example.com/b)\n"+
+		"\n> other\n", buf.String())
+}
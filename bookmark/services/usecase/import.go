@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/urlnorm"
+	"gorm.io/gorm"
+)
+
+const (
+	// Progress is saved every importProgressEvery rows.
+	importProgressEvery = 100
+	// Only the first maxImportErrors failed rows of a job are reported.
+	maxImportErrors = 1000
+
+	importInterrupted = "import terhenti karena server dimatikan"
+)
+
+// ImportUseCase imports bookmarks from export files of other tools. Files
+// are parsed up front, then imported in the background while the job
+// records its progress.
+type ImportUseCase struct {
+	importRepo   services.ImportRepositorySQL
+	bookmarkRepo services.BookmarkRepositorySQL
+	tagRepo      services.TagRepositorySQL
+	parser       services.ImportParser
+	index        services.SearchIndex
+	metadata     services.MetadataUseCase
+	log          *slog.Logger
+
+	mu      sync.Mutex
+	running map[uint]bool // users with a job in progress
+	stop    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+func NewImportUseCase(
+	importRepo services.ImportRepositorySQL,
+	bookmarkRepo services.BookmarkRepositorySQL,
+	tagRepo services.TagRepositorySQL,
+	parser services.ImportParser,
+	index services.SearchIndex,
+	metadata services.MetadataUseCase,
+	log *slog.Logger) *ImportUseCase {
+	return &ImportUseCase{
+		importRepo:   importRepo,
+		bookmarkRepo: bookmarkRepo,
+		tagRepo:      tagRepo,
+		parser:       parser,
+		index:        index,
+		metadata:     metadata,
+		log:          log,
+		running:      map[uint]bool{},
+		stop:         make(chan struct{}),
+	}
+}
+
+func (u *ImportUseCase) StartImport(ctx context.Context, userID uint, inp models.ImportInput, data []byte) (*models.ImportJob, error) {
+	inp.Format = strings.ToLower(strings.TrimSpace(inp.Format))
+	inp.FolderMode = strings.ToLower(strings.TrimSpace(inp.FolderMode))
+
+	switch inp.FolderMode {
+	case "":
+		inp.FolderMode = models.FolderModeTags
+	case models.FolderModeTags, models.FolderModePath, models.FolderModeNone:
+	default:
+		return nil, bookmark.ErrBadRequest
+	}
+
+	format, items, err := u.parser.Parse(inp.Format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.running[userID] {
+		return nil, bookmark.ErrImportRunning
+	}
+
+	job := &models.ImportJob{
+		UserID:     userID,
+		Format:     format,
+		FolderMode: inp.FolderMode,
+		Status:     models.ImportStatusRunning,
+		Total:      len(items),
+	}
+	if err := u.importRepo.SQLCreateImportJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	u.running[userID] = true
+	u.wg.Add(1)
+
+	// The caller gets job; the worker updates its own copy. The job outlives
+	// the request that started it.
+	progress := *job
+	go func() {
+		defer u.wg.Done()
+		u.run(context.Background(), &progress, items)
+
+		u.mu.Lock()
+		delete(u.running, userID)
+		u.mu.Unlock()
+	}()
+
+	return job, nil
+}
+
+func (u *ImportUseCase) GetImport(ctx context.Context, userID, id uint) (*models.ImportJob, error) {
+	job, err := u.importRepo.SQLGetImportJob(ctx, userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, bookmark.ErrImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (u *ImportUseCase) ListImports(ctx context.Context, userID uint) ([]models.ImportJob, error) {
+	return u.importRepo.SQLListImportJobs(ctx, userID)
+}
+
+// RecoverInterrupted fails the jobs a previous run of the server left
+// behind. Call it once at startup, before any import is started.
+func (u *ImportUseCase) RecoverInterrupted(ctx context.Context) error {
+	return u.importRepo.SQLFailUnfinishedImports(ctx, importInterrupted)
+}
+
+// Stop asks running jobs to stop after their current row and waits for them.
+func (u *ImportUseCase) Stop() {
+	u.once.Do(func() {
+		close(u.stop)
+	})
+	u.wg.Wait()
+}
+
+func (u *ImportUseCase) run(ctx context.Context, job *models.ImportJob, items []models.ImportItem) {
+	urls, err := u.bookmarkRepo.SQLListNormalizedURLs(ctx, job.UserID)
+	if err != nil {
+		u.finish(ctx, job, nil, err)
+		return
+	}
+
+	seen := make(map[string]bool, len(urls)+len(items))
+	for _, url := range urls {
+		seen[url] = true
+	}
+
+	var errs []models.ImportError
+	for _, item := range items {
+		select {
+		case <-u.stop:
+			u.finish(ctx, job, errs, errors.New(importInterrupted))
+			return
+		default:
+		}
+
+		duplicate, err := u.importItem(ctx, job, item, seen)
+		switch {
+		case err != nil:
+			job.Failed++
+			if job.Failed <= maxImportErrors {
+				errs = append(errs, models.ImportError{Row: item.Row, URL: item.URL, Error: err.Error()})
+			}
+		case duplicate:
+			job.Duplicates++
+		default:
+			job.Imported++
+		}
+		job.Processed++
+
+		if job.Processed%importProgressEvery == 0 && job.Processed < job.Total {
+			if err := u.importRepo.SQLUpdateImportJob(ctx, job, errs); err != nil {
+				u.log.ErrorContext(ctx, "import: failed to save progress", "job", job.ID, "err", err)
+				continue
+			}
+			errs = nil
+		}
+	}
+
+	u.finish(ctx, job, errs, nil)
+}
+
+func (u *ImportUseCase) finish(ctx context.Context, job *models.ImportJob, errs []models.ImportError, err error) {
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = models.ImportStatusDone
+	if err != nil {
+		job.Status = models.ImportStatusFailed
+		job.Error = err.Error()
+	}
+
+	if err := u.importRepo.SQLUpdateImportJob(ctx, job, errs); err != nil {
+		u.log.ErrorContext(ctx, "import: failed to save result", "job", job.ID, "err", err)
+	}
+}
+
+// importItem saves one bookmark of the file, unless its normalized URL is
+// already in seen. Bookmarks without a title get one from their page later on.
+func (u *ImportUseCase) importItem(ctx context.Context, job *models.ImportJob, item models.ImportItem, seen map[string]bool) (bool, error) {
+	inp := models.BookmarkInput{URL: item.URL, Title: item.Title, Notes: strings.TrimSpace(item.Notes)}
+	if err := validateInput(&inp); err != nil {
+		return false, err
+	}
+
+	normalized, err := urlnorm.Normalize(inp.URL)
+	if err != nil {
+		return false, err
+	}
+	if seen[normalized] {
+		return true, nil
+	}
+
+	names := append(append([]string{}, item.Tags...), folderTags(item.Folders, job.FolderMode)...)
+	tags, err := normalizeTagNames(names)
+	if err != nil {
+		return false, err
+	}
+
+	bm := &models.Bookmark{UserID: job.UserID, URL: inp.URL, Title: inp.Title, Notes: inp.Notes}
+	if item.CreatedAt != nil {
+		bm.CreatedAt = *item.CreatedAt
+	}
+	_, err = createBookmark(ctx, u.bookmarkRepo, bm)
+	if err == bookmark.ErrBookmarkDuplicate {
+		seen[normalized] = true
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	seen[normalized] = true
+
+	if len(tags) > 0 {
+		bm.Tags, err = u.tagRepo.SQLAddTags(ctx, job.UserID, bm.ID, tags)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	indexBookmark(ctx, u.log, u.index, bm, "")
+	if bm.Title == "" {
+		u.metadata.Enqueue(ctx, job.UserID, bm.ID)
+	}
+
+	return false, nil
+}
+
+func folderTags(folders []string, mode string) []string {
+	var names []string
+	for _, folder := range folders {
+		// Commas separate tags, so they cannot be part of one.
+		if folder = strings.TrimSpace(strings.Replace(folder, ",", " ", -1)); folder != "" {
+			names = append(names, folder)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	switch mode {
+	case models.FolderModeTags:
+		return names
+	case models.FolderModePath:
+		return []string{strings.Join(names, "/")}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/importer"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/urlnorm"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type importResult struct {
+	job  models.ImportJob
+	errs []models.ImportError
+}
+
+func newImportUseCase() (*ImportUseCase, *mock.ImportStorageMock, *mock.BookmarkStorageMock, *mock.TagStorageMock) {
+	importRepo := new(mock.ImportStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	tagRepo := new(mock.TagStorageMock)
+	uc := NewImportUseCase(importRepo, bookmarkRepo, tagRepo, importer.NewParser(), search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	importRepo.On("SQLCreateImportJob", testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		args.Get(0).(*models.ImportJob).ID = 3
+	})
+
+	return uc, importRepo, bookmarkRepo, tagRepo
+}
+
+// finished reports the job each time its final state is saved.
+func finished(importRepo *mock.ImportStorageMock) <-chan importResult {
+	results := make(chan importResult, 1)
+	importRepo.On("SQLUpdateImportJob", testifymock.Anything, testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		job := args.Get(0).(*models.ImportJob)
+		if job.Status != models.ImportStatusRunning {
+			results <- importResult{job: *job, errs: args.Get(1).([]models.ImportError)}
+		}
+	})
+	return results
+}
+
+func wait(t *testing.T, results <-chan importResult) importResult {
+	select {
+	case res := <-results:
+		return res
+	case <-time.After(2 * time.Second):
+		t.Fatal("import did not finish")
+	}
+	return importResult{}
+}
+
+func Test_StartImport_Success(t *testing.T) {
+	uc, importRepo, bookmarkRepo, tagRepo := newImportUseCase()
+	results := finished(importRepo)
+
+	file := "url,title,tags,folder,created\n" +
+		"https://go.dev/,Go,Golang,Dev / Languages,1600000000\n" +
+		"https://existing.com/,Existing,,,\n" +
+		"http://GO.dev?utm_source=x,Go again,,,\n" +
+		"ftp://example.com/,FTP,,,\n" +
+		"https://example.com/,,a|b,,\n"
+
+	bookmarkRepo.On("SQLListNormalizedURLs", uint(1)).Return([]string{"existing.com"}, nil)
+	bookmarkRepo.On("SQLGetBookmarkByURLHash", uint(1), testifymock.Anything).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	var created []*models.Bookmark
+	bookmarkRepo.On("SQLCreateBookmark", testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		bm := args.Get(0).(*models.Bookmark)
+		bm.ID = uint(10 + len(created))
+		created = append(created, bm)
+	})
+	tagRepo.On("SQLAddTags", uint(1), uint(10), []string{"golang", "dev", "languages"}).Return([]models.Tag{{ID: 1, Name: "golang"}}, nil)
+	tagRepo.On("SQLAddTags", uint(1), uint(11), []string{"a", "b"}).Return([]models.Tag{}, nil)
+
+	job, err := uc.StartImport(context.Background(), 1, models.ImportInput{}, []byte(file))
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), job.ID)
+	assert.Equal(t, models.ImportFormatCSV, job.Format)
+	assert.Equal(t, models.FolderModeTags, job.FolderMode)
+	assert.Equal(t, models.ImportStatusRunning, job.Status)
+	assert.Equal(t, 5, job.Total)
+
+	res := wait(t, results)
+	assert.Equal(t, models.ImportStatusDone, res.job.Status)
+	assert.Equal(t, 5, res.job.Processed)
+	assert.Equal(t, 2, res.job.Imported)
+	assert.Equal(t, 2, res.job.Duplicates)
+	assert.Equal(t, 1, res.job.Failed)
+	assert.NotNil(t, res.job.FinishedAt)
+	assert.Equal(t, []models.ImportError{{Row: 5, URL: "ftp://example.com/", Error: bookmark.ErrInvalidURL.Error()}}, res.errs)
+
+	require.Len(t, created, 2)
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), created[0].CreatedAt)
+	assert.Equal(t, "Go", created[0].Title)
+	tagRepo.AssertExpectations(t)
+}
+
+func Test_StartImport_DuplicateOfCanonicalLink(t *testing.T) {
+	uc, importRepo, bookmarkRepo, _ := newImportUseCase()
+	results := finished(importRepo)
+
+	bookmarkRepo.On("SQLListNormalizedURLs", uint(1)).Return([]string{"go.dev/doc?lang=en"}, nil)
+	bookmarkRepo.On("SQLGetBookmarkByURLHash", uint(1), urlnorm.Hash("go.dev/doc")).Return(&models.Bookmark{ID: 4}, nil)
+
+	_, err := uc.StartImport(context.Background(), 1, models.ImportInput{}, []byte("url\nhttps://go.dev/doc/\n"))
+	require.NoError(t, err)
+
+	res := wait(t, results)
+	assert.Equal(t, 1, res.job.Duplicates)
+	assert.Equal(t, 0, res.job.Imported)
+	bookmarkRepo.AssertNotCalled(t, "SQLCreateBookmark", testifymock.Anything)
+}
+
+func Test_StartImport_FolderPath(t *testing.T) {
+	uc, importRepo, bookmarkRepo, tagRepo := newImportUseCase()
+	results := finished(importRepo)
+
+	bookmarkRepo.On("SQLListNormalizedURLs", uint(1)).Return([]string{}, nil)
+	bookmarkRepo.On("SQLGetBookmarkByURLHash", uint(1), testifymock.Anything).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	bookmarkRepo.On("SQLCreateBookmark", testifymock.Anything).Return(nil).Run(func(args testifymock.Arguments) {
+		args.Get(0).(*models.Bookmark).ID = 10
+	})
+	tagRepo.On("SQLAddTags", uint(1), uint(10), []string{"dev/go/go tools"}).Return([]models.Tag{}, nil)
+
+	_, err := uc.StartImport(context.Background(), 1, models.ImportInput{FolderMode: "path"}, []byte("url,title,folder\nhttps://go.dev/,Go,\"Dev/Go/Go, tools\"\n"))
+	require.NoError(t, err)
+
+	res := wait(t, results)
+	assert.Equal(t, 1, res.job.Imported)
+	tagRepo.AssertExpectations(t)
+}
+
+func Test_StartImport_Failed_BadInput(t *testing.T) {
+	uc, importRepo, _, _ := newImportUseCase()
+
+	_, err := uc.StartImport(context.Background(), 1, models.ImportInput{Format: "xml"}, []byte("<x/>"))
+	assert.Equal(t, bookmark.ErrImportFormat, err)
+
+	_, err = uc.StartImport(context.Background(), 1, models.ImportInput{FolderMode: "flat"}, []byte("url\nhttps://go.dev/\n"))
+	assert.Equal(t, bookmark.ErrBadRequest, err)
+
+	importRepo.AssertNotCalled(t, "SQLCreateImportJob", testifymock.Anything)
+}
+
+func Test_StartImport_OneJobPerUser(t *testing.T) {
+	uc, importRepo, bookmarkRepo, _ := newImportUseCase()
+	results := finished(importRepo)
+
+	release := make(chan time.Time)
+	bookmarkRepo.On("SQLListNormalizedURLs", uint(1)).Return([]string{"go.dev"}, nil).WaitUntil(release)
+
+	_, err := uc.StartImport(context.Background(), 1, models.ImportInput{}, []byte("url\nhttps://go.dev/\n"))
+	require.NoError(t, err)
+
+	_, err = uc.StartImport(context.Background(), 1, models.ImportInput{}, []byte("url\nhttps://go.dev/\n"))
+	assert.Equal(t, bookmark.ErrImportRunning, err)
+
+	close(release)
+	res := wait(t, results)
+	assert.Equal(t, 1, res.job.Duplicates)
+}
+
+func Test_ImportStop_FailsRunningJob(t *testing.T) {
+	uc, importRepo, bookmarkRepo, _ := newImportUseCase()
+	results := finished(importRepo)
+
+	release := make(chan time.Time)
+	bookmarkRepo.On("SQLListNormalizedURLs", uint(1)).Return([]string{}, nil).WaitUntil(release)
+
+	_, err := uc.StartImport(context.Background(), 1, models.ImportInput{}, []byte("url\nhttps://go.dev/\n"))
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	uc.Stop()
+
+	res := wait(t, results)
+	assert.Equal(t, models.ImportStatusFailed, res.job.Status)
+	assert.Equal(t, importInterrupted, res.job.Error)
+	assert.Equal(t, 0, res.job.Processed)
+}
+
+func Test_GetImport_NotFound(t *testing.T) {
+	uc, importRepo, _, _ := newImportUseCase()
+
+	importRepo.On("SQLGetImportJob", uint(2), uint(3)).Return((*models.ImportJob)(nil), gorm.ErrRecordNotFound)
+
+	_, err := uc.GetImport(context.Background(), 2, 3)
+	assert.Equal(t, bookmark.ErrImportNotFound, err)
+}
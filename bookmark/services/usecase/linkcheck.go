@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+type LinkCheckConfig struct {
+	// Interval is how often a round of checks starts, MaxAge how old a
+	// check may get before the link is due again.
+	Interval time.Duration
+	MaxAge   time.Duration
+	// A link that got no answer or a server error is checked again after
+	// RetryAfter, and only counts as broken after FailureThreshold such
+	// checks in a row.
+	RetryAfter       time.Duration
+	FailureThreshold int
+	// BatchSize bounds the links checked in one round, Workers how many of
+	// them are checked at once.
+	BatchSize int
+	Workers   int
+	// HostDelay spaces out requests to the same host.
+	HostDelay time.Duration
+	Timeout   time.Duration
+}
+
+// LinkCheckUseCase periodically checks that saved URLs still answer and
+// flags the ones that are gone or moved.
+type LinkCheckUseCase struct {
+	bookmarkRepo services.BookmarkRepositorySQL
+	checker      services.LinkChecker
+	config       LinkCheckConfig
+	log          *slog.Logger
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+func NewLinkCheckUseCase(bookmarkRepo services.BookmarkRepositorySQL, checker services.LinkChecker, config LinkCheckConfig, log *slog.Logger) *LinkCheckUseCase {
+	return &LinkCheckUseCase{
+		bookmarkRepo: bookmarkRepo,
+		checker:      checker,
+		config:       config,
+		log:          log,
+	}
+}
+
+// Start runs a round right away and then one every Interval, until Stop.
+func (l *LinkCheckUseCase) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.stop = cancel
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+
+		ticker := time.NewTicker(l.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := l.CheckLinks(ctx); err != nil {
+				l.log.ErrorContext(ctx, "linkcheck: failed to list links", "err", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop abandons the round in progress and waits for it to wind down.
+func (l *LinkCheckUseCase) Stop() {
+	if l.stop == nil {
+		return
+	}
+	l.stop()
+	l.wg.Wait()
+}
+
+// CheckLinks checks one batch of due links and returns how many of them got
+// a result stored.
+func (l *LinkCheckUseCase) CheckLinks(ctx context.Context) (int, error) {
+	now := time.Now()
+	bookmarks, err := l.bookmarkRepo.SQLListLinksToCheck(ctx, now.Add(-l.config.MaxAge), now.Add(-l.config.RetryAfter), l.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	gate := newHostGate(l.config.HostDelay)
+	jobs := make(chan *models.Bookmark)
+
+	var (
+		mu      sync.Mutex
+		checked int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < l.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for bm := range jobs {
+				if err := gate.wait(ctx, linkHost(bm.URL)); err != nil {
+					continue
+				}
+				if l.checkLink(ctx, bm) {
+					mu.Lock()
+					checked++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range bookmarks {
+		select {
+		case jobs <- &bookmarks[i]:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return checked, nil
+}
+
+func (l *LinkCheckUseCase) checkLink(ctx context.Context, bm *models.Bookmark) bool {
+	checkCtx, cancel := context.WithTimeout(ctx, l.config.Timeout)
+	defer cancel()
+
+	result := l.checker.Check(checkCtx, bm.URL)
+	// A check cut short by Stop says nothing about the link.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	now := time.Now()
+	status, failures := linkStatus(bm, result, l.config.FailureThreshold)
+	update := &models.Bookmark{
+		ID:            bm.ID,
+		UserID:        bm.UserID,
+		URL:           bm.URL,
+		LinkStatus:    status,
+		LinkCode:      result.StatusCode,
+		RedirectURL:   result.RedirectURL,
+		LinkError:     result.Error,
+		LinkFailures:  failures,
+		LinkCheckedAt: &now,
+	}
+
+	if err := l.bookmarkRepo.SQLUpdateLinkStatus(ctx, update); err != nil {
+		l.log.ErrorContext(ctx, "linkcheck: failed to save link status", "bookmark", bm.ID, "err", err)
+		return false
+	}
+	return true
+}
+
+// linkStatus turns a check of bm into a status and the count of failed
+// checks in a row. No answer or a server error may be a hiccup of the
+// network or the site, so the link only turns broken after threshold of
+// them; 404 and 410 are a verdict right away. Answers such as 403 or 429
+// are how many sites turn robots away rather than a verdict on the link,
+// so they keep the previous status.
+func linkStatus(bm *models.Bookmark, result *models.LinkCheck, threshold int) (string, int) {
+	code := result.StatusCode
+	switch {
+	case result.Error != "" || code >= 500:
+		failures := bm.LinkFailures + 1
+		if failures >= threshold {
+			return models.LinkStatusBroken, failures
+		}
+		return bm.LinkStatus, failures
+	case code >= 200 && code <= 299:
+		if result.Permanent {
+			return models.LinkStatusMoved, 0
+		}
+		return models.LinkStatusOK, 0
+	case code == 404 || code == 410:
+		return models.LinkStatusBroken, 0
+	}
+	return bm.LinkStatus, bm.LinkFailures
+}
+
+func validLinkStatus(status string) bool {
+	switch status {
+	case models.LinkStatusOK, models.LinkStatusMoved, models.LinkStatusBroken:
+		return true
+	}
+	return false
+}
+
+func linkHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// hostGate hands out turns per host, at least delay apart.
+type hostGate struct {
+	delay time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newHostGate(delay time.Duration) *hostGate {
+	return &hostGate{delay: delay, next: make(map[string]time.Time)}
+}
+
+func (g *hostGate) wait(ctx context.Context, host string) error {
+	g.mu.Lock()
+	at := time.Now()
+	if next := g.next[host]; next.After(at) {
+		at = next
+	}
+	g.next[host] = at.Add(g.delay)
+	g.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// linkCheckerStub answers from a map and remembers when each URL was asked.
+type linkCheckerStub struct {
+	results map[string]*models.LinkCheck
+
+	mu    sync.Mutex
+	calls map[string]time.Time
+}
+
+func (s *linkCheckerStub) Check(ctx context.Context, rawURL string) *models.LinkCheck {
+	s.mu.Lock()
+	if s.calls == nil {
+		s.calls = make(map[string]time.Time)
+	}
+	s.calls[rawURL] = time.Now()
+	s.mu.Unlock()
+
+	return s.results[rawURL]
+}
+
+func testLinkCheckConfig() LinkCheckConfig {
+	return LinkCheckConfig{
+		Interval:         time.Hour,
+		MaxAge:           7 * 24 * time.Hour,
+		RetryAfter:       6 * time.Hour,
+		FailureThreshold: 3,
+		BatchSize:        500,
+		Workers:          8,
+		Timeout:          15 * time.Second,
+	}
+}
+
+func Test_CheckLinks_Success(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	checker := &linkCheckerStub{results: map[string]*models.LinkCheck{
+		"https://a.example/ok":    {StatusCode: 200},
+		"https://b.example/gone":  {StatusCode: 404},
+		"https://c.example/moved": {StatusCode: 200, RedirectURL: "https://c.example/new", Permanent: true},
+		"https://d.example/down":  {Error: "gagal mengambil halaman: timeout"},
+		"https://e.example/down":  {StatusCode: 503},
+	}}
+	uc := NewLinkCheckUseCase(repo, checker, testLinkCheckConfig(), logging.Discard())
+
+	repo.On("SQLListLinksToCheck", testifymock.Anything, testifymock.Anything, 500).Return([]models.Bookmark{
+		{ID: 1, UserID: 1, URL: "https://a.example/ok", LinkStatus: models.LinkStatusBroken},
+		{ID: 2, UserID: 1, URL: "https://b.example/gone"},
+		{ID: 3, UserID: 2, URL: "https://c.example/moved"},
+		{ID: 4, UserID: 2, URL: "https://d.example/down", LinkStatus: models.LinkStatusOK},
+		{ID: 5, UserID: 2, URL: "https://e.example/down", LinkStatus: models.LinkStatusOK, LinkFailures: 2},
+	}, nil)
+	expect := map[uint]string{1: models.LinkStatusOK, 2: models.LinkStatusBroken, 3: models.LinkStatusMoved, 4: models.LinkStatusOK, 5: models.LinkStatusBroken}
+	failures := map[uint]int{4: 1, 5: 3}
+	repo.On("SQLUpdateLinkStatus", testifymock.MatchedBy(func(u *models.Bookmark) bool {
+		return u.LinkCheckedAt != nil && u.LinkStatus == expect[u.ID] && u.LinkFailures == failures[u.ID] &&
+			(u.ID != 3 || (u.LinkCode == 200 && u.RedirectURL == "https://c.example/new")) &&
+			(u.ID != 4 || u.LinkError == "gagal mengambil halaman: timeout")
+	})).Return(nil)
+
+	checked, err := uc.CheckLinks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, checked)
+	repo.AssertNumberOfCalls(t, "SQLUpdateLinkStatus", 5)
+}
+
+func Test_CheckLinks_SkipsDeletedBookmarks(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	checker := &linkCheckerStub{results: map[string]*models.LinkCheck{"https://a.example/": {StatusCode: 200}}}
+	uc := NewLinkCheckUseCase(repo, checker, testLinkCheckConfig(), logging.Discard())
+
+	repo.On("SQLListLinksToCheck", testifymock.Anything, testifymock.Anything, 500).Return([]models.Bookmark{{ID: 1, UserID: 1, URL: "https://a.example/"}}, nil)
+	repo.On("SQLUpdateLinkStatus", testifymock.Anything).Return(gorm.ErrRecordNotFound)
+
+	checked, err := uc.CheckLinks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, checked)
+}
+
+func Test_CheckLinks_SpacesOutRequestsToOneHost(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	checker := &linkCheckerStub{results: map[string]*models.LinkCheck{
+		"https://a.example/1": {StatusCode: 200},
+		"https://A.example/2": {StatusCode: 200},
+		"https://b.example/1": {StatusCode: 200},
+	}}
+	config := testLinkCheckConfig()
+	config.HostDelay = 100 * time.Millisecond
+	uc := NewLinkCheckUseCase(repo, checker, config, logging.Discard())
+
+	repo.On("SQLListLinksToCheck", testifymock.Anything, testifymock.Anything, 500).Return([]models.Bookmark{
+		{ID: 1, UserID: 1, URL: "https://a.example/1"},
+		{ID: 2, UserID: 1, URL: "https://A.example/2"},
+		{ID: 3, UserID: 1, URL: "https://b.example/1"},
+	}, nil)
+	repo.On("SQLUpdateLinkStatus", testifymock.Anything).Return(nil)
+
+	_, err := uc.CheckLinks(context.Background())
+	assert.NoError(t, err)
+
+	sameHost := checker.calls["https://A.example/2"].Sub(checker.calls["https://a.example/1"])
+	if sameHost < 0 {
+		sameHost = -sameHost
+	}
+	assert.True(t, sameHost >= 90*time.Millisecond, sameHost)
+	otherHost := checker.calls["https://b.example/1"].Sub(checker.calls["https://a.example/1"])
+	if otherHost < 0 {
+		otherHost = -otherHost
+	}
+	assert.True(t, otherHost < 90*time.Millisecond, otherHost)
+}
+
+func Test_CheckLinks_Stopped(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewLinkCheckUseCase(repo, &linkCheckerStub{}, testLinkCheckConfig(), logging.Discard())
+
+	repo.On("SQLListLinksToCheck", testifymock.Anything, testifymock.Anything, 500).Return([]models.Bookmark{{ID: 1, UserID: 1, URL: "https://a.example/"}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checked, err := uc.CheckLinks(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, checked)
+	repo.AssertNotCalled(t, "SQLUpdateLinkStatus", testifymock.Anything)
+}
+
+func Test_LinkStatus(t *testing.T) {
+	cases := []struct {
+		previous models.Bookmark
+		check    models.LinkCheck
+		status   string
+		failures int
+	}{
+		{models.Bookmark{}, models.LinkCheck{StatusCode: 204}, models.LinkStatusOK, 0},
+		{models.Bookmark{}, models.LinkCheck{StatusCode: 200, RedirectURL: "https://example.com/login"}, models.LinkStatusOK, 0},
+		{models.Bookmark{}, models.LinkCheck{StatusCode: 200, RedirectURL: "https://example.com/new", Permanent: true}, models.LinkStatusMoved, 0},
+		{models.Bookmark{LinkStatus: models.LinkStatusOK, LinkFailures: 2}, models.LinkCheck{StatusCode: 200}, models.LinkStatusOK, 0},
+		{models.Bookmark{LinkStatus: models.LinkStatusOK}, models.LinkCheck{StatusCode: 410}, models.LinkStatusBroken, 0},
+		{models.Bookmark{LinkStatus: models.LinkStatusOK}, models.LinkCheck{StatusCode: 502}, models.LinkStatusOK, 1},
+		{models.Bookmark{LinkStatus: models.LinkStatusOK, LinkFailures: 1}, models.LinkCheck{Error: "gagal mengambil halaman"}, models.LinkStatusOK, 2},
+		{models.Bookmark{LinkStatus: models.LinkStatusOK, LinkFailures: 2}, models.LinkCheck{Error: "gagal mengambil halaman"}, models.LinkStatusBroken, 3},
+		{models.Bookmark{LinkStatus: models.LinkStatusBroken, LinkFailures: 3}, models.LinkCheck{StatusCode: 503}, models.LinkStatusBroken, 4},
+		{models.Bookmark{LinkStatus: models.LinkStatusOK, LinkFailures: 1}, models.LinkCheck{StatusCode: 403}, models.LinkStatusOK, 1},
+		{models.Bookmark{}, models.LinkCheck{StatusCode: 429}, "", 0},
+	}
+
+	for _, c := range cases {
+		status, failures := linkStatus(&c.previous, &c.check, 3)
+		assert.Equal(t, c.status, status, c.check)
+		assert.Equal(t, c.failures, failures, c.check)
+	}
+}
+
+func Test_ListBookmarks_InvalidLinkStatus(t *testing.T) {
+	uc := NewBookmarkUseCase(new(mock.BookmarkStorageMock), nil, newMetadataStub(), logging.Discard())
+
+	_, err := uc.ListBookmarks(context.Background(), 1, models.ListInput{Link: "dead"})
+	assert.Equal(t, bookmark.ErrInvalidLinkStatus, err)
+}
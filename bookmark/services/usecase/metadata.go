@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+const (
+	metadataQueueSize = 256
+	metadataTimeout   = 15 * time.Second
+)
+
+type metadataJob struct {
+	userID     uint
+	bookmarkID uint
+}
+
+// MetadataUseCase fills in title, description and images of saved bookmarks
+// in the background. Jobs are queued with Enqueue and processed by the
+// workers started with Start.
+type MetadataUseCase struct {
+	bookmarkRepo  services.BookmarkRepositorySQL
+	highlightRepo services.HighlightRepositorySQL
+	fetcher       services.PageFetcher
+	index         services.SearchIndex
+	log           *slog.Logger
+
+	mu      sync.RWMutex
+	stopped bool
+	jobs    chan metadataJob
+	wg      sync.WaitGroup
+}
+
+func NewMetadataUseCase(bookmarkRepo services.BookmarkRepositorySQL, highlightRepo services.HighlightRepositorySQL, fetcher services.PageFetcher, index services.SearchIndex, log *slog.Logger) *MetadataUseCase {
+	return &MetadataUseCase{
+		bookmarkRepo:  bookmarkRepo,
+		highlightRepo: highlightRepo,
+		fetcher:       fetcher,
+		index:         index,
+		log:           log,
+		jobs:          make(chan metadataJob, metadataQueueSize),
+	}
+}
+
+func (m *MetadataUseCase) Start(workers int) {
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			ctx := context.Background()
+			for job := range m.jobs {
+				if _, err := m.RefreshMetadata(ctx, job.userID, job.bookmarkID); err != nil {
+					m.log.ErrorContext(ctx, "metadata: failed to refresh bookmark", "bookmark", job.bookmarkID, "err", err)
+				}
+			}
+		}()
+	}
+}
+
+// Stop stops accepting jobs and waits for the queued ones to finish.
+func (m *MetadataUseCase) Stop() {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return
+	}
+	m.stopped = true
+	close(m.jobs)
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// Enqueue never blocks the caller; when the queue is full the job is dropped
+// and the bookmark can still be refreshed on demand.
+func (m *MetadataUseCase) Enqueue(ctx context.Context, userID, bookmarkID uint) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.stopped {
+		return
+	}
+
+	select {
+	case m.jobs <- metadataJob{userID: userID, bookmarkID: bookmarkID}:
+	default:
+		m.log.WarnContext(ctx, "metadata: queue full, skipping bookmark", "bookmark", bookmarkID)
+	}
+}
+
+// RefreshMetadata fetches the page of a bookmark and stores what it found,
+// including a reader copy of the article, and moves the highlights along
+// with the article. A failed fetch is recorded on the bookmark rather than
+// returned.
+func (m *MetadataUseCase) RefreshMetadata(ctx context.Context, userID, bookmarkID uint) (*models.Bookmark, error) {
+	bm, err := m.bookmarkRepo.SQLGetBookmark(ctx, userID, bookmarkID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	now := time.Now()
+	// Start from what an earlier fetch found, so a failed one keeps it.
+	update := &models.Bookmark{
+		ID:            bm.ID,
+		UserID:        bm.UserID,
+		URL:           bm.URL,
+		Description:   bm.Description,
+		CanonicalURL:  bm.CanonicalURL,
+		CanonicalHash: bm.CanonicalHash,
+		ImageURL:      bm.ImageURL,
+		FaviconURL:    bm.FaviconURL,
+		SiteName:      bm.SiteName,
+		WordCount:     bm.WordCount,
+		ReadingTime:   bm.ReadingTime,
+		FetchedAt:     &now,
+	}
+	var content *models.BookmarkContent
+
+	page, err := m.fetcher.Fetch(fetchCtx, bm.URL)
+	if err != nil {
+		update.FetchError = err.Error()
+	} else {
+		meta := page.Metadata
+		update.Title = meta.Title
+		update.Description = meta.Description
+		update.CanonicalURL = meta.CanonicalURL
+		update.CanonicalHash = canonicalHash(bm.NormalizedURL, meta.CanonicalURL)
+		update.ImageURL = meta.ImageURL
+		update.FaviconURL = meta.FaviconURL
+		update.SiteName = meta.SiteName
+
+		if article := page.Article; article != nil {
+			update.WordCount = article.WordCount
+			update.ReadingTime = article.ReadingTime
+			content = &models.BookmarkContent{
+				BookmarkID:  bm.ID,
+				UserID:      bm.UserID,
+				HTML:        article.HTML,
+				Text:        article.Text,
+				WordCount:   article.WordCount,
+				ReadingTime: article.ReadingTime,
+				ExtractedAt: now,
+			}
+		}
+	}
+
+	if err := m.bookmarkRepo.SQLUpdateMetadata(ctx, update, content); err != nil {
+		return nil, notFound(err)
+	}
+
+	bm, err = m.bookmarkRepo.SQLGetBookmark(ctx, userID, bookmarkID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if content != nil {
+		indexBookmark(ctx, m.log, m.index, bm, content.Text)
+		reanchorHighlights(ctx, m.log, m.highlightRepo, userID, bookmarkID, content.Text)
+	} else {
+		// A failed fetch keeps the article of the last one.
+		indexBookmarks(ctx, m.log, m.index, m.bookmarkRepo, []models.Bookmark{*bm})
+	}
+
+	return bm, nil
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/fetcher"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func newTestFetcher() *fetcher.Fetcher {
+	config := fetcher.DefaultConfig()
+	config.AllowPrivateNetworks = true
+	config.Timeout = 2 * time.Second
+	return fetcher.NewFetcher(config)
+}
+
+func newPageServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func Test_RefreshMetadata_Success(t *testing.T) {
+	ts := newPageServer(200, `<head><title>Fetched</title><meta name="description" content="About"></head>
+<body><article><p>Some article text that is long enough to be picked up, with commas, too.</p></article></body>`)
+	defer ts.Close()
+
+	repo := new(mock.BookmarkStorageMock)
+	highlightRepo := new(mock.HighlightStorageMock)
+	index := search.NewMemoryIndex()
+	uc := NewMetadataUseCase(repo, highlightRepo, newTestFetcher(), index, logging.Discard())
+
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: ts.URL}
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(bm, nil).Once()
+	repo.On("SQLUpdateMetadata", testifymock.MatchedBy(func(u *models.Bookmark) bool {
+		return u.ID == 7 && u.URL == ts.URL && u.Title == "Fetched" && u.Description == "About" &&
+			u.FetchError == "" && u.FetchedAt != nil && u.WordCount == 14 && u.ReadingTime == 1
+	}), testifymock.MatchedBy(func(c *models.BookmarkContent) bool {
+		return c.BookmarkID == 7 && c.UserID == 1 && c.WordCount == 14 &&
+			c.HTML == "<p>Some article text that is long enough to be picked up, with commas, too.</p>"
+	})).Return(nil)
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7, UserID: 1, URL: ts.URL, Title: "Fetched"}, nil).Once()
+	// The new article moved one highlight and left the other in place.
+	highlightRepo.On("SQLListBookmarkHighlights", uint(1), uint(7)).Return([]models.Highlight{
+		{ID: 1, UserID: 1, BookmarkID: 7, StartOffset: 0, EndOffset: 4, Quote: "Some"},
+		{ID: 2, UserID: 1, BookmarkID: 7, StartOffset: 12, EndOffset: 18, Quote: "commas"},
+	}, nil)
+	highlightRepo.On("SQLUpdateHighlightAnchor", testifymock.MatchedBy(func(h *models.Highlight) bool {
+		return h.ID == 2 && h.StartOffset == 60 && h.EndOffset == 66 && h.Suffix == ", too."
+	})).Return(nil)
+
+	res, err := uc.RefreshMetadata(context.Background(), 1, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fetched", res.Title)
+	repo.AssertExpectations(t)
+	highlightRepo.AssertExpectations(t)
+	highlightRepo.AssertNumberOfCalls(t, "SQLUpdateHighlightAnchor", 1)
+
+	hits, _, _ := index.Search(context.Background(), 1, "fetched", 10, 0)
+	assert.Len(t, hits, 1)
+	// Only the article mentions commas.
+	hits, _, _ = index.Search(context.Background(), 1, "commas", 10, 0)
+	assert.Len(t, hits, 1)
+}
+
+func Test_RefreshMetadata_RecordsFetchError(t *testing.T) {
+	ts := newPageServer(500, "")
+	defer ts.Close()
+
+	repo := new(mock.BookmarkStorageMock)
+	highlightRepo := new(mock.HighlightStorageMock)
+	index := search.NewMemoryIndex()
+	uc := NewMetadataUseCase(repo, highlightRepo, newTestFetcher(), index, logging.Discard())
+
+	bm := &models.Bookmark{ID: 7, UserID: 1, URL: ts.URL, Description: "Old", SiteName: "example.com", WordCount: 120}
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(bm, nil)
+	repo.On("SQLUpdateMetadata", testifymock.MatchedBy(func(u *models.Bookmark) bool {
+		return u.FetchError != "" && u.Title == "" && u.Description == "Old" && u.SiteName == "example.com" && u.WordCount == 120
+	}), (*models.BookmarkContent)(nil)).Return(nil)
+	repo.On("SQLGetContentTexts", []uint{7}).Return(map[uint]string{7: "The article of an earlier fetch"}, nil)
+
+	_, err := uc.RefreshMetadata(context.Background(), 1, 7)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	hits, _, _ := index.Search(context.Background(), 1, "earlier", 10, 0)
+	assert.Len(t, hits, 1)
+	// Without a new article the highlights stay where they are.
+	highlightRepo.AssertNotCalled(t, "SQLListBookmarkHighlights", uint(1), uint(7))
+}
+
+func Test_MetadataWorkers_ProcessQueue(t *testing.T) {
+	ts := newPageServer(200, `<title>Queued</title>`)
+	defer ts.Close()
+
+	repo := new(mock.BookmarkStorageMock)
+	highlightRepo := new(mock.HighlightStorageMock)
+	uc := NewMetadataUseCase(repo, highlightRepo, newTestFetcher(), search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7, UserID: 1, URL: ts.URL}, nil)
+	repo.On("SQLUpdateMetadata", testifymock.Anything, testifymock.Anything).Return(nil)
+	repo.On("SQLGetContentTexts", []uint{7}).Return(map[uint]string{}, nil)
+	highlightRepo.On("SQLListBookmarkHighlights", uint(1), uint(7)).Return([]models.Highlight{}, nil)
+
+	uc.Start(2)
+	uc.Enqueue(context.Background(), 1, 7)
+	uc.Stop()
+
+	repo.AssertCalled(t, "SQLUpdateMetadata", testifymock.Anything, testifymock.Anything)
+
+	// Jobs after Stop are ignored.
+	uc.Enqueue(context.Background(), 1, 8)
+	repo.AssertNotCalled(t, "SQLGetBookmark", uint(1), uint(8))
+}
@@ -0,0 +1,317 @@
+package mock
+
+import (
+	"context"
+	"io"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type BookmarkUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *BookmarkUseCaseMock) CreateBookmark(ctx context.Context, userID uint, inp models.BookmarkInput) (*models.Bookmark, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+func (m *BookmarkUseCaseMock) GetBookmark(ctx context.Context, userID, id uint) (*models.Bookmark, error) {
+	args := m.Called(userID, id)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+func (m *BookmarkUseCaseMock) ListBookmarks(ctx context.Context, userID uint, inp models.ListInput) ([]models.Bookmark, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).([]models.Bookmark), args.Error(1)
+}
+
+func (m *BookmarkUseCaseMock) UpdateBookmark(ctx context.Context, userID, id uint, inp models.BookmarkInput) (*models.Bookmark, error) {
+	args := m.Called(userID, id, inp)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+func (m *BookmarkUseCaseMock) DeleteBookmark(ctx context.Context, userID, id uint) error {
+	args := m.Called(userID, id)
+
+	return args.Error(0)
+}
+
+func (m *BookmarkUseCaseMock) GetContent(ctx context.Context, userID, id uint) (*models.BookmarkContent, error) {
+	args := m.Called(userID, id)
+
+	return args.Get(0).(*models.BookmarkContent), args.Error(1)
+}
+
+func (m *BookmarkUseCaseMock) UpdateState(ctx context.Context, userID, id uint, inp models.StateInput) (*models.Bookmark, error) {
+	args := m.Called(userID, id, inp)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+func (m *BookmarkUseCaseMock) BulkUpdateState(ctx context.Context, userID uint, inp models.BulkStateInput) (*models.BulkStateResponse, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).(*models.BulkStateResponse), args.Error(1)
+}
+
+func (m *BookmarkUseCaseMock) Queue(ctx context.Context, userID uint, inp models.QueueInput) ([]models.Bookmark, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).([]models.Bookmark), args.Error(1)
+}
+
+type TagUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *TagUseCaseMock) AddTags(ctx context.Context, userID, bookmarkID uint, inp models.TagsInput) ([]models.Tag, error) {
+	args := m.Called(userID, bookmarkID, inp)
+
+	return args.Get(0).([]models.Tag), args.Error(1)
+}
+
+func (m *TagUseCaseMock) RemoveTag(ctx context.Context, userID, bookmarkID uint, name string) error {
+	args := m.Called(userID, bookmarkID, name)
+
+	return args.Error(0)
+}
+
+func (m *TagUseCaseMock) ListTags(ctx context.Context, userID uint) ([]models.TagCount, error) {
+	args := m.Called(userID)
+
+	return args.Get(0).([]models.TagCount), args.Error(1)
+}
+
+func (m *TagUseCaseMock) RenameTag(ctx context.Context, userID, id uint, inp models.RenameTagInput) (*models.Tag, error) {
+	args := m.Called(userID, id, inp)
+
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *TagUseCaseMock) MergeTags(ctx context.Context, userID uint, inp models.MergeTagsInput) (*models.Tag, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *TagUseCaseMock) DeleteTag(ctx context.Context, userID, id uint) error {
+	args := m.Called(userID, id)
+
+	return args.Error(0)
+}
+
+type CollectionUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *CollectionUseCaseMock) ListCollections(ctx context.Context, userID uint) ([]models.CollectionNode, error) {
+	args := m.Called(userID)
+
+	return args.Get(0).([]models.CollectionNode), args.Error(1)
+}
+
+func (m *CollectionUseCaseMock) CreateCollection(ctx context.Context, userID uint, inp models.CollectionInput) (*models.Collection, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *CollectionUseCaseMock) GetCollection(ctx context.Context, userID, id uint) (*models.Collection, error) {
+	args := m.Called(userID, id)
+
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *CollectionUseCaseMock) RenameCollection(ctx context.Context, userID, id uint, inp models.RenameCollectionInput) (*models.Collection, error) {
+	args := m.Called(userID, id, inp)
+
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *CollectionUseCaseMock) MoveCollection(ctx context.Context, userID, id uint, inp models.MoveCollectionInput) (*models.Collection, error) {
+	args := m.Called(userID, id, inp)
+
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *CollectionUseCaseMock) DeleteCollection(ctx context.Context, userID, id uint, mode string) error {
+	args := m.Called(userID, id, mode)
+
+	return args.Error(0)
+}
+
+func (m *CollectionUseCaseMock) SetBookmarkCollection(ctx context.Context, userID, bookmarkID uint, inp models.BookmarkCollectionInput) (*models.Bookmark, error) {
+	args := m.Called(userID, bookmarkID, inp)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+type ShareUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *ShareUseCaseMock) CreateShare(ctx context.Context, userID uint, inp models.ShareInput) (*models.Share, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).(*models.Share), args.Error(1)
+}
+
+func (m *ShareUseCaseMock) ListShares(ctx context.Context, userID uint) ([]models.Share, error) {
+	args := m.Called(userID)
+
+	return args.Get(0).([]models.Share), args.Error(1)
+}
+
+func (m *ShareUseCaseMock) RevokeShare(ctx context.Context, userID, id uint) error {
+	args := m.Called(userID, id)
+
+	return args.Error(0)
+}
+
+func (m *ShareUseCaseMock) ViewShare(ctx context.Context, token, password string, inp models.ShareViewInput) (*models.PublicShare, error) {
+	args := m.Called(token, password, inp)
+
+	return args.Get(0).(*models.PublicShare), args.Error(1)
+}
+
+type FeedUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *FeedUseCaseMock) CreateFeed(ctx context.Context, userID uint, inp models.FeedInput) (*models.Feed, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).(*models.Feed), args.Error(1)
+}
+
+func (m *FeedUseCaseMock) ListFeeds(ctx context.Context, userID uint) ([]models.Feed, error) {
+	args := m.Called(userID)
+
+	return args.Get(0).([]models.Feed), args.Error(1)
+}
+
+func (m *FeedUseCaseMock) RevokeFeed(ctx context.Context, userID, id uint) error {
+	args := m.Called(userID, id)
+
+	return args.Error(0)
+}
+
+func (m *FeedUseCaseMock) GetFeed(ctx context.Context, token string) (*models.FeedDocument, error) {
+	args := m.Called(token)
+
+	return args.Get(0).(*models.FeedDocument), args.Error(1)
+}
+
+func (m *FeedUseCaseMock) WriteFeed(w io.Writer, format string, feed *models.FeedDocument) error {
+	args := m.Called(w, format, feed)
+
+	return args.Error(0)
+}
+
+type SearchUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *SearchUseCaseMock) Search(ctx context.Context, userID uint, inp models.SearchInput) (*models.SearchResponse, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).(*models.SearchResponse), args.Error(1)
+}
+
+func (m *SearchUseCaseMock) RebuildIndex(ctx context.Context) error {
+	args := m.Called()
+
+	return args.Error(0)
+}
+
+type MetadataUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *MetadataUseCaseMock) Enqueue(ctx context.Context, userID, bookmarkID uint) {
+	m.Called(userID, bookmarkID)
+}
+
+func (m *MetadataUseCaseMock) RefreshMetadata(ctx context.Context, userID, bookmarkID uint) (*models.Bookmark, error) {
+	args := m.Called(userID, bookmarkID)
+
+	return args.Get(0).(*models.Bookmark), args.Error(1)
+}
+
+type ImportUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *ImportUseCaseMock) StartImport(ctx context.Context, userID uint, inp models.ImportInput, data []byte) (*models.ImportJob, error) {
+	args := m.Called(userID, inp, data)
+
+	return args.Get(0).(*models.ImportJob), args.Error(1)
+}
+
+func (m *ImportUseCaseMock) GetImport(ctx context.Context, userID, id uint) (*models.ImportJob, error) {
+	args := m.Called(userID, id)
+
+	return args.Get(0).(*models.ImportJob), args.Error(1)
+}
+
+func (m *ImportUseCaseMock) ListImports(ctx context.Context, userID uint) ([]models.ImportJob, error) {
+	args := m.Called(userID)
+
+	return args.Get(0).([]models.ImportJob), args.Error(1)
+}
+
+type ExportUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *ExportUseCaseMock) ExportBookmarks(ctx context.Context, userID uint, format string, w io.Writer) error {
+	args := m.Called(userID, format, w)
+
+	return args.Error(0)
+}
+
+type HighlightUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *HighlightUseCaseMock) CreateHighlight(ctx context.Context, userID, bookmarkID uint, inp models.HighlightInput) (*models.Highlight, error) {
+	args := m.Called(userID, bookmarkID, inp)
+
+	return args.Get(0).(*models.Highlight), args.Error(1)
+}
+
+func (m *HighlightUseCaseMock) ListBookmarkHighlights(ctx context.Context, userID, bookmarkID uint) ([]models.Highlight, error) {
+	args := m.Called(userID, bookmarkID)
+
+	return args.Get(0).([]models.Highlight), args.Error(1)
+}
+
+func (m *HighlightUseCaseMock) ListHighlights(ctx context.Context, userID uint, inp models.HighlightListInput) ([]models.Highlight, error) {
+	args := m.Called(userID, inp)
+
+	return args.Get(0).([]models.Highlight), args.Error(1)
+}
+
+func (m *HighlightUseCaseMock) UpdateHighlight(ctx context.Context, userID, id uint, inp models.HighlightUpdateInput) (*models.Highlight, error) {
+	args := m.Called(userID, id, inp)
+
+	return args.Get(0).(*models.Highlight), args.Error(1)
+}
+
+func (m *HighlightUseCaseMock) DeleteHighlight(ctx context.Context, userID, id uint) error {
+	args := m.Called(userID, id)
+
+	return args.Error(0)
+}
+
+func (m *HighlightUseCaseMock) ExportHighlights(ctx context.Context, userID, bookmarkID uint, w io.Writer) error {
+	args := m.Called(userID, bookmarkID, w)
+
+	return args.Error(0)
+}
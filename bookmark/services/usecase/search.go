@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+)
+
+const rebuildBatchSize = 500
+
+type SearchUseCase struct {
+	index        services.SearchIndex
+	bookmarkRepo services.BookmarkRepositorySQL
+	log          *slog.Logger
+}
+
+func NewSearchUseCase(index services.SearchIndex, bookmarkRepo services.BookmarkRepositorySQL, log *slog.Logger) *SearchUseCase {
+	return &SearchUseCase{
+		index:        index,
+		bookmarkRepo: bookmarkRepo,
+		log:          log,
+	}
+}
+
+func (s *SearchUseCase) Search(ctx context.Context, userID uint, inp models.SearchInput) (*models.SearchResponse, error) {
+	if strings.TrimSpace(inp.Q) == "" {
+		return nil, bookmark.ErrDataTidakLengkap
+	}
+	if inp.Page < 1 {
+		inp.Page = 1
+	}
+	if inp.Limit <= 0 {
+		inp.Limit = defaultListLimit
+	}
+	if inp.Limit > maxListLimit {
+		inp.Limit = maxListLimit
+	}
+
+	hits, total, err := s.index.Search(ctx, userID, inp.Q, inp.Limit, (inp.Page-1)*inp.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.BookmarkID)
+	}
+
+	bookmarks, err := s.bookmarkRepo.SQLGetBookmarksByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Bookmark, len(bookmarks))
+	for _, bm := range bookmarks {
+		byID[bm.ID] = bm
+	}
+
+	// Keep the index's ranking; hits whose bookmark vanished in the meantime
+	// are dropped.
+	results := make([]models.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		bm, ok := byID[hit.BookmarkID]
+		if !ok {
+			continue
+		}
+		results = append(results, models.SearchResult{Bookmark: bm, Score: hit.Score, Snippet: hit.Snippet})
+	}
+
+	return &models.SearchResponse{Results: results, Total: total, Page: inp.Page, Limit: inp.Limit}, nil
+}
+
+// RebuildIndex indexes every stored bookmark if the index reports that it is
+// missing data, as the in-memory index does after every restart. It stops
+// between batches once ctx is done.
+func (s *SearchUseCase) RebuildIndex(ctx context.Context) error {
+	needed, err := s.index.NeedsRebuild(ctx)
+	if err != nil || !needed {
+		return err
+	}
+
+	return s.bookmarkRepo.SQLEachBookmark(ctx, rebuildBatchSize, func(batch []models.Bookmark) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		texts, err := s.bookmarkRepo.SQLGetContentTexts(ctx, bookmarkIDs(batch))
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := s.index.Index(ctx, searchDocument(&batch[i], texts[batch[i].ID])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// searchDocument is what the index knows of bm, with content the text of
+// the article extracted from its page, if any.
+func searchDocument(bm *models.Bookmark, content string) models.SearchDocument {
+	tags := make([]string, 0, len(bm.Tags))
+	for _, tag := range bm.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return models.SearchDocument{
+		BookmarkID: bm.ID,
+		UserID:     bm.UserID,
+		Title:      bm.Title,
+		URL:        bm.URL,
+		Notes:      bm.Notes,
+		Tags:       strings.Join(tags, " "),
+		Content:    content,
+	}
+}
+
+func bookmarkIDs(bookmarks []models.Bookmark) []uint {
+	ids := make([]uint, 0, len(bookmarks))
+	for _, bm := range bookmarks {
+		ids = append(ids, bm.ID)
+	}
+	return ids
+}
+
+// reindexBookmarks refreshes the search documents of already saved bookmarks.
+// The write has been committed by then, so failures are logged instead of
+// failing the request; RebuildIndex recovers anything missed.
+func reindexBookmarks(ctx context.Context, log *slog.Logger, index services.SearchIndex, repo services.BookmarkRepositorySQL, userID uint, ids []uint) {
+	if len(ids) == 0 {
+		return
+	}
+
+	bookmarks, err := repo.SQLGetBookmarksByIDs(ctx, userID, ids)
+	if err != nil {
+		log.ErrorContext(ctx, "search: failed to load bookmarks for indexing", "bookmarks", ids, "err", err)
+		return
+	}
+
+	indexBookmarks(ctx, log, index, repo, bookmarks)
+}
+
+// indexBookmarks indexes saved bookmarks along with their stored article
+// text. Without the text they are left as they are, so that it is not lost
+// from the index.
+func indexBookmarks(ctx context.Context, log *slog.Logger, index services.SearchIndex, repo services.BookmarkRepositorySQL, bookmarks []models.Bookmark) {
+	texts, err := repo.SQLGetContentTexts(ctx, bookmarkIDs(bookmarks))
+	if err != nil {
+		log.ErrorContext(ctx, "search: failed to load article texts for indexing", "bookmarks", bookmarkIDs(bookmarks), "err", err)
+		return
+	}
+
+	for i := range bookmarks {
+		indexBookmark(ctx, log, index, &bookmarks[i], texts[bookmarks[i].ID])
+	}
+}
+
+func indexBookmark(ctx context.Context, log *slog.Logger, index services.SearchIndex, bm *models.Bookmark, content string) {
+	if err := index.Index(ctx, searchDocument(bm, content)); err != nil {
+		log.ErrorContext(ctx, "search: failed to index bookmark", "bookmark", bm.ID, "err", err)
+	}
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func Test_Search_KeepsRanking(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	index := search.NewMemoryIndex()
+	uc := NewSearchUseCase(index, repo, logging.Discard())
+
+	require.NoError(t, index.Index(context.Background(), models.SearchDocument{BookmarkID: 7, UserID: 1, Title: "Clean Architecture"}))
+	require.NoError(t, index.Index(context.Background(), models.SearchDocument{BookmarkID: 8, UserID: 1, Title: "Misc", Notes: "about clean architecture"}))
+
+	// The repository returns rows in its own order.
+	repo.On("SQLGetBookmarksByIDs", uint(1), []uint{7, 8}).Return([]models.Bookmark{{ID: 8, Title: "Misc"}, {ID: 7, Title: "Clean Architecture"}}, nil)
+
+	resp, err := uc.Search(context.Background(), 1, models.SearchInput{Q: "clean architecture"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Equal(t, 1, resp.Page)
+	assert.Equal(t, 20, resp.Limit)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, uint(7), resp.Results[0].Bookmark.ID)
+	assert.Equal(t, uint(8), resp.Results[1].Bookmark.ID)
+	assert.Contains(t, resp.Results[1].Snippet, "<mark>clean</mark>")
+}
+
+func Test_Search_Failed_EmptyQuery(t *testing.T) {
+	uc := NewSearchUseCase(search.NewMemoryIndex(), new(mock.BookmarkStorageMock), logging.Discard())
+
+	_, err := uc.Search(context.Background(), 1, models.SearchInput{Q: "  "})
+	assert.Equal(t, bookmark.ErrDataTidakLengkap, err)
+}
+
+func Test_RebuildIndex(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	index := search.NewMemoryIndex()
+	uc := NewSearchUseCase(index, repo, logging.Discard())
+
+	repo.On("SQLEachBookmark", rebuildBatchSize).Return([]models.Bookmark{
+		{ID: 7, UserID: 1, Title: "Clean Architecture", Tags: []models.Tag{{Name: "design"}}},
+	}, nil).Once()
+	repo.On("SQLGetContentTexts", []uint{7}).Return(map[uint]string{7: "Policies sit above details."}, nil)
+
+	require.NoError(t, uc.RebuildIndex(context.Background()))
+	hits, _, err := index.Search(context.Background(), 1, "design", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	hits, _, err = index.Search(context.Background(), 1, "policies", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+
+	// A populated index is left alone.
+	require.NoError(t, uc.RebuildIndex(context.Background()))
+	repo.AssertNumberOfCalls(t, "SQLEachBookmark", 1)
+}
+
+func Test_RebuildIndex_Stopped(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewSearchUseCase(search.NewMemoryIndex(), repo, logging.Discard())
+
+	repo.On("SQLEachBookmark", rebuildBatchSize).Return([]models.Bookmark{{ID: 7, UserID: 1}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, uc.RebuildIndex(ctx), context.Canceled)
+	repo.AssertNotCalled(t, "SQLGetContentTexts", testifymock.Anything)
+}
+
+func Test_BookmarkLifecycle_UpdatesIndex(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	index := search.NewMemoryIndex()
+	uc := NewBookmarkUseCase(repo, index, newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmarkByURLHash", uint(1), testifymock.Anything).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	repo.On("SQLCreateBookmark", testifymock.Anything).Return(nil)
+	repo.On("SQLDeleteBookmark", uint(1), uint(0)).Return(nil)
+
+	_, err := uc.CreateBookmark(context.Background(), 1, models.BookmarkInput{URL: "https://example.com", Title: "Clean Architecture"})
+	require.NoError(t, err)
+	hits, _, _ := index.Search(context.Background(), 1, "architecture", 10, 0)
+	assert.Len(t, hits, 1)
+
+	require.NoError(t, uc.DeleteBookmark(context.Background(), 1, 0))
+	hits, _, _ = index.Search(context.Background(), 1, "architecture", 10, 0)
+	assert.Empty(t, hits)
+}
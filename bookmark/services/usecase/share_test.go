@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newShareUseCase() (*ShareUseCase, *mock.ShareStorageMock, *mock.BookmarkStorageMock, *mock.CollectionStorageMock) {
+	shareRepo := new(mock.ShareStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	collectionRepo := new(mock.CollectionStorageMock)
+	return NewShareUseCase(shareRepo, bookmarkRepo, collectionRepo, logging.Discard()), shareRepo, bookmarkRepo, collectionRepo
+}
+
+func Test_CreateShare_Success(t *testing.T) {
+	uc, shareRepo, bookmarkRepo, _ := newShareUseCase()
+
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7}, nil)
+	shareRepo.On("SQLCreateShare", testifymock.Anything).Return(nil)
+
+	expires := time.Now().Add(time.Hour)
+	share, err := uc.CreateShare(context.Background(), 1, models.ShareInput{BookmarkID: uintPtr(7), Password: "rahasia", ExpiresAt: &expires})
+	assert.NoError(t, err)
+	assert.Len(t, share.Token, 43)
+	assert.True(t, share.Protected)
+	assert.NotEqual(t, "rahasia", share.PasswordHash)
+	assert.Equal(t, hashSharePassword("rahasia", share.PasswordSalt), share.PasswordHash)
+
+	other, err := uc.CreateShare(context.Background(), 1, models.ShareInput{BookmarkID: uintPtr(7)})
+	assert.NoError(t, err)
+	assert.NotEqual(t, share.Token, other.Token)
+	assert.False(t, other.Protected)
+	assert.Empty(t, other.PasswordHash)
+}
+
+func Test_CreateShare_Failed(t *testing.T) {
+	uc, shareRepo, bookmarkRepo, collectionRepo := newShareUseCase()
+
+	bookmarkRepo.On("SQLGetBookmark", uint(2), uint(7)).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	collectionRepo.On("SQLGetCollection", uint(2), uint(5)).Return((*models.Collection)(nil), gorm.ErrRecordNotFound)
+
+	_, err := uc.CreateShare(context.Background(), 2, models.ShareInput{})
+	assert.Equal(t, bookmark.ErrShareTarget, err)
+	_, err = uc.CreateShare(context.Background(), 2, models.ShareInput{BookmarkID: uintPtr(7), CollectionID: uintPtr(5)})
+	assert.Equal(t, bookmark.ErrShareTarget, err)
+
+	past := time.Now().Add(-time.Minute)
+	_, err = uc.CreateShare(context.Background(), 2, models.ShareInput{BookmarkID: uintPtr(7), ExpiresAt: &past})
+	assert.Equal(t, bookmark.ErrInvalidExpiry, err)
+
+	_, err = uc.CreateShare(context.Background(), 2, models.ShareInput{BookmarkID: uintPtr(7)})
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+	_, err = uc.CreateShare(context.Background(), 2, models.ShareInput{CollectionID: uintPtr(5)})
+	assert.Equal(t, bookmark.ErrCollectionNotFound, err)
+	shareRepo.AssertNotCalled(t, "SQLCreateShare", testifymock.Anything)
+}
+
+func Test_ViewShare_Bookmark(t *testing.T) {
+	uc, shareRepo, bookmarkRepo, _ := newShareUseCase()
+
+	share := &models.Share{ID: 3, UserID: 1, Token: "abc", BookmarkID: uintPtr(7), Protected: true, PasswordSalt: "salt", PasswordFailures: 2}
+	share.PasswordHash = hashSharePassword("rahasia", "salt")
+	shareRepo.On("SQLGetShareByToken", "abc").Return(share, nil)
+	shareRepo.On("SQLRecordView", uint(3), testifymock.Anything).Return(nil)
+	shareRepo.On("SQLRecordPasswordFailure", uint(3), sharePasswordAttempts, testifymock.Anything).Return(nil)
+	shareRepo.On("SQLResetPasswordFailures", uint(3)).Return(nil)
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7, URL: "https://go.dev/", Title: "Go", Notes: "private"}, nil)
+
+	_, err := uc.ViewShare(context.Background(), "abc", "", models.ShareViewInput{})
+	assert.Equal(t, bookmark.ErrSharePassword, err)
+	_, err = uc.ViewShare(context.Background(), "abc", "salah", models.ShareViewInput{})
+	assert.Equal(t, bookmark.ErrSharePassword, err)
+	shareRepo.AssertNotCalled(t, "SQLRecordView", uint(3), testifymock.Anything)
+	shareRepo.AssertNumberOfCalls(t, "SQLRecordPasswordFailure", 2)
+
+	res, err := uc.ViewShare(context.Background(), "abc", "rahasia", models.ShareViewInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Go", res.Bookmark.Title)
+	assert.Nil(t, res.Collection)
+	shareRepo.AssertNumberOfCalls(t, "SQLRecordView", 1)
+	shareRepo.AssertNumberOfCalls(t, "SQLResetPasswordFailures", 1)
+}
+
+func Test_ViewShare_Locked(t *testing.T) {
+	uc, shareRepo, _, _ := newShareUseCase()
+
+	until := time.Now().Add(time.Minute)
+	share := &models.Share{ID: 3, UserID: 1, Token: "abc", BookmarkID: uintPtr(7), Protected: true, PasswordSalt: "salt", LockedUntil: &until}
+	share.PasswordHash = hashSharePassword("rahasia", "salt")
+	shareRepo.On("SQLGetShareByToken", "abc").Return(share, nil)
+
+	// Even the right password is refused until the lock passes.
+	_, err := uc.ViewShare(context.Background(), "abc", "rahasia", models.ShareViewInput{})
+	assert.Equal(t, bookmark.ErrShareLocked, err)
+	_, err = uc.ViewShare(context.Background(), "abc", "salah", models.ShareViewInput{})
+	assert.Equal(t, bookmark.ErrShareLocked, err)
+	shareRepo.AssertNotCalled(t, "SQLRecordPasswordFailure", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func Test_ViewShare_Collection(t *testing.T) {
+	uc, shareRepo, bookmarkRepo, collectionRepo := newShareUseCase()
+
+	shareRepo.On("SQLGetShareByToken", "abc").Return(&models.Share{ID: 3, UserID: 1, CollectionID: uintPtr(5)}, nil)
+	shareRepo.On("SQLRecordView", uint(3), testifymock.Anything).Return(nil)
+	collectionRepo.On("SQLGetCollection", uint(1), uint(5)).Return(&models.Collection{ID: 5, Name: "Bacaan"}, nil)
+	bookmarkRepo.On("SQLListBookmarks", uint(1), models.ListInput{Limit: 20, TagMode: models.TagModeAny, Collection: 5, Recursive: true}).
+		Return([]models.Bookmark{{ID: 7, URL: "https://go.dev/"}, {ID: 8, URL: "https://sqlite.org/"}}, nil)
+
+	res, err := uc.ViewShare(context.Background(), "abc", "", models.ShareViewInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bacaan", res.Collection.Name)
+	assert.Len(t, res.Collection.Bookmarks, 2)
+
+	// The next pages are part of the same view.
+	bookmarkRepo.On("SQLListBookmarks", uint(1), models.ListInput{Limit: 20, Offset: 20, TagMode: models.TagModeAny, Collection: 5, Recursive: true}).
+		Return([]models.Bookmark{{ID: 9, URL: "https://example.com/"}}, nil)
+	_, err = uc.ViewShare(context.Background(), "abc", "", models.ShareViewInput{Offset: 20})
+	assert.NoError(t, err)
+	shareRepo.AssertNumberOfCalls(t, "SQLRecordView", 1)
+}
+
+func Test_ViewShare_Unavailable(t *testing.T) {
+	uc, shareRepo, bookmarkRepo, _ := newShareUseCase()
+
+	past := time.Now().Add(-time.Minute)
+	shareRepo.On("SQLGetShareByToken", "nope").Return((*models.Share)(nil), gorm.ErrRecordNotFound)
+	shareRepo.On("SQLGetShareByToken", "revoked").Return(&models.Share{ID: 1, BookmarkID: uintPtr(7), RevokedAt: &past}, nil)
+	shareRepo.On("SQLGetShareByToken", "expired").Return(&models.Share{ID: 2, BookmarkID: uintPtr(7), ExpiresAt: &past}, nil)
+	shareRepo.On("SQLGetShareByToken", "gone").Return(&models.Share{ID: 3, UserID: 1, BookmarkID: uintPtr(8)}, nil)
+	bookmarkRepo.On("SQLGetBookmark", uint(1), uint(8)).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+
+	for token, expected := range map[string]error{
+		"nope":    bookmark.ErrShareNotFound,
+		"revoked": bookmark.ErrShareNotFound,
+		"expired": bookmark.ErrShareExpired,
+		"gone":    bookmark.ErrShareNotFound,
+	} {
+		_, err := uc.ViewShare(context.Background(), token, "", models.ShareViewInput{})
+		assert.Equal(t, expected, err, token)
+	}
+}
+
+func Test_RevokeShare(t *testing.T) {
+	uc, shareRepo, _, _ := newShareUseCase()
+
+	shareRepo.On("SQLRevokeShare", uint(1), uint(3), testifymock.Anything).Return(nil)
+	shareRepo.On("SQLRevokeShare", uint(2), uint(3), testifymock.Anything).Return(gorm.ErrRecordNotFound)
+
+	assert.NoError(t, uc.RevokeShare(context.Background(), 1, 3))
+	assert.Equal(t, bookmark.ErrShareNotFound, uc.RevokeShare(context.Background(), 2, 3))
+}
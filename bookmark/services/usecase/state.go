@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+)
+
+const maxBulkIDs = 500
+
+func (b *BookmarkUseCase) UpdateState(ctx context.Context, userID, id uint, inp models.StateInput) (*models.Bookmark, error) {
+	if err := validateState(inp); err != nil {
+		return nil, err
+	}
+
+	bm, err := b.bookmarkRepo.SQLGetBookmark(ctx, userID, id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	applyState(bm, inp, time.Now())
+	if err := b.bookmarkRepo.SQLUpdateStates(ctx, []models.Bookmark{*bm}); err != nil {
+		return nil, notFound(err)
+	}
+
+	return bm, nil
+}
+
+// BulkUpdateState applies the same change to every listed bookmark. If one of
+// them is missing nothing is changed.
+func (b *BookmarkUseCase) BulkUpdateState(ctx context.Context, userID uint, inp models.BulkStateInput) (*models.BulkStateResponse, error) {
+	ids := uniqueIDs(inp.IDs)
+	if len(ids) == 0 || len(ids) > maxBulkIDs {
+		return nil, bookmark.ErrBadRequest
+	}
+	if err := validateState(inp.StateInput); err != nil {
+		return nil, err
+	}
+
+	bookmarks, err := b.bookmarkRepo.SQLGetBookmarksByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(bookmarks) != len(ids) {
+		return nil, bookmark.ErrBookmarkNotFound
+	}
+
+	now := time.Now()
+	for i := range bookmarks {
+		applyState(&bookmarks[i], inp.StateInput, now)
+	}
+	if err := b.bookmarkRepo.SQLUpdateStates(ctx, bookmarks); err != nil {
+		return nil, notFound(err)
+	}
+
+	return &models.BulkStateResponse{Updated: len(bookmarks)}, nil
+}
+
+// Queue lists unread bookmarks, by default the highest priority first and
+// the oldest first within a priority.
+func (b *BookmarkUseCase) Queue(ctx context.Context, userID uint, inp models.QueueInput) ([]models.Bookmark, error) {
+	inp.Limit, inp.Offset = pageBounds(inp.Limit, inp.Offset)
+
+	switch inp.Order {
+	case "":
+		inp.Order = models.QueueOrderPriority
+	case models.QueueOrderPriority, models.QueueOrderOldest, models.QueueOrderNewest,
+		models.QueueOrderShortest, models.QueueOrderLongest:
+	default:
+		return nil, bookmark.ErrBadRequest
+	}
+
+	if inp.MaxTime < 0 {
+		return nil, bookmark.ErrBadRequest
+	}
+
+	return b.bookmarkRepo.SQLQueue(ctx, userID, inp)
+}
+
+func validateState(inp models.StateInput) error {
+	if inp.Status == nil && inp.Favorite == nil && inp.Progress == nil && inp.Priority == nil {
+		return bookmark.ErrBadRequest
+	}
+
+	if inp.Status != nil && !validStatus(*inp.Status) {
+		return bookmark.ErrInvalidState
+	}
+	if inp.Progress != nil && (*inp.Progress < 0 || *inp.Progress > 100) {
+		return bookmark.ErrInvalidState
+	}
+	if inp.Priority != nil && (*inp.Priority < 0 || *inp.Priority > models.MaxPriority) {
+		return bookmark.ErrInvalidState
+	}
+
+	return nil
+}
+
+func validStatus(status string) bool {
+	switch status {
+	case models.StatusUnread, models.StatusRead, models.StatusArchived:
+		return true
+	}
+	return false
+}
+
+// applyState changes bm as asked and keeps the timestamps in step. Reading to
+// the end marks an unread bookmark read, marking it read finishes it unless
+// a progress is given, and marking a finished one unread starts it over.
+func applyState(bm *models.Bookmark, inp models.StateInput, now time.Time) {
+	if inp.Priority != nil {
+		bm.Priority = *inp.Priority
+	}
+
+	if inp.Favorite != nil && *inp.Favorite != bm.Favorite {
+		bm.Favorite = *inp.Favorite
+		bm.FavoritedAt = nil
+		if bm.Favorite {
+			bm.FavoritedAt = &now
+		}
+	}
+
+	if inp.Progress != nil {
+		bm.Progress = *inp.Progress
+		if bm.Progress == 100 && bm.Status == models.StatusUnread && inp.Status == nil {
+			bm.Status = models.StatusRead
+		}
+	}
+	if inp.Status != nil {
+		bm.Status = *inp.Status
+	}
+
+	switch bm.Status {
+	case models.StatusUnread:
+		bm.ReadAt = nil
+		bm.ArchivedAt = nil
+		if inp.Status != nil && inp.Progress == nil && bm.Progress == 100 {
+			bm.Progress = 0
+		}
+	case models.StatusRead:
+		if bm.ReadAt == nil {
+			bm.ReadAt = &now
+		}
+		bm.ArchivedAt = nil
+		if inp.Status != nil && inp.Progress == nil {
+			bm.Progress = 100
+		}
+	case models.StatusArchived:
+		if bm.ArchivedAt == nil {
+			bm.ArchivedAt = &now
+		}
+	}
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
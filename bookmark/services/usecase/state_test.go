@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
+func intPtr(i int) *int       { return &i }
+
+func Test_UpdateState_Success(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7, UserID: 1, Status: models.StatusUnread}, nil)
+	repo.On("SQLUpdateStates", testifymock.Anything).Return(nil)
+
+	res, err := uc.UpdateState(context.Background(), 1, 7, models.StateInput{Status: strPtr(models.StatusRead), Favorite: boolPtr(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusRead, res.Status)
+	assert.Equal(t, 100, res.Progress)
+	assert.NotNil(t, res.ReadAt)
+	assert.True(t, res.Favorite)
+	assert.NotNil(t, res.FavoritedAt)
+
+	saved := repo.Calls[1].Arguments.Get(0).([]models.Bookmark)
+	assert.Equal(t, *res, saved[0])
+}
+
+func Test_UpdateState_Failed(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmark", uint(2), uint(7)).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+
+	_, err := uc.UpdateState(context.Background(), 1, 7, models.StateInput{})
+	assert.Equal(t, bookmark.ErrBadRequest, err)
+	_, err = uc.UpdateState(context.Background(), 1, 7, models.StateInput{Status: strPtr("deleted")})
+	assert.Equal(t, bookmark.ErrInvalidState, err)
+	_, err = uc.UpdateState(context.Background(), 1, 7, models.StateInput{Progress: intPtr(101)})
+	assert.Equal(t, bookmark.ErrInvalidState, err)
+	_, err = uc.UpdateState(context.Background(), 1, 7, models.StateInput{Priority: intPtr(models.MaxPriority + 1)})
+	assert.Equal(t, bookmark.ErrInvalidState, err)
+	repo.AssertNotCalled(t, "SQLGetBookmark", uint(1), uint(7))
+
+	_, err = uc.UpdateState(context.Background(), 2, 7, models.StateInput{Favorite: boolPtr(true)})
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+}
+
+func Test_ApplyState(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	// Reading to the end marks an unread bookmark read.
+	bm := &models.Bookmark{Status: models.StatusUnread, Progress: 40}
+	applyState(bm, models.StateInput{Progress: intPtr(100)}, now)
+	assert.Equal(t, models.StatusRead, bm.Status)
+	assert.Equal(t, &now, bm.ReadAt)
+
+	// Archiving keeps when it was read; unarchiving to unread starts over.
+	bm = &models.Bookmark{Status: models.StatusRead, Progress: 100, ReadAt: &earlier}
+	applyState(bm, models.StateInput{Status: strPtr(models.StatusArchived)}, now)
+	assert.Equal(t, &earlier, bm.ReadAt)
+	assert.Equal(t, &now, bm.ArchivedAt)
+	applyState(bm, models.StateInput{Status: strPtr(models.StatusUnread)}, now)
+	assert.Nil(t, bm.ReadAt)
+	assert.Nil(t, bm.ArchivedAt)
+	assert.Equal(t, 0, bm.Progress)
+
+	// Favoriting twice keeps the first timestamp.
+	bm = &models.Bookmark{Status: models.StatusUnread, Favorite: true, FavoritedAt: &earlier}
+	applyState(bm, models.StateInput{Favorite: boolPtr(true), Priority: intPtr(2)}, now)
+	assert.Equal(t, &earlier, bm.FavoritedAt)
+	assert.Equal(t, 2, bm.Priority)
+	applyState(bm, models.StateInput{Favorite: boolPtr(false)}, now)
+	assert.Nil(t, bm.FavoritedAt)
+}
+
+func Test_BulkUpdateState_Success(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmarksByIDs", uint(1), []uint{7, 8}).Return([]models.Bookmark{
+		{ID: 7, UserID: 1, Status: models.StatusUnread},
+		{ID: 8, UserID: 1, Status: models.StatusRead},
+	}, nil)
+	repo.On("SQLUpdateStates", testifymock.MatchedBy(func(bookmarks []models.Bookmark) bool {
+		return len(bookmarks) == 2 && bookmarks[0].Status == models.StatusArchived && bookmarks[1].ArchivedAt != nil
+	})).Return(nil)
+
+	res, err := uc.BulkUpdateState(context.Background(), 1, models.BulkStateInput{IDs: []uint{7, 8, 7}, StateInput: models.StateInput{Status: strPtr(models.StatusArchived)}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Updated)
+	repo.AssertExpectations(t)
+}
+
+func Test_BulkUpdateState_Failed(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+	read := models.StateInput{Status: strPtr(models.StatusRead)}
+
+	_, err := uc.BulkUpdateState(context.Background(), 1, models.BulkStateInput{StateInput: read})
+	assert.Equal(t, bookmark.ErrBadRequest, err)
+
+	ids := make([]uint, maxBulkIDs+1)
+	for i := range ids {
+		ids[i] = uint(i + 1)
+	}
+	_, err = uc.BulkUpdateState(context.Background(), 1, models.BulkStateInput{IDs: ids, StateInput: read})
+	assert.Equal(t, bookmark.ErrBadRequest, err)
+
+	// One of them belongs to someone else.
+	repo.On("SQLGetBookmarksByIDs", uint(1), []uint{7, 9}).Return([]models.Bookmark{{ID: 7, UserID: 1}}, nil)
+	_, err = uc.BulkUpdateState(context.Background(), 1, models.BulkStateInput{IDs: []uint{7, 9}, StateInput: read})
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+	repo.AssertNotCalled(t, "SQLUpdateStates", testifymock.Anything)
+}
+
+func Test_Queue(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLQueue", uint(1), models.QueueInput{Order: models.QueueOrderPriority, Limit: 20}).Return([]models.Bookmark{}, nil)
+	repo.On("SQLQueue", uint(1), models.QueueInput{Order: models.QueueOrderShortest, MaxTime: 10, Limit: 100}).Return([]models.Bookmark{}, nil)
+
+	_, err := uc.Queue(context.Background(), 1, models.QueueInput{})
+	assert.NoError(t, err)
+	_, err = uc.Queue(context.Background(), 1, models.QueueInput{Order: models.QueueOrderShortest, MaxTime: 10, Limit: 500})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	_, err = uc.Queue(context.Background(), 1, models.QueueInput{Order: "random"})
+	assert.Equal(t, bookmark.ErrBadRequest, err)
+}
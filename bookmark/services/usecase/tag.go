@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"gorm.io/gorm"
+)
+
+const maxTagLength = 100
+
+type TagUseCase struct {
+	tagRepo      services.TagRepositorySQL
+	bookmarkRepo services.BookmarkRepositorySQL
+	index        services.SearchIndex
+	log          *slog.Logger
+}
+
+func NewTagUseCase(tagRepo services.TagRepositorySQL, bookmarkRepo services.BookmarkRepositorySQL, index services.SearchIndex, log *slog.Logger) *TagUseCase {
+	return &TagUseCase{
+		tagRepo:      tagRepo,
+		bookmarkRepo: bookmarkRepo,
+		index:        index,
+		log:          log,
+	}
+}
+
+func (t *TagUseCase) AddTags(ctx context.Context, userID, bookmarkID uint, inp models.TagsInput) ([]models.Tag, error) {
+	names, err := normalizeTagNames(inp.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, bookmark.ErrDataTidakLengkap
+	}
+
+	tags, err := t.tagRepo.SQLAddTags(ctx, userID, bookmarkID, names)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	reindexBookmarks(ctx, t.log, t.index, t.bookmarkRepo, userID, []uint{bookmarkID})
+
+	return tags, nil
+}
+
+func (t *TagUseCase) RemoveTag(ctx context.Context, userID, bookmarkID uint, name string) error {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return err
+	}
+
+	if err := t.tagRepo.SQLRemoveTag(ctx, userID, bookmarkID, name); err != nil {
+		return tagNotFound(err)
+	}
+	reindexBookmarks(ctx, t.log, t.index, t.bookmarkRepo, userID, []uint{bookmarkID})
+
+	return nil
+}
+
+func (t *TagUseCase) ListTags(ctx context.Context, userID uint) ([]models.TagCount, error) {
+	return t.tagRepo.SQLListTags(ctx, userID)
+}
+
+func (t *TagUseCase) RenameTag(ctx context.Context, userID, id uint, inp models.RenameTagInput) (*models.Tag, error) {
+	name, err := normalizeTagName(inp.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := t.tagRepo.SQLGetTag(ctx, userID, id)
+	if err != nil {
+		return nil, tagNotFound(err)
+	}
+	if tag.Name == name {
+		return tag, nil
+	}
+
+	// Renaming onto an existing tag is a merge, which the client has to ask
+	// for explicitly.
+	if existing, err := t.tagRepo.SQLGetTagByName(ctx, userID, name); err == nil && existing.ID != id {
+		return nil, bookmark.ErrTagDuplicate
+	}
+
+	if err := t.tagRepo.SQLRenameTag(ctx, userID, id, name); err != nil {
+		if errors.Is(err, dberr.ErrDuplicateKey) {
+			return nil, bookmark.ErrTagDuplicate
+		}
+		return nil, tagNotFound(err)
+	}
+	t.reindexTag(ctx, userID, id)
+
+	tag.Name = name
+	return tag, nil
+}
+
+func (t *TagUseCase) MergeTags(ctx context.Context, userID uint, inp models.MergeTagsInput) (*models.Tag, error) {
+	sources, err := normalizeTagNames(inp.Sources)
+	if err != nil {
+		return nil, err
+	}
+	target, err := normalizeTagName(inp.Target)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, bookmark.ErrDataTidakLengkap
+	}
+
+	tag, err := t.tagRepo.SQLMergeTags(ctx, userID, sources, target)
+	if err != nil {
+		return nil, tagNotFound(err)
+	}
+	t.reindexTag(ctx, userID, tag.ID)
+
+	return tag, nil
+}
+
+func (t *TagUseCase) DeleteTag(ctx context.Context, userID, id uint) error {
+	ids, err := t.tagRepo.SQLListBookmarkIDsByTag(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := t.tagRepo.SQLDeleteTag(ctx, userID, id); err != nil {
+		return tagNotFound(err)
+	}
+	reindexBookmarks(ctx, t.log, t.index, t.bookmarkRepo, userID, ids)
+
+	return nil
+}
+
+func (t *TagUseCase) reindexTag(ctx context.Context, userID, tagID uint) {
+	ids, err := t.tagRepo.SQLListBookmarkIDsByTag(ctx, userID, tagID)
+	if err != nil {
+		t.log.ErrorContext(ctx, "search: failed to list bookmarks of tag", "tag", tagID, "err", err)
+		return
+	}
+	reindexBookmarks(ctx, t.log, t.index, t.bookmarkRepo, userID, ids)
+}
+
+// normalizeTagName trims, lowercases and collapses inner whitespace so that
+// "Go  Lang" and "go lang" end up as the same tag.
+func normalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if name == "" || len(name) > maxTagLength || strings.Contains(name, ",") {
+		return "", bookmark.ErrInvalidTag
+	}
+	return name, nil
+}
+
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	return result, nil
+}
+
+func tagNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bookmark.ErrTagNotFound
+	}
+	return err
+}
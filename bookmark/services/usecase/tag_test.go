@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_AddTags_Success(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	tags := []models.Tag{{ID: 3, Name: "golang"}, {ID: 4, Name: "clean code"}}
+	repo.On("SQLAddTags", uint(1), uint(7), []string{"golang", "clean code"}).Return(tags, nil)
+	bookmarkRepo.On("SQLGetBookmarksByIDs", uint(1), []uint{7}).Return([]models.Bookmark{{ID: 7, UserID: 1, Tags: tags}}, nil)
+	bookmarkRepo.On("SQLGetContentTexts", []uint{7}).Return(map[uint]string{}, nil)
+
+	res, err := uc.AddTags(context.Background(), 1, 7, models.TagsInput{Tags: []string{" GoLang", "clean   Code", "golang"}})
+	assert.NoError(t, err)
+	assert.Equal(t, tags, res)
+}
+
+func Test_AddTags_Failed(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	_, err := uc.AddTags(context.Background(), 1, 7, models.TagsInput{})
+	assert.Equal(t, bookmark.ErrDataTidakLengkap, err)
+
+	_, err = uc.AddTags(context.Background(), 1, 7, models.TagsInput{Tags: []string{"a,b"}})
+	assert.Equal(t, bookmark.ErrInvalidTag, err)
+
+	repo.On("SQLAddTags", uint(2), uint(7), []string{"golang"}).Return([]models.Tag(nil), gorm.ErrRecordNotFound)
+	_, err = uc.AddTags(context.Background(), 2, 7, models.TagsInput{Tags: []string{"golang"}})
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+}
+
+func Test_RemoveTag_NotFound(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLRemoveTag", uint(1), uint(7), "golang").Return(gorm.ErrRecordNotFound)
+	assert.Equal(t, bookmark.ErrTagNotFound, uc.RemoveTag(context.Background(), 1, 7, "Golang"))
+}
+
+func Test_RenameTag_Success(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLGetTag", uint(1), uint(3)).Return(&models.Tag{ID: 3, UserID: 1, Name: "go"}, nil)
+	repo.On("SQLGetTagByName", uint(1), "golang").Return(new(models.Tag), gorm.ErrRecordNotFound)
+	repo.On("SQLRenameTag", uint(1), uint(3), "golang").Return(nil)
+	repo.On("SQLListBookmarkIDsByTag", uint(1), uint(3)).Return([]uint{}, nil)
+
+	tag, err := uc.RenameTag(context.Background(), 1, 3, models.RenameTagInput{Name: "Golang"})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Tag{ID: 3, UserID: 1, Name: "golang"}, tag)
+}
+
+func Test_RenameTag_Failed_Duplicate(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLGetTag", uint(1), uint(3)).Return(&models.Tag{ID: 3, UserID: 1, Name: "go"}, nil)
+	repo.On("SQLGetTagByName", uint(1), "golang").Return(&models.Tag{ID: 4, UserID: 1, Name: "golang"}, nil)
+
+	_, err := uc.RenameTag(context.Background(), 1, 3, models.RenameTagInput{Name: "golang"})
+	assert.Equal(t, bookmark.ErrTagDuplicate, err)
+	repo.AssertNotCalled(t, "SQLRenameTag", uint(1), uint(3), "golang")
+}
+
+func Test_RenameTag_Failed_Duplicate_LostRace(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLGetTag", uint(1), uint(3)).Return(&models.Tag{ID: 3, UserID: 1, Name: "go"}, nil)
+	repo.On("SQLGetTagByName", uint(1), "golang").Return(new(models.Tag), gorm.ErrRecordNotFound)
+	repo.On("SQLRenameTag", uint(1), uint(3), "golang").Return(fmt.Errorf("%w: UNIQUE constraint failed", dberr.ErrDuplicateKey))
+
+	_, err := uc.RenameTag(context.Background(), 1, 3, models.RenameTagInput{Name: "golang"})
+	assert.Equal(t, bookmark.ErrTagDuplicate, err)
+}
+
+func Test_MergeTags_Success(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	repo.On("SQLMergeTags", uint(1), []string{"go", "go-lang"}, "golang").Return(&models.Tag{ID: 3, Name: "golang"}, nil)
+	repo.On("SQLListBookmarkIDsByTag", uint(1), uint(3)).Return([]uint{7}, nil)
+	bookmarkRepo.On("SQLGetBookmarksByIDs", uint(1), []uint{7}).Return([]models.Bookmark{{ID: 7, UserID: 1}}, nil)
+	bookmarkRepo.On("SQLGetContentTexts", []uint{7}).Return(map[uint]string{}, nil)
+
+	tag, err := uc.MergeTags(context.Background(), 1, models.MergeTagsInput{Sources: []string{"Go", "go-lang"}, Target: "golang"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), tag.ID)
+}
+
+func Test_MergeTags_Failed(t *testing.T) {
+	repo := new(mock.TagStorageMock)
+	bookmarkRepo := new(mock.BookmarkStorageMock)
+	uc := NewTagUseCase(repo, bookmarkRepo, search.NewMemoryIndex(), logging.Discard())
+
+	_, err := uc.MergeTags(context.Background(), 1, models.MergeTagsInput{Target: "golang"})
+	assert.Equal(t, bookmark.ErrDataTidakLengkap, err)
+
+	_, err = uc.MergeTags(context.Background(), 1, models.MergeTagsInput{Sources: []string{"go"}})
+	assert.Equal(t, bookmark.ErrInvalidTag, err)
+
+	repo.On("SQLMergeTags", uint(1), []string{"go"}, "golang").Return((*models.Tag)(nil), gorm.ErrRecordNotFound)
+	_, err = uc.MergeTags(context.Background(), 1, models.MergeTagsInput{Sources: []string{"go"}, Target: "golang"})
+	assert.Equal(t, bookmark.ErrTagNotFound, err)
+}
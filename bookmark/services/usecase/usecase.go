@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/urlnorm"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultListLimit   = 20
+	maxListLimit       = 100
+	normalizeBatchSize = 200
+)
+
+type BookmarkUseCase struct {
+	bookmarkRepo services.BookmarkRepositorySQL
+	index        services.SearchIndex
+	metadata     services.MetadataUseCase
+	log          *slog.Logger
+}
+
+func NewBookmarkUseCase(bookmarkRepo services.BookmarkRepositorySQL, index services.SearchIndex, metadata services.MetadataUseCase, log *slog.Logger) *BookmarkUseCase {
+	return &BookmarkUseCase{
+		bookmarkRepo: bookmarkRepo,
+		index:        index,
+		metadata:     metadata,
+		log:          log,
+	}
+}
+
+func (b *BookmarkUseCase) CreateBookmark(ctx context.Context, userID uint, inp models.BookmarkInput) (*models.Bookmark, error) {
+	if err := validateInput(&inp); err != nil {
+		return nil, err
+	}
+
+	bm := &models.Bookmark{
+		UserID: userID,
+		URL:    inp.URL,
+		Title:  inp.Title,
+		Notes:  inp.Notes,
+	}
+	if inp.Public != nil {
+		bm.Public = *inp.Public
+	}
+
+	bm, err := createBookmark(ctx, b.bookmarkRepo, bm)
+	if err != nil {
+		return bm, err
+	}
+	// A new bookmark has no article yet.
+	indexBookmark(ctx, b.log, b.index, bm, "")
+	b.metadata.Enqueue(ctx, userID, bm.ID)
+
+	return bm, nil
+}
+
+func (b *BookmarkUseCase) GetBookmark(ctx context.Context, userID, id uint) (*models.Bookmark, error) {
+	bm, err := b.bookmarkRepo.SQLGetBookmark(ctx, userID, id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return bm, nil
+}
+
+// GetContent returns the reader copy of a bookmark, which is only there once
+// its page has been fetched and an article found on it.
+func (b *BookmarkUseCase) GetContent(ctx context.Context, userID, id uint) (*models.BookmarkContent, error) {
+	if _, err := b.bookmarkRepo.SQLGetBookmark(ctx, userID, id); err != nil {
+		return nil, notFound(err)
+	}
+
+	content, err := b.bookmarkRepo.SQLGetContent(ctx, userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, bookmark.ErrContentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+func (b *BookmarkUseCase) ListBookmarks(ctx context.Context, userID uint, inp models.ListInput) ([]models.Bookmark, error) {
+	inp.Limit, inp.Offset = pageBounds(inp.Limit, inp.Offset)
+
+	inp.TagNames = nil
+	if inp.Tags != "" {
+		names, err := normalizeTagNames(strings.Split(inp.Tags, ","))
+		if err != nil {
+			return nil, err
+		}
+		inp.TagNames = names
+	}
+
+	switch inp.TagMode {
+	case "":
+		inp.TagMode = models.TagModeAny
+	case models.TagModeAny, models.TagModeAll:
+	default:
+		return nil, bookmark.ErrBadRequest
+	}
+
+	if inp.Status != "" && !validStatus(inp.Status) {
+		return nil, bookmark.ErrInvalidState
+	}
+
+	if inp.Link != "" && !validLinkStatus(inp.Link) {
+		return nil, bookmark.ErrInvalidLinkStatus
+	}
+
+	return b.bookmarkRepo.SQLListBookmarks(ctx, userID, inp)
+}
+
+func (b *BookmarkUseCase) UpdateBookmark(ctx context.Context, userID, id uint, inp models.BookmarkInput) (*models.Bookmark, error) {
+	if err := validateInput(&inp); err != nil {
+		return nil, err
+	}
+
+	bm, err := b.bookmarkRepo.SQLGetBookmark(ctx, userID, id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	urlChanged := bm.URL != inp.URL
+	bm.URL = inp.URL
+	bm.Title = inp.Title
+	bm.Notes = inp.Notes
+	if inp.Public != nil {
+		bm.Public = *inp.Public
+	}
+	if urlChanged {
+		if err := setNormalizedURL(bm); err != nil {
+			return nil, bookmark.ErrInvalidURL
+		}
+		existing, err := bookmarkByURLHash(ctx, b.bookmarkRepo, userID, *bm.URLHash)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.ID != bm.ID {
+			return nil, bookmark.ErrBookmarkDuplicate
+		}
+
+		// What the fetcher and the checker found was about the old URL.
+		bm.CanonicalHash = nil
+		bm.LinkStatus = ""
+		bm.LinkCode = 0
+		bm.RedirectURL = ""
+		bm.LinkError = ""
+		bm.LinkCheckedAt = nil
+	}
+
+	if err := b.bookmarkRepo.SQLUpdateBookmark(ctx, bm); err != nil {
+		return nil, notFound(err)
+	}
+	indexBookmarks(ctx, b.log, b.index, b.bookmarkRepo, []models.Bookmark{*bm})
+	if urlChanged {
+		b.metadata.Enqueue(ctx, userID, bm.ID)
+	}
+
+	return bm, nil
+}
+
+func (b *BookmarkUseCase) DeleteBookmark(ctx context.Context, userID, id uint) error {
+	if err := b.bookmarkRepo.SQLDeleteBookmark(ctx, userID, id); err != nil {
+		return notFound(err)
+	}
+
+	if err := b.index.Remove(ctx, userID, id); err != nil {
+		b.log.ErrorContext(ctx, "search: failed to remove bookmark", "bookmark", id, "err", err)
+	}
+	return nil
+}
+
+// NormalizeURLs fills in the normalized URL of the bookmarks saved before
+// there was one. Bookmarks that turn out to be duplicates of each other keep
+// none, since there is no telling which of them to drop. It stops between
+// batches once ctx is done.
+func (b *BookmarkUseCase) NormalizeURLs(ctx context.Context) error {
+	return b.bookmarkRepo.SQLEachUnnormalizedBookmark(ctx, normalizeBatchSize, func(batch []models.Bookmark) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for i := range batch {
+			bm := &batch[i]
+			if err := setNormalizedURL(bm); err != nil {
+				continue
+			}
+			if err := b.bookmarkRepo.SQLSetNormalizedURL(ctx, bm); err != nil {
+				b.log.ErrorContext(ctx, "normalize: failed to save normalized url", "bookmark", bm.ID, "err", err)
+			}
+		}
+		return nil
+	})
+}
+
+// createBookmark saves bm, unless the user already has a bookmark of the
+// same page. That one is returned instead, along with ErrBookmarkDuplicate.
+func createBookmark(ctx context.Context, repo services.BookmarkRepositorySQL, bm *models.Bookmark) (*models.Bookmark, error) {
+	if err := setNormalizedURL(bm); err != nil {
+		return nil, bookmark.ErrInvalidURL
+	}
+
+	existing, err := bookmarkByURLHash(ctx, repo, bm.UserID, *bm.URLHash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, bookmark.ErrBookmarkDuplicate
+	}
+
+	if err := repo.SQLCreateBookmark(ctx, bm); err != nil {
+		// Another save of the same page won the race to the index.
+		if errors.Is(err, dberr.ErrDuplicateKey) {
+			if existing, _ := bookmarkByURLHash(ctx, repo, bm.UserID, *bm.URLHash); existing != nil {
+				return existing, bookmark.ErrBookmarkDuplicate
+			}
+		}
+		return nil, err
+	}
+
+	return bm, nil
+}
+
+func bookmarkByURLHash(ctx context.Context, repo services.BookmarkRepositorySQL, userID uint, hash string) (*models.Bookmark, error) {
+	bm, err := repo.SQLGetBookmarkByURLHash(ctx, userID, hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bm, nil
+}
+
+// setNormalizedURL fills in the normalized URL of bm and the hashes of it
+// and of its canonical link. It leaves them alone and returns the error
+// when bm.URL cannot be normalized.
+func setNormalizedURL(bm *models.Bookmark) error {
+	normalized, err := urlnorm.Normalize(bm.URL)
+	if err != nil {
+		return err
+	}
+
+	hash := urlnorm.Hash(normalized)
+	bm.NormalizedURL = normalized
+	bm.URLHash = &hash
+	bm.CanonicalHash = canonicalHash(normalized, bm.CanonicalURL)
+	return nil
+}
+
+// canonicalHash hashes the canonical link a page declares. Some sites point
+// every page at their front page, so such a link only counts for the front
+// page itself.
+func canonicalHash(normalizedURL, canonical string) *string {
+	normalized, err := urlnorm.Normalize(canonical)
+	if err != nil {
+		return nil
+	}
+	if urlnorm.IsSiteRoot(normalized) && !urlnorm.IsSiteRoot(normalizedURL) {
+		return nil
+	}
+
+	hash := urlnorm.Hash(normalized)
+	return &hash
+}
+
+func validateInput(inp *models.BookmarkInput) error {
+	inp.URL = strings.TrimSpace(inp.URL)
+	inp.Title = strings.TrimSpace(inp.Title)
+
+	if inp.URL == "" {
+		return bookmark.ErrDataTidakLengkap
+	}
+
+	u, err := url.Parse(inp.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return bookmark.ErrInvalidURL
+	}
+
+	return nil
+}
+
+func pageBounds(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bookmark.ErrBookmarkNotFound
+	}
+	return err
+}
@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/models"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/repository/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/search"
+	"github.com/khuchuz/go-clean-architecture-sql/bookmark/services/urlnorm"
+	ucmock "github.com/khuchuz/go-clean-architecture-sql/bookmark/services/usecase/mock"
+	"github.com/khuchuz/go-clean-architecture-sql/dberr"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newMetadataStub() *ucmock.MetadataUseCaseMock {
+	metadata := new(ucmock.MetadataUseCaseMock)
+	metadata.On("Enqueue", testifymock.Anything, testifymock.Anything).Return()
+	return metadata
+}
+
+func Test_CreateBookmark_Success(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	metadata := new(ucmock.MetadataUseCaseMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), metadata, logging.Discard())
+
+	hash := urlnorm.Hash("example.com")
+	bm := &models.Bookmark{UserID: 1, URL: "https://example.com", NormalizedURL: "example.com", URLHash: &hash, Title: "Example"}
+
+	repo.On("SQLGetBookmarkByURLHash", uint(1), hash).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	repo.On("SQLCreateBookmark", bm).Return(nil).Run(func(args testifymock.Arguments) {
+		args.Get(0).(*models.Bookmark).ID = 7
+	})
+	metadata.On("Enqueue", uint(1), uint(7)).Return()
+
+	res, err := uc.CreateBookmark(context.Background(), 1, models.BookmarkInput{URL: " https://example.com ", Title: "Example"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), res.ID)
+	assert.Equal(t, "Example", res.Title)
+	metadata.AssertExpectations(t)
+}
+
+func Test_CreateBookmark_Duplicate(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	metadata := new(ucmock.MetadataUseCaseMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), metadata, logging.Discard())
+
+	existing := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com/a", NormalizedURL: "example.com/a"}
+	repo.On("SQLGetBookmarkByURLHash", uint(1), urlnorm.Hash("example.com/a")).Return(existing, nil)
+
+	res, err := uc.CreateBookmark(context.Background(), 1, models.BookmarkInput{URL: "http://Example.com/a/?utm_source=x"})
+	assert.Equal(t, bookmark.ErrBookmarkDuplicate, err)
+	assert.Equal(t, existing, res)
+	repo.AssertNotCalled(t, "SQLCreateBookmark", testifymock.Anything)
+	metadata.AssertNotCalled(t, "Enqueue", uint(1), uint(7))
+}
+
+func Test_CreateBookmark_Duplicate_LostRace(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	existing := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com/a"}
+	repo.On("SQLGetBookmarkByURLHash", uint(1), urlnorm.Hash("example.com/a")).Return(new(models.Bookmark), gorm.ErrRecordNotFound).Once()
+	repo.On("SQLCreateBookmark", testifymock.Anything).Return(fmt.Errorf("%w: Duplicate entry", dberr.ErrDuplicateKey))
+	repo.On("SQLGetBookmarkByURLHash", uint(1), urlnorm.Hash("example.com/a")).Return(existing, nil).Once()
+
+	res, err := uc.CreateBookmark(context.Background(), 1, models.BookmarkInput{URL: "https://example.com/a"})
+	assert.Equal(t, bookmark.ErrBookmarkDuplicate, err)
+	assert.Equal(t, existing, res)
+}
+
+func Test_CreateBookmark_Failed_EmptyURL(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	_, err := uc.CreateBookmark(context.Background(), 1, models.BookmarkInput{Title: "Example"})
+	assert.Equal(t, bookmark.ErrDataTidakLengkap, err)
+	repo.AssertNotCalled(t, "SQLCreateBookmark")
+}
+
+func Test_CreateBookmark_Failed_InvalidURL(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	for _, u := range []string{"example.com", "ftp://example.com/file", "https://", "javascript:alert(1)", "http://:80/"} {
+		_, err := uc.CreateBookmark(context.Background(), 1, models.BookmarkInput{URL: u})
+		assert.Equal(t, bookmark.ErrInvalidURL, err, u)
+	}
+	repo.AssertNotCalled(t, "SQLGetBookmarkByURLHash")
+
+	// Without validateInput in front, saving still fails rather than panics.
+	_, err := createBookmark(context.Background(), repo, &models.Bookmark{UserID: 1, URL: "http://:80/"})
+	assert.Equal(t, bookmark.ErrInvalidURL, err)
+}
+
+func Test_GetBookmark_NotFound(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmark", uint(2), uint(7)).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	_, err := uc.GetBookmark(context.Background(), 2, 7)
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+}
+
+func Test_GetContent(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7}, nil)
+	repo.On("SQLGetBookmark", uint(1), uint(8)).Return(&models.Bookmark{ID: 8}, nil)
+	repo.On("SQLGetBookmark", uint(2), uint(7)).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	repo.On("SQLGetContent", uint(1), uint(7)).Return(&models.BookmarkContent{BookmarkID: 7, Text: "Hello"}, nil)
+	repo.On("SQLGetContent", uint(1), uint(8)).Return(new(models.BookmarkContent), gorm.ErrRecordNotFound)
+
+	content, err := uc.GetContent(context.Background(), 1, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", content.Text)
+
+	_, err = uc.GetContent(context.Background(), 1, 8)
+	assert.Equal(t, bookmark.ErrContentNotFound, err)
+
+	_, err = uc.GetContent(context.Background(), 2, 7)
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+}
+
+func Test_ListBookmarks_ClampsLimit(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLListBookmarks", uint(1), models.ListInput{Limit: 20, TagMode: models.TagModeAny}).Return([]models.Bookmark{}, nil)
+	repo.On("SQLListBookmarks", uint(1), models.ListInput{Limit: 100, Offset: 0, TagMode: models.TagModeAny}).Return([]models.Bookmark{}, nil)
+
+	_, err := uc.ListBookmarks(context.Background(), 1, models.ListInput{})
+	assert.NoError(t, err)
+	_, err = uc.ListBookmarks(context.Background(), 1, models.ListInput{Limit: 1000, Offset: -5})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func Test_ListBookmarks_TagFilter(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	expected := models.ListInput{Limit: 20, Tags: "Golang, SQL,golang", TagMode: models.TagModeAll, TagNames: []string{"golang", "sql"}}
+	repo.On("SQLListBookmarks", uint(1), expected).Return([]models.Bookmark{}, nil)
+
+	_, err := uc.ListBookmarks(context.Background(), 1, models.ListInput{Tags: "Golang, SQL,golang", TagMode: "all"})
+	assert.NoError(t, err)
+
+	_, err = uc.ListBookmarks(context.Background(), 1, models.ListInput{Tags: "golang", TagMode: "some"})
+	assert.Equal(t, bookmark.ErrBadRequest, err)
+}
+
+func Test_UpdateBookmark_Success(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	metadata := new(ucmock.MetadataUseCaseMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), metadata, logging.Discard())
+
+	checked := time.Now()
+	existing := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com", Title: "Lama", LinkStatus: models.LinkStatusBroken, LinkCode: 404, LinkCheckedAt: &checked}
+	updated := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com/baru", Title: "Baru", Notes: "catatan"}
+
+	hash := urlnorm.Hash("example.com/baru")
+	updated.NormalizedURL = "example.com/baru"
+	updated.URLHash = &hash
+
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(existing, nil)
+	repo.On("SQLGetBookmarkByURLHash", uint(1), hash).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+	repo.On("SQLUpdateBookmark", updated).Return(nil)
+	repo.On("SQLGetContentTexts", []uint{7}).Return(map[uint]string{}, nil)
+	metadata.On("Enqueue", uint(1), uint(7)).Return()
+
+	res, err := uc.UpdateBookmark(context.Background(), 1, 7, models.BookmarkInput{URL: "https://example.com/baru", Title: "Baru", Notes: "catatan"})
+	assert.NoError(t, err)
+	assert.Equal(t, updated, res)
+	metadata.AssertExpectations(t)
+}
+
+func Test_UpdateBookmark_SameURL_NoRefetch(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	metadata := new(ucmock.MetadataUseCaseMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), metadata, logging.Discard())
+
+	existing := &models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com", Title: "Lama"}
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(existing, nil)
+	repo.On("SQLUpdateBookmark", existing).Return(nil)
+	repo.On("SQLGetContentTexts", []uint{7}).Return(map[uint]string{}, nil)
+
+	_, err := uc.UpdateBookmark(context.Background(), 1, 7, models.BookmarkInput{URL: "https://example.com", Title: "Baru"})
+	assert.NoError(t, err)
+	metadata.AssertNotCalled(t, "Enqueue", uint(1), uint(7))
+}
+
+func Test_UpdateBookmark_Duplicate(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmark", uint(1), uint(7)).Return(&models.Bookmark{ID: 7, UserID: 1, URL: "https://example.com/a"}, nil)
+	repo.On("SQLGetBookmarkByURLHash", uint(1), urlnorm.Hash("example.com/b")).Return(&models.Bookmark{ID: 8}, nil)
+
+	_, err := uc.UpdateBookmark(context.Background(), 1, 7, models.BookmarkInput{URL: "https://example.com/b"})
+	assert.Equal(t, bookmark.ErrBookmarkDuplicate, err)
+	repo.AssertNotCalled(t, "SQLUpdateBookmark", testifymock.Anything)
+}
+
+func Test_NormalizeURLs(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLEachUnnormalizedBookmark", normalizeBatchSize).Return([]models.Bookmark{
+		{ID: 7, UserID: 1, URL: "https://example.com/a?fbclid=1", CanonicalURL: "https://example.com/"},
+		{ID: 8, UserID: 1, URL: "https://blog.example.com/post/", CanonicalURL: "https://example.com/post"},
+		{ID: 9, UserID: 1, URL: "https://example.com/a"},
+	}, nil)
+	repo.On("SQLSetNormalizedURL", testifymock.MatchedBy(func(bm *models.Bookmark) bool {
+		return bm.ID == 7 && bm.NormalizedURL == "example.com/a" && *bm.URLHash == urlnorm.Hash("example.com/a") && bm.CanonicalHash == nil
+	})).Return(nil)
+	repo.On("SQLSetNormalizedURL", testifymock.MatchedBy(func(bm *models.Bookmark) bool {
+		return bm.ID == 8 && bm.NormalizedURL == "blog.example.com/post" && *bm.CanonicalHash == urlnorm.Hash("example.com/post")
+	})).Return(nil)
+	repo.On("SQLSetNormalizedURL", testifymock.MatchedBy(func(bm *models.Bookmark) bool {
+		return bm.ID == 9
+	})).Return(errors.New("Duplicate entry"))
+
+	assert.NoError(t, uc.NormalizeURLs(context.Background()))
+	repo.AssertNumberOfCalls(t, "SQLSetNormalizedURL", 3)
+}
+
+func Test_NormalizeURLs_Stopped(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLEachUnnormalizedBookmark", normalizeBatchSize).Return([]models.Bookmark{{ID: 7, UserID: 1, URL: "https://example.com/a"}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, uc.NormalizeURLs(ctx), context.Canceled)
+	repo.AssertNotCalled(t, "SQLSetNormalizedURL", testifymock.Anything)
+}
+
+func Test_UpdateBookmark_NotFound(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLGetBookmark", uint(2), uint(7)).Return(new(models.Bookmark), gorm.ErrRecordNotFound)
+
+	_, err := uc.UpdateBookmark(context.Background(), 2, 7, models.BookmarkInput{URL: "https://example.com"})
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, err)
+}
+
+func Test_UpdateBookmark_InvalidURL(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	_, err := uc.UpdateBookmark(context.Background(), 2, 7, models.BookmarkInput{URL: "http://:80/"})
+	assert.Equal(t, bookmark.ErrInvalidURL, err)
+	repo.AssertNotCalled(t, "SQLUpdateBookmark")
+}
+
+func Test_DeleteBookmark(t *testing.T) {
+	repo := new(mock.BookmarkStorageMock)
+	uc := NewBookmarkUseCase(repo, search.NewMemoryIndex(), newMetadataStub(), logging.Discard())
+
+	repo.On("SQLDeleteBookmark", uint(1), uint(7)).Return(nil)
+	repo.On("SQLDeleteBookmark", uint(2), uint(7)).Return(gorm.ErrRecordNotFound)
+
+	assert.NoError(t, uc.DeleteBookmark(context.Background(), 1, 7))
+	assert.Equal(t, bookmark.ErrBookmarkNotFound, uc.DeleteBookmark(context.Background(), 2, 7))
+}
@@ -0,0 +1,92 @@
+// Package dberr turns the errors of the MySQL, PostgreSQL and SQLite drivers
+// into errors the repositories and usecases can check without knowing which
+// database they run on.
+package dberr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sqlite "github.com/glebarez/go-sqlite"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is gorm's own, which the repositories already return for
+	// a missing row.
+	ErrNotFound = gorm.ErrRecordNotFound
+
+	// ErrDuplicateKey is a write that broke a unique index.
+	ErrDuplicateKey = errors.New("duplicate key")
+)
+
+const (
+	mysqlDuplicateEntry = 1062
+	pgUniqueViolation   = "23505"
+
+	// Extended result codes of SQLite.
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// Translate returns err as ErrNotFound or ErrDuplicateKey when it is one of
+// those, and as it is otherwise. A duplicate key keeps the message of the
+// driver, which names the index.
+func Translate(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrNotFound
+	case errors.Is(err, ErrDuplicateKey), !isDuplicateKey(err):
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrDuplicateKey, err)
+}
+
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqliteConstraintUnique || code == sqliteConstraintPrimaryKey
+	}
+
+	return false
+}
+
+// Register makes every query run through db translate its error.
+func Register(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, register := range []func(name string, fn func(*gorm.DB)) error{
+		callbacks.Create().Register,
+		callbacks.Query().Register,
+		callbacks.Update().Register,
+		callbacks.Delete().Register,
+		callbacks.Row().Register,
+		callbacks.Raw().Register,
+	} {
+		if err := register("dberr:translate", translate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func translate(db *gorm.DB) {
+	if db.Error != nil {
+		db.Error = Translate(db.Error)
+	}
+}
@@ -0,0 +1,29 @@
+package dberr
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslate(t *testing.T) {
+	assert.NoError(t, Translate(nil))
+	assert.Equal(t, ErrNotFound, Translate(sql.ErrNoRows))
+
+	for _, err := range []error{
+		&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'khuchuz' for key 'idx_users_username'"},
+		&pgconn.PgError{Code: "23505", Message: `duplicate key value violates unique constraint "idx_users_username"`},
+	} {
+		translated := Translate(err)
+		assert.True(t, errors.Is(translated, ErrDuplicateKey), err)
+		assert.Contains(t, translated.Error(), "idx_users_username")
+		assert.Equal(t, translated, Translate(translated))
+	}
+
+	other := &mysql.MySQLError{Number: 1146, Message: "Table 'users' doesn't exist"}
+	assert.Equal(t, error(other), Translate(other))
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database/migrate"
+	"gorm.io/gorm"
+)
+
+// Database pings the database of db.
+func Database(db *gorm.DB) Check {
+	return Check{
+		Name: "database",
+		Run: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+	}
+}
+
+// Migrations fails while the schema of db is behind the migrations built
+// into the server.
+func Migrations(db *gorm.DB) Check {
+	return Check{
+		Name: "migrations",
+		Run: func(ctx context.Context) error {
+			migrator, err := migrate.New(db.WithContext(ctx))
+			if err != nil {
+				return err
+			}
+			pending, err := migrator.Pending()
+			if err != nil {
+				return err
+			}
+			if len(pending) > 0 {
+				return fmt.Errorf("migration %04d_%s is pending, %d in all", pending[0].Version, pending[0].Name, len(pending))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterHTTPEndpoints serves /healthz, which answers 200 as long as the
+// server runs, and /readyz, which answers 200 when every check of checker
+// passes and 503 otherwise, with the result of each.
+func RegisterHTTPEndpoints(router *gin.Engine, checker *Checker) {
+	router.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, Report{Status: StatusOK})
+	})
+	router.GET("/readyz", func(c *gin.Context) {
+		report := checker.Ready(c.Request.Context())
+		status := http.StatusOK
+		if report.Status != StatusReady {
+			status = http.StatusServiceUnavailable
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, report)
+	})
+}
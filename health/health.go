@@ -0,0 +1,103 @@
+// Package health answers the probes of an orchestrator: /healthz says the
+// process is up, /readyz whether it can serve, by checking what it depends
+// on. Readiness is withdrawn for good once shutdown starts, so that traffic
+// is drained before the server stops listening.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Timeout bounds each check, so that a hung dependency fails its check
+// rather than the probe.
+const Timeout = 2 * time.Second
+
+const (
+	StatusOK           = "ok"
+	StatusFailed       = "failed"
+	StatusReady        = "ready"
+	StatusNotReady     = "not_ready"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check is one dependency the server needs to serve.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is how a check went.
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the answer to /readyz.
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks,omitempty"`
+}
+
+// Checker runs the checks of readiness.
+type Checker struct {
+	checks       []Check
+	shuttingDown atomic.Bool
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// ShuttingDown makes the server not ready from now on.
+func (c *Checker) ShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs every check at once and reports the server ready when all of
+// them pass. Once shutdown has started, nothing is checked.
+func (c *Checker) Ready(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	report := Report{Status: StatusReady, Checks: make([]Result, len(c.checks))}
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			report.Checks[i] = run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusNotReady
+		}
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := Result{
+		Name:      check.Name,
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
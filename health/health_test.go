@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func get(t *testing.T, r *gin.Engine, path string) (int, Report) {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func TestEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var cacheErr error
+	checker := NewChecker(
+		Check{Name: "cache", Run: func(ctx context.Context) error { return cacheErr }},
+		Check{Name: "mailer", Run: func(ctx context.Context) error { return nil }},
+	)
+	r := gin.New()
+	RegisterHTTPEndpoints(r, checker)
+
+	code, report := get(t, r, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, report.Status)
+
+	code, report = get(t, r, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusReady, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "cache", report.Checks[0].Name)
+	assert.Equal(t, StatusOK, report.Checks[0].Status)
+
+	cacheErr = errors.New("connection refused")
+	code, report = get(t, r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusNotReady, report.Status)
+	assert.Equal(t, Result{Name: "cache", Status: StatusFailed, LatencyMS: report.Checks[0].LatencyMS, Error: "connection refused"}, report.Checks[0])
+	assert.Equal(t, StatusOK, report.Checks[1].Status)
+
+	cacheErr = nil
+	checker.ShuttingDown()
+	code, report = get(t, r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, Report{Status: StatusShuttingDown}, report)
+
+	code, _ = get(t, r, "/healthz")
+	assert.Equal(t, http.StatusOK, code, "the process is still alive")
+}
+
+func TestReady_Timeout(t *testing.T) {
+	checker := NewChecker(Check{Name: "hung", Run: func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Minute):
+			return nil
+		}
+	}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	report := checker.Ready(ctx)
+	assert.Equal(t, StatusNotReady, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestDatabaseChecks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	assert.NoError(t, Database(db).Run(ctx))
+
+	migrator, err := migrate.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(1)
+	require.NoError(t, err)
+	err = Migrations(db).Run(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "migration 0002_")
+
+	_, err = migrator.Up(0)
+	require.NoError(t, err)
+	assert.NoError(t, Migrations(db).Run(ctx))
+
+	require.NoError(t, sqlDB.Close())
+	assert.Error(t, Database(db).Run(ctx))
+}
@@ -0,0 +1,92 @@
+// Package lifecycle stops what the server started, in the reverse order it
+// was started in: the HTTP server before the workers that serve it, and the
+// workers before the database they use.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Manager keeps the stop hooks of the server.
+type Manager struct {
+	log *slog.Logger
+
+	mu    sync.Mutex
+	hooks []hook
+}
+
+func New(log *slog.Logger) *Manager {
+	return &Manager{log: log}
+}
+
+// OnStop registers stop to be called by Stop, before everything registered
+// earlier.
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, stop: stop})
+}
+
+// Go runs fn in the background. Stop cancels the ctx given to fn and waits
+// for it to return, in the order it was registered, so that it finishes
+// before what it uses is stopped.
+func (m *Manager) Go(name string, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			m.log.Error(name+": failed", "err", err)
+		}
+	}()
+	m.OnStop(name, func(context.Context) error {
+		cancel()
+		<-done
+		return nil
+	})
+}
+
+// Stop calls every stop hook, newest first, and returns their errors. A hook
+// still running when ctx is done is given up on. The next ones are called
+// anyway, so that the database is closed even when a worker hangs, but not
+// waited for. Hooks are called once; Stop may be called again and does
+// nothing.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := m.hooks
+	m.hooks = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := m.stop(ctx, hooks[i]); err != nil {
+			m.log.Error("failed to stop "+hooks[i].name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+		} else {
+			m.log.Debug("stopped " + hooks[i].name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) stop(ctx context.Context, h hook) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- h.stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,86 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStop_Order(t *testing.T) {
+	m := New(logging.Discard())
+	var stopped []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			stopped = append(stopped, name)
+			return err
+		}
+	}
+	m.OnStop("database", record("database", nil))
+	m.OnStop("workers", record("workers", errors.New("stuck job")))
+	m.OnStop("http server", record("http server", nil))
+
+	err := m.Stop(context.Background())
+	assert.Equal(t, []string{"http server", "workers", "database"}, stopped)
+	assert.EqualError(t, err, "workers: stuck job")
+
+	assert.NoError(t, m.Stop(context.Background()))
+	assert.Len(t, stopped, 3, "hooks are called once")
+}
+
+func TestStop_Timeout(t *testing.T) {
+	m := New(logging.Discard())
+	closed := make(chan struct{})
+	m.OnStop("database", func(context.Context) error {
+		close(closed)
+		return nil
+	})
+	m.OnStop("workers", func(context.Context) error {
+		time.Sleep(time.Minute)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := m.Stop(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "workers: ")
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Error("what comes after a hung hook is stopped anyway")
+	}
+}
+
+func TestGo(t *testing.T) {
+	m := New(logging.Discard())
+	var closedAt, finishedAt time.Time
+	m.OnStop("database", func(context.Context) error {
+		closedAt = time.Now()
+		return nil
+	})
+	m.Go("rebuild", func(context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		finishedAt = time.Now()
+		return errors.New("logged, not returned")
+	})
+
+	assert.NoError(t, m.Stop(context.Background()))
+	assert.False(t, finishedAt.IsZero())
+	assert.True(t, closedAt.After(finishedAt), "the database outlives its users")
+}
+
+func TestGo_Stopped(t *testing.T) {
+	m := New(logging.Discard())
+	m.Go("rebuild", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, m.Stop(ctx), "Stop cancels what runs in the background")
+}
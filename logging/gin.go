@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID both ways.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID keeps what a client sends as its request ID short and
+// printable; anything else is replaced.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:+/=-]{1,128}$`)
+
+// RequestIDMiddleware gives every request an ID: the X-Request-ID it came
+// with, or a new one. The ID is sent back in the same header and carried by
+// the context of the request, so whatever logs with it is tagged.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// AccessLogMiddleware logs every request once it is answered: server errors
+// as errors, client errors as warnings. The query string is left out, and
+// so is the path segment after each of secretPaths, like "/s/" for share
+// tokens.
+func AccessLogMiddleware(log *slog.Logger, secretPaths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		log.Log(c.Request.Context(), level, "request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", redactPath(c.Request.URL.Path, secretPaths)),
+			slog.Int("status", status),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+func redactPath(path string, secretPaths []string) string {
+	for _, prefix := range secretPaths {
+		if !strings.HasPrefix(path, prefix) || len(path) == len(prefix) {
+			continue
+		}
+		rest := path[len(prefix):]
+		if i := strings.Index(rest, "/"); i >= 0 {
+			return prefix + redacted + rest[i:]
+		}
+		return prefix + redacted
+	}
+	return path
+}
+
+// RecoveryMiddleware answers 500 to a handler that panics and logs the
+// panic with its stack. It goes after AccessLogMiddleware, which then logs
+// the 500.
+func RecoveryMiddleware(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.ErrorContext(c.Request.Context(), "panic",
+					slog.String("panic", fmt.Sprint(recovered)),
+					slog.String("stack", string(debug.Stack())),
+				)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+	}
+}
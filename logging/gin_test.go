@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	out := new(bytes.Buffer)
+	log := New(config.LogConfig{Level: "info", Format: config.LogFormatLogfmt}, out)
+
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), AccessLogMiddleware(log, "/s/"), RecoveryMiddleware(log))
+	var seen string
+	r.GET("/s/:token", func(c *gin.Context) {
+		seen = RequestID(c.Request.Context())
+		c.String(http.StatusOK, "shared")
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/s/abc123?password=x", nil)
+	req.Header.Set(RequestIDHeader, "from-proxy")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "from-proxy", w.Header().Get(RequestIDHeader))
+	assert.Equal(t, "from-proxy", seen)
+	assert.Contains(t, out.String(), "level=INFO msg=request method=GET path=/s/[REDACTED] status=200 bytes=6")
+	assert.Contains(t, out.String(), "request_id=from-proxy")
+	assert.NotContains(t, out.String(), "abc123")
+	assert.NotContains(t, out.String(), "password=x")
+	out.Reset()
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/panic", nil)
+	req.Header.Set(RequestIDHeader, strings.Repeat("x", 200))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 500, w.Code)
+	assert.Len(t, w.Header().Get(RequestIDHeader), 32, "an overlong ID is replaced")
+	assert.Contains(t, out.String(), "level=ERROR msg=panic panic=boom")
+	assert.Contains(t, out.String(), "level=ERROR msg=request method=GET path=/panic status=500")
+}
+
+func TestRedactPath(t *testing.T) {
+	secret := []string{"/s/", "/feeds/"}
+	assert.Equal(t, "/feeds/[REDACTED]/atom", redactPath("/feeds/tok/atom", secret))
+	assert.Equal(t, "/feeds/", redactPath("/feeds/", secret))
+	assert.Equal(t, "/api/feeds/1", redactPath("/api/feeds/1", secret))
+}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var queryLevels = map[string]gormlogger.LogLevel{
+	"silent": gormlogger.Silent,
+	"error":  gormlogger.Error,
+	"warn":   gormlogger.Warn,
+	"info":   gormlogger.Info,
+}
+
+// GormLogger logs the queries of GORM to a slog logger: failed ones as
+// errors, slow ones as warnings and, at the info level, all of them. The
+// statements are redacted with RedactSQL.
+type GormLogger struct {
+	log   *slog.Logger
+	level gormlogger.LogLevel
+	slow  time.Duration
+}
+
+// NewGormLogger logs queries up to level, one of silent, error, warn and
+// info, and counts those slower than slow as slow unless slow is 0.
+func NewGormLogger(log *slog.Logger, level string, slow time.Duration) *GormLogger {
+	l, ok := queryLevels[level]
+	if !ok {
+		l = gormlogger.Warn
+	}
+	return &GormLogger{log: log, level: l, slow: slow}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.log.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.log.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.log.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{slog.String("sql", RedactSQL(sql)), slog.Int64("rows", rows), slog.Duration("duration", elapsed)}
+	}
+
+	switch {
+	// A missing row is an answer, not a failure.
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		l.log.ErrorContext(ctx, "query failed", append(attrs(), slog.String("err", err.Error()))...)
+	case l.slow > 0 && elapsed > l.slow && l.level >= gormlogger.Warn:
+		l.log.WarnContext(ctx, "slow query", append(attrs(), slog.Duration("threshold", l.slow))...)
+	case l.level >= gormlogger.Info:
+		l.log.InfoContext(ctx, "query", attrs()...)
+	}
+}
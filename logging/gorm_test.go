@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGormLogger_Trace(t *testing.T) {
+	out := new(bytes.Buffer)
+	log := New(config.LogConfig{Level: "info", Format: config.LogFormatLogfmt}, out)
+	ctx := WithRequestID(context.Background(), "req-1")
+	query := func() (string, int64) {
+		return "SELECT * FROM `users` WHERE password = 'hash'", 1
+	}
+
+	warn := NewGormLogger(log, "warn", 100*time.Millisecond)
+
+	warn.Trace(ctx, time.Now(), query, nil)
+	assert.Empty(t, out.String(), "fast queries are not logged at warn")
+
+	warn.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	assert.Empty(t, out.String(), "a missing row is no failure")
+
+	warn.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	assert.Contains(t, out.String(), `level=WARN msg="slow query"`)
+	assert.Contains(t, out.String(), "password = '[REDACTED]'")
+	assert.Contains(t, out.String(), "threshold=100ms")
+	assert.Contains(t, out.String(), "request_id=req-1")
+	out.Reset()
+
+	warn.Trace(ctx, time.Now(), query, errors.New("connection refused"))
+	assert.Contains(t, out.String(), `level=ERROR msg="query failed"`)
+	assert.Contains(t, out.String(), `err="connection refused"`)
+	out.Reset()
+
+	NewGormLogger(log, "info", 0).Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	assert.Contains(t, out.String(), "level=INFO msg=query")
+	out.Reset()
+
+	NewGormLogger(log, "silent", 0).Trace(ctx, time.Now(), query, errors.New("connection refused"))
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,81 @@
+// Package logging builds the structured logger of the server and the pieces
+// that feed it: request IDs carried in contexts, the GORM query logger and
+// the gin middleware. Secrets are redacted on the way out.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New builds the logger cfg describes, writing to w. Every record logged
+// with a context carries the request and trace IDs of the context, and
+// every attribute goes through Redact.
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       level(cfg.Level),
+		ReplaceAttr: Redact,
+	}
+
+	var handler slog.Handler
+	if cfg.Format == config.LogFormatLogfmt {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(&contextHandler{handler})
+}
+
+// Discard is a logger that writes nothing, for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func level(name string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx carrying the ID of the request it serves.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID is the request ID ctx carries, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID of the context to each record, and
+// the ID of its trace when it is traced.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
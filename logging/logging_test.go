@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew_JSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	log := New(config.LogConfig{Level: "info", Format: config.LogFormatJSON}, out)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	ctx = WithRequestID(ctx, "req-1")
+	log.InfoContext(ctx, "signed in", "username", "khuchuz", "password", "hunter2", "header", "Bearer eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJ4In0.c2ln")
+	log.Debug("not logged")
+	log.Error("failed", "err", errors.New("parse eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJ4In0.c2ln: expired"), "url", &url.URL{Scheme: "https", Host: "example.com", RawQuery: "t=eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJ4In0.c2ln"})
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &record))
+	assert.Equal(t, "signed in", record["msg"])
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	assert.Equal(t, "khuchuz", record["username"])
+	assert.Equal(t, "[REDACTED]", record["password"])
+	assert.Equal(t, "Bearer [REDACTED]", record["header"])
+
+	// Tokens inside errors and Stringers are hidden too.
+	require.NoError(t, json.Unmarshal(lines[1], &record))
+	assert.Equal(t, "parse [REDACTED]: expired", record["err"])
+	assert.Equal(t, "https://example.com?t=[REDACTED]", record["url"])
+}
+
+func TestNew_Logfmt(t *testing.T) {
+	out := new(bytes.Buffer)
+	log := New(config.LogConfig{Level: "debug", Format: config.LogFormatLogfmt}, out)
+
+	log.With("signing_secret", config.Secret("key")).Debug("started", "port", 8000)
+
+	line := out.String()
+	assert.True(t, strings.HasPrefix(line, "time="), line)
+	assert.Contains(t, line, "level=DEBUG msg=started")
+	assert.Contains(t, line, "signing_secret=[REDACTED]")
+	assert.Contains(t, line, "port=8000")
+	assert.NotContains(t, line, "request_id")
+}
+
+func TestSensitive(t *testing.T) {
+	for _, key := range []string{"password", "OldPassword", "password_hash", "token", "client_secret", "hash_salt", "Authorization"} {
+		assert.True(t, Sensitive(key), key)
+	}
+	for _, key := range []string{"username", "email", "sql", "path"} {
+		assert.False(t, Sensitive(key), key)
+	}
+}
+
+func TestRedactSQL(t *testing.T) {
+	for sql, want := range map[string]string{
+		"SELECT * FROM `users` WHERE username = 'khuchuz' AND password = 'a1b2' ORDER BY `users`.`id` LIMIT 1":                                                  "SELECT * FROM `users` WHERE username = 'khuchuz' AND password = '[REDACTED]' ORDER BY `users`.`id` LIMIT 1",
+		"UPDATE `users` SET `password`='new' WHERE username = 'khuchuz' AND password = 'old'":                                                                   "UPDATE `users` SET `password`='[REDACTED]' WHERE username = 'khuchuz' AND password = '[REDACTED]'",
+		`SELECT * FROM "shares" WHERE "shares"."token" = 'it''s\'secret' LIMIT 1`:                                                                               `SELECT * FROM "shares" WHERE "shares"."token" = '[REDACTED]' LIMIT 1`,
+		"INSERT INTO `users` (`username`,`email`,`password`,`role`,`disabled`) VALUES ('a','a@x.com','h,(1)','user',false),('b','b@x.com','h''2','user',false)": "INSERT INTO `users` (`username`,`email`,`password`,`role`,`disabled`) VALUES ('a','a@x.com','[REDACTED]','user',false),('b','b@x.com','[REDACTED]','user',false)",
+		`INSERT INTO "tags" ("user_id","name") VALUES (1,'go') RETURNING "id"`:                                                                                  `INSERT INTO "tags" ("user_id","name") VALUES (1,'go') RETURNING "id"`,
+		"SELECT * FROM `users` WHERE username = \"u\" AND password = \"a\\\"b\" LIMIT 1":                                                                        "SELECT * FROM `users` WHERE username = \"u\" AND password = \"[REDACTED]\" LIMIT 1",
+		"INSERT INTO `shares` (`token`,`user_id`) VALUES (\"t\",1) RETURNING `id`":                                                                              "INSERT INTO `shares` (`token`,`user_id`) VALUES (\"[REDACTED]\",1) RETURNING `id`",
+		"SELECT count(*) FROM `bookmarks` WHERE user_id = 1":                                                                                                    "SELECT count(*) FROM `bookmarks` WHERE user_id = 1",
+	} {
+		assert.Equal(t, want, RedactSQL(sql))
+	}
+}
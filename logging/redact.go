@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveWords mark the keys and columns whose values are never logged.
+var sensitiveWords = []string{"password", "passwd", "secret", "token", "salt", "authorization", "cookie"}
+
+// Sensitive reports whether values under key are secrets, as with
+// "password", "oldpassword", "signing_key_secret" or "share_token".
+func Sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range sensitiveWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// jwt matches a JSON Web Token wherever it turns up.
+var jwt = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+// Redact is a slog ReplaceAttr: the values of sensitive keys become
+// [REDACTED], and so do tokens inside other strings. An error or a
+// fmt.Stringer holding a token is logged as its text, with the token hidden.
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	if Sensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, jwt.ReplaceAllString(a.Value.String(), redacted))
+	case slog.KindAny:
+		var text string
+		switch v := a.Value.Any().(type) {
+		case error:
+			text = v.Error()
+		case fmt.Stringer:
+			text = v.String()
+		default:
+			return a
+		}
+		if jwt.MatchString(text) {
+			return slog.String(a.Key, jwt.ReplaceAllString(text, redacted))
+		}
+	}
+	return a
+}
+
+var (
+	// sqlComparison matches a sensitive column compared to or set to a
+	// string, as in password = 'x' or `token`="x". SQLite quotes strings
+	// with double quotes.
+	sqlComparison = regexp.MustCompile(`(?i)([\w.` + "`" + `"]*(?:` + strings.Join(sensitiveWords, "|") + `)[\w` + "`" + `"]*\s*(?:=|<>|!=)\s*)('(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*")`)
+
+	sqlInsert = regexp.MustCompile(`(?is)^(\s*INSERT\s+INTO\s+\S+\s*\()([^)]*)(\)\s*VALUES\s*)(.*)$`)
+)
+
+// RedactSQL hides the values of sensitive columns in a statement GORM has
+// filled in with its arguments: in comparisons, assignments and the values
+// of inserts. Tokens anywhere are hidden as well.
+func RedactSQL(sql string) string {
+	sql = sqlComparison.ReplaceAllStringFunc(sql, func(match string) string {
+		parts := sqlComparison.FindStringSubmatch(match)
+		quote := parts[2][:1]
+		return parts[1] + quote + redacted + quote
+	})
+	if match := sqlInsert.FindStringSubmatch(sql); match != nil {
+		sql = match[1] + match[2] + match[3] + redactValues(match[4], sensitiveColumns(match[2]))
+	}
+	return jwt.ReplaceAllString(sql, redacted)
+}
+
+func sensitiveColumns(columns string) map[int]bool {
+	sensitive := make(map[int]bool)
+	for i, column := range strings.Split(columns, ",") {
+		if Sensitive(strings.Trim(strings.TrimSpace(column), "`\"")) {
+			sensitive[i] = true
+		}
+	}
+	return sensitive
+}
+
+// redactValues hides the string literals in the sensitive columns of the
+// tuples of an insert, leaving whatever follows them alone. Identifiers are
+// never quoted there, so double quotes start strings, as SQLite has them.
+func redactValues(values string, sensitive map[int]bool) string {
+	if len(sensitive) == 0 {
+		return values
+	}
+
+	var (
+		out    strings.Builder
+		depth  int
+		column int
+	)
+	for i := 0; i < len(values); i++ {
+		c := values[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := closingQuote(values, i)
+			if depth == 1 && sensitive[column] {
+				out.WriteString(string(c) + redacted + string(c))
+			} else {
+				out.WriteString(values[i : end+1])
+			}
+			i = end
+			continue
+		case c == '(':
+			depth++
+			if depth == 1 {
+				column = 0
+			}
+		case c == ')':
+			depth--
+		case c == ',' && depth == 1:
+			column++
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
+
+// closingQuote finds the quote ending the literal opened at start, past
+// backslash escapes and doubled quotes, or the end of s.
+func closingQuote(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(s) - 1
+}
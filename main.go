@@ -1,16 +1,120 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/khuchuz/go-clean-architecture-sql/auth/app"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
 )
 
-func main() {
+const usage = `usage: go-clean-architecture-sql COMMAND [config flags] [ARGS]
+
+commands:
+  serve                  run the HTTP server, the default without a command
+  migrate                apply, take back, list or create schema migrations
+  user                   create, list, disable, enable and change accounts
+  token                  issue tokens and inspect them
+  config print           show the configuration, with secrets redacted
 
-	serv := app.NewApp()
+Config flags come right after the command; see "serve -h" for all of them.`
+
+// cli runs a command with its input and output, so tests can run it too.
+// Logs go to errOut.
+type cli struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+}
 
-	if err := serv.Run("8000"); err != nil {
+func main() {
+
+	c := &cli{in: os.Stdin, out: os.Stdout, errOut: os.Stderr}
+	if err := c.run(context.Background(), os.Args[1:]); err != nil && err != flag.ErrHelp {
 		log.Fatalf("%s", err.Error())
 	}
 }
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	// Flags alone start the server, as they did before there were commands.
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return c.serve(ctx, args)
+	}
+
+	switch args[0] {
+	case "serve":
+		return c.serve(ctx, args[1:])
+	case "migrate":
+		return c.migrate(args[1:])
+	case "user":
+		return c.user(ctx, args[1:])
+	case "token":
+		return c.token(ctx, args[1:])
+	case "config":
+		return c.config(args[1:])
+	case "help":
+		fmt.Fprintln(c.out, usage)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q\n\n%s", args[0], usage)
+}
+
+func (c *cli) serve(ctx context.Context, args []string) error {
+	cfg, args, err := config.Load(args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("serve takes no arguments, got %q", args)
+	}
+
+	logger := c.logger(cfg)
+	// What still logs through the log package comes out structured too.
+	slog.SetDefault(logger)
+
+	// The first SIGINT or SIGTERM shuts the server down; a second one ends
+	// it at once.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	serv, err := app.NewApp(ctx, cfg, logger)
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		// Stopped while waiting for the database.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return serv.Run(ctx)
+}
+
+func (c *cli) logger(cfg *config.Config) *slog.Logger {
+	return logging.New(cfg.Log, c.errOut)
+}
+
+func (c *cli) config(args []string) error {
+	cfg, args, err := config.Load(args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 || args[0] != "print" {
+		return fmt.Errorf("usage: config [config flags] print")
+	}
+
+	fmt.Fprint(c.out, cfg.String())
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLI_Users(t *testing.T) {
+	db := []string{"-db.driver", "sqlite", "-db.name", filepath.Join(t.TempDir(), "cli.db")}
+	run := func(stdin string, args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		c := &cli{in: strings.NewReader(stdin), out: out, errOut: io.Discard}
+		err := c.run(context.Background(), append(append(args[:1:1], db...), args[1:]...))
+		return out.String(), err
+	}
+
+	_, err := run("", "user", "list")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run migrate up first")
+
+	_, err = run("", "migrate", "up")
+	require.NoError(t, err)
+
+	out, err := run("", "user", "create", "-role", "admin", "root", "root@example.com")
+	require.NoError(t, err)
+	assert.Contains(t, out, "created root, admin\npassword: ")
+
+	out, err = run("secret\n", "user", "create", "-password-stdin", "khuchuz", "khuchuz@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "created khuchuz, user\n", out)
+
+	_, err = run("", "user", "create", "-role", "root", "other", "other@example.com")
+	assert.Equal(t, auth.ErrInvalidRole, err)
+
+	require.NoError(t, runErr(run("", "user", "set-role", "khuchuz", "admin")))
+	require.NoError(t, runErr(run("", "user", "disable", "khuchuz")))
+
+	out, err = run("", "user", "list")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"ID", "USERNAME", "EMAIL", "ROLE", "DISABLED"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"2", "khuchuz", "khuchuz@example.com", "admin", "true"}, strings.Fields(lines[2]))
+
+	_, err = run("", "token", "issue", "khuchuz")
+	assert.Equal(t, auth.ErrUserDisabled, err)
+
+	require.NoError(t, runErr(run("", "user", "enable", "khuchuz")))
+	require.NoError(t, runErr(run("new\n", "user", "reset-password", "-password-stdin", "khuchuz")))
+
+	token, err := run("", "token", "issue", "-ttl", "1h", "khuchuz")
+	require.NoError(t, err)
+
+	out, err = run("", "token", "inspect", strings.TrimSpace(token))
+	require.NoError(t, err)
+	assert.Contains(t, out, `"active": true`)
+	assert.Contains(t, out, `"role": "admin"`)
+
+	_, err = run("", "user", "list", "-role", "admin")
+	assert.Error(t, err)
+	_, err = run("", "user", "disable", "nobody")
+	assert.Equal(t, auth.ErrUserNotFound, err)
+}
+
+func TestCLI_ConfigPrint(t *testing.T) {
+	out := new(bytes.Buffer)
+	c := &cli{in: strings.NewReader(""), out: out, errOut: io.Discard}
+
+	require.NoError(t, c.run(context.Background(), []string{"config", "-db.password", "hunter2", "print"}))
+	assert.Contains(t, out.String(), "[REDACTED]")
+	assert.NotContains(t, out.String(), "hunter2")
+
+	assert.Error(t, c.run(context.Background(), []string{"frobnicate"}))
+}
+
+func runErr(_ string, err error) error {
+	return err
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/services"
+)
+
+// authUseCase counts what happens to the sign-ups, sign-ins and tokens that
+// go through the usecase it wraps.
+type authUseCase struct {
+	services.UseCase
+	m *Metrics
+}
+
+// InstrumentAuth wraps uc so that m counts its sign-ups, sign-ins, lockouts
+// and token validations.
+func InstrumentAuth(uc services.UseCase, m *Metrics) services.UseCase {
+	return &authUseCase{UseCase: uc, m: m}
+}
+
+func (a *authUseCase) SignUp(ctx context.Context, inp models.SignUpInput) error {
+	err := a.UseCase.SignUp(ctx, inp)
+
+	outcome := OutcomeError
+	switch {
+	case err == nil:
+		outcome = OutcomeSuccess
+	case errors.Is(err, auth.ErrUserDuplicate), errors.Is(err, auth.ErrEmailDuplicate):
+		outcome = OutcomeDuplicate
+	case errors.Is(err, auth.ErrDataTidakLengkap), errors.Is(err, auth.ErrInvalidRole):
+		outcome = OutcomeInvalid
+	}
+	a.m.signUps.WithLabelValues(outcome).Inc()
+	return err
+}
+
+func (a *authUseCase) SignIn(ctx context.Context, inp models.SignInput) (string, error) {
+	token, err := a.UseCase.SignIn(ctx, inp)
+
+	outcome := OutcomeError
+	switch {
+	case err == nil:
+		outcome = OutcomeSuccess
+	case errors.Is(err, auth.ErrUserNotFound):
+		outcome = OutcomeInvalidCredentials
+	case errors.Is(err, auth.ErrUserDisabled):
+		outcome = OutcomeLockedOut
+		a.m.lockouts.Inc()
+	}
+	a.m.signIns.WithLabelValues(outcome).Inc()
+	return token, err
+}
+
+func (a *authUseCase) ParseToken(ctx context.Context, accessToken string) (*models.User, error) {
+	user, err := a.UseCase.ParseToken(ctx, accessToken)
+
+	outcome := OutcomeValid
+	if err != nil {
+		outcome = OutcomeInvalid
+	}
+	a.m.tokenValidations.WithLabelValues(SourceMiddleware, outcome).Inc()
+	return user, err
+}
+
+func (a *authUseCase) IntrospectToken(ctx context.Context, token string) (*models.IntrospectResponse, error) {
+	resp, err := a.UseCase.IntrospectToken(ctx, token)
+
+	outcome := OutcomeInvalid
+	switch {
+	case err != nil:
+		outcome = OutcomeError
+	case resp.Active:
+		outcome = OutcomeValid
+	case resp.Revoked:
+		outcome = OutcomeRevoked
+	}
+	a.m.tokenValidations.WithLabelValues(SourceIntrospection, outcome).Inc()
+	return resp, err
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute is the route of the requests no route answers.
+const unmatchedRoute = "unmatched"
+
+// Middleware counts and times every request, labelled with the route it
+// matched, like "/api/bookmarks/:id", rather than its path. Requests no
+// route answers are all "unmatched", so that scanners probing random paths
+// do not grow the number of series. It goes before the recovery
+// middleware, so that panics count as 500s.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		method := c.Request.Method
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		m.httpRequests.WithLabelValues(method, route, status).Inc()
+		m.httpDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,122 @@
+// Package metrics exposes the server to Prometheus: its HTTP traffic, what
+// happens to sign-ups, sign-ins and tokens, the connection pool of the
+// database and the Go runtime.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+// Outcomes of the auth counters.
+const (
+	OutcomeSuccess            = "success"
+	OutcomeInvalid            = "invalid"
+	OutcomeDuplicate          = "duplicate"
+	OutcomeInvalidCredentials = "invalid_credentials"
+	OutcomeLockedOut          = "locked_out"
+	OutcomeValid              = "valid"
+	OutcomeRevoked            = "revoked"
+	OutcomeError              = "error"
+)
+
+// Sources of token validations.
+const (
+	SourceMiddleware    = "middleware"
+	SourceIntrospection = "introspection"
+)
+
+// Metrics holds the collectors of the server in a registry of its own, so
+// that tests can build as many as they like.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	signUps          *prometheus.CounterVec
+	signIns          *prometheus.CounterVec
+	lockouts         prometheus.Counter
+	tokenValidations *prometheus.CounterVec
+}
+
+// New registers the collectors of the server, the pool statistics of db and
+// the Go runtime and process metrics.
+func New(db *gorm.DB) (*Metrics, error) {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests answered, by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to answer HTTP requests, by method, route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		signUps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_sign_ups_total",
+			Help: "Sign-ups, by outcome.",
+		}, []string{"outcome"}),
+		signIns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_sign_ins_total",
+			Help: "Sign-ins, by outcome.",
+		}, []string{"outcome"}),
+		lockouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "auth_lockouts_total",
+			Help: "Sign-ins with the right password refused because the account is disabled.",
+		}),
+		tokenValidations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_token_validations_total",
+			Help: "Access tokens checked, by where they were checked and outcome.",
+		}, []string{"source", "outcome"}),
+	}
+
+	// Every outcome is reported from the start, so that rates work before
+	// the first of each.
+	for _, outcome := range []string{OutcomeSuccess, OutcomeInvalid, OutcomeDuplicate, OutcomeError} {
+		m.signUps.WithLabelValues(outcome)
+	}
+	for _, outcome := range []string{OutcomeSuccess, OutcomeInvalidCredentials, OutcomeLockedOut, OutcomeError} {
+		m.signIns.WithLabelValues(outcome)
+	}
+	for _, source := range []string{SourceMiddleware, SourceIntrospection} {
+		for _, outcome := range []string{OutcomeValid, OutcomeInvalid, OutcomeRevoked, OutcomeError} {
+			if source == SourceMiddleware && (outcome == OutcomeRevoked || outcome == OutcomeError) {
+				continue
+			}
+			m.tokenValidations.WithLabelValues(source, outcome)
+		}
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, collector := range []prometheus.Collector{
+		m.httpRequests,
+		m.httpDuration,
+		m.signUps,
+		m.signIns,
+		m.lockouts,
+		m.tokenValidations,
+		collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name()),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	} {
+		if err := m.registry.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/khuchuz/go-clean-architecture-sql/auth"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/services/usecase/mock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newMetrics(t *testing.T) *Metrics {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	m, err := New(db)
+	require.NoError(t, err)
+	return m
+}
+
+func TestHandler(t *testing.T) {
+	m := newMetrics(t)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	for _, line := range []string{
+		`auth_sign_ins_total{outcome="locked_out"} 0`,
+		`auth_sign_ups_total{outcome="duplicate"} 0`,
+		`auth_token_validations_total{outcome="revoked",source="introspection"} 0`,
+		`auth_token_validations_total{outcome="error",source="introspection"} 0`,
+		`auth_lockouts_total 0`,
+		`go_sql_max_open_connections{db_name="sqlite"}`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, w.Body.String(), line)
+	}
+}
+
+func TestInstrumentAuth(t *testing.T) {
+	m := newMetrics(t)
+	uc := new(mock.AuthUseCaseMock)
+	instrumented := InstrumentAuth(uc, m)
+	ctx := context.Background()
+
+	uc.On("SignUp", "new", "new@example.com", "pw").Return(nil)
+	uc.On("SignUp", "taken", "taken@example.com", "pw").Return(auth.ErrUserDuplicate)
+	uc.On("SignUp", "", "", "").Return(auth.ErrDataTidakLengkap)
+	assert.NoError(t, instrumented.SignUp(ctx, models.SignUpInput{Username: "new", Email: "new@example.com", Password: "pw"}))
+	assert.Equal(t, auth.ErrUserDuplicate, instrumented.SignUp(ctx, models.SignUpInput{Username: "taken", Email: "taken@example.com", Password: "pw"}))
+	assert.Equal(t, auth.ErrDataTidakLengkap, instrumented.SignUp(ctx, models.SignUpInput{}))
+
+	uc.On("SignIn", "user", "right").Return("token", nil)
+	uc.On("SignIn", "user", "wrong").Return("", auth.ErrUserNotFound)
+	uc.On("SignIn", "disabled", "right").Return("", auth.ErrUserDisabled)
+	uc.On("SignIn", "broken", "right").Return("", errors.New("sign failed"))
+	for _, inp := range []models.SignInput{
+		{Username: "user", Password: "right"},
+		{Username: "user", Password: "wrong"},
+		{Username: "user", Password: "wrong"},
+		{Username: "disabled", Password: "right"},
+		{Username: "broken", Password: "right"},
+	} {
+		instrumented.SignIn(ctx, inp)
+	}
+
+	uc.On("ParseToken", "good").Return(&models.User{Username: "user"}, nil)
+	uc.On("ParseToken", "bad").Return((*models.User)(nil), auth.ErrInvalidAccessToken)
+	instrumented.ParseToken(ctx, "good")
+	instrumented.ParseToken(ctx, "bad")
+
+	uc.On("IntrospectToken", "good").Return(&models.IntrospectResponse{Active: true}, nil)
+	uc.On("IntrospectToken", "revoked").Return(&models.IntrospectResponse{Revoked: true}, nil)
+	uc.On("IntrospectToken", "bad").Return(&models.IntrospectResponse{}, nil)
+	uc.On("IntrospectToken", "unchecked").Return((*models.IntrospectResponse)(nil), errors.New("connection refused"))
+	instrumented.IntrospectToken(ctx, "good")
+	instrumented.IntrospectToken(ctx, "revoked")
+	instrumented.IntrospectToken(ctx, "revoked")
+	instrumented.IntrospectToken(ctx, "bad")
+	instrumented.IntrospectToken(ctx, "unchecked")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.signUps.WithLabelValues(OutcomeSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.signUps.WithLabelValues(OutcomeDuplicate)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.signUps.WithLabelValues(OutcomeInvalid)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.signIns.WithLabelValues(OutcomeSuccess)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.signIns.WithLabelValues(OutcomeInvalidCredentials)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.signIns.WithLabelValues(OutcomeLockedOut)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.signIns.WithLabelValues(OutcomeError)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.lockouts))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenValidations.WithLabelValues(SourceMiddleware, OutcomeValid)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenValidations.WithLabelValues(SourceMiddleware, OutcomeInvalid)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenValidations.WithLabelValues(SourceIntrospection, OutcomeValid)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.tokenValidations.WithLabelValues(SourceIntrospection, OutcomeRevoked)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenValidations.WithLabelValues(SourceIntrospection, OutcomeInvalid)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenValidations.WithLabelValues(SourceIntrospection, OutcomeError)))
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := newMetrics(t)
+
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/api/bookmarks/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/bookmarks/export", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/bookmarks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/bookmarks/:id", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/api/bookmarks/1"},
+		{"GET", "/api/bookmarks/2"},
+		{"GET", "/api/bookmarks"},
+		{"GET", "/api/bookmarks/export"},
+		{"POST", "/api/bookmarks/3"},
+		{"GET", "/wp-admin/install.php"},
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/bookmarks/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/bookmarks", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/bookmarks/export", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("POST", "/api/bookmarks/:id", "201")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", unmatchedRoute, "404")))
+	assert.Equal(t, 5, testutil.CollectAndCount(m.httpDuration))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/database/migrate"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const migrateUsage = `usage:
+  migrate [config flags] up [N]     apply N pending migrations, all by default
+  migrate [config flags] down [N]   take back the last N migrations, 1 by default
+  migrate [config flags] status     list the migrations and when they were applied
+  migrate create [-dir DIR] NAME    add empty scripts for a new migration`
+
+// migrate runs the migrate command with the arguments after "migrate".
+func (c *cli) migrate(args []string) error {
+	if len(args) > 0 && args[0] == "create" {
+		return c.migrateCreate(args[1:])
+	}
+
+	cfg, args, err := config.Load(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(migrateUsage)
+	}
+
+	n := 0
+	if len(args) == 2 {
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+			return fmt.Errorf("migrate %s: %q is not a number of migrations", args[0], args[1])
+		}
+	}
+
+	db, err := database.Open(cfg.Database, app.QueryLogger(cfg.Log, c.logger(cfg)))
+	if err != nil {
+		return err
+	}
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(n)
+		for _, migration := range done {
+			fmt.Fprintf(c.out, "applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Fprintln(c.out, "nothing to apply")
+		}
+		return err
+	case "down":
+		if n == 0 {
+			n = 1
+		}
+		done, err := migrator.Down(n)
+		for _, migration := range done {
+			fmt.Fprintf(c.out, "took back %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Fprintln(c.out, "nothing to take back")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(c.out, "%04d_%-40s %s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	}
+	return errors.New(migrateUsage)
+}
+
+func (c *cli) migrateCreate(args []string) error {
+	fs := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+	dir := fs.String("dir", migrate.Dir, "directory holding the migrations of each driver")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	paths, err := migrate.Create(*dir, fs.Arg(0))
+	for _, path := range paths {
+		fmt.Fprintln(c.out, "created", path)
+	}
+	return err
+}
+
+// openMigrated opens the database for the commands that work on its data,
+// which, unlike the server, leave migrating to the migrate command.
+func openMigrated(cfg config.DatabaseConfig, queryLog logger.Interface) (*gorm.DB, error) {
+	db, err := database.Open(cfg, queryLog)
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		return nil, fmt.Errorf("migration %04d_%s is pending; run migrate up first", pending[0].Version, pending[0].Name)
+	}
+	return db, nil
+}
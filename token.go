@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+)
+
+const tokenUsage = `usage:
+  token [config flags] issue [-ttl DURATION] NAME   sign a token for NAME, lasting auth.token_ttl by default
+  token [config flags] inspect TOKEN                 show what introspection says of TOKEN`
+
+// token runs the token command with the arguments after "token".
+func (c *cli) token(ctx context.Context, args []string) error {
+	cfg, args, err := config.Load(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(tokenUsage)
+	}
+
+	command, args := args[0], args[1:]
+	fs := flag.NewFlagSet("token "+command, flag.ContinueOnError)
+	var ttl time.Duration
+	if command == "issue" {
+		fs.DurationVar(&ttl, "ttl", 0, "lifetime of the token, auth.token_ttl when 0")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	args = fs.Args()
+	if len(args) != 1 || (command != "issue" && command != "inspect") {
+		return errors.New(tokenUsage)
+	}
+	if ttl < 0 {
+		return fmt.Errorf("token issue: -ttl %s is negative", ttl)
+	}
+
+	logger := c.logger(cfg)
+	db, err := openMigrated(cfg.Database, app.QueryLogger(cfg.Log, logger))
+	if err != nil {
+		return err
+	}
+	uc := app.NewAuthUseCase(db, cfg.Auth, logger)
+
+	if command == "issue" {
+		token, err := uc.IssueToken(ctx, args[0], ttl)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, token)
+		return nil
+	}
+
+	resp, err := uc.IntrospectToken(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, string(out))
+	if resp.Exp != 0 {
+		fmt.Fprintln(c.out, "expires", time.Unix(resp.Exp, 0).Format(time.RFC3339))
+	}
+	return nil
+}
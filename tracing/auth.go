@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/services"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// authUseCase puts every call to the usecase it wraps in a span of its own,
+// so that the time spent hashing and signing shows apart from the queries
+// beneath it.
+type authUseCase struct {
+	next   services.UseCase
+	tracer trace.Tracer
+}
+
+// InstrumentAuth wraps uc so that each of its methods is traced.
+func InstrumentAuth(uc services.UseCase, tp trace.TracerProvider) services.UseCase {
+	return &authUseCase{next: uc, tracer: tp.Tracer(instrumentationName)}
+}
+
+func (a *authUseCase) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return a.tracer.Start(ctx, "AuthUseCase."+method)
+}
+
+// end records err, if any, on span and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (a *authUseCase) SignUp(ctx context.Context, inp models.SignUpInput) error {
+	ctx, span := a.start(ctx, "SignUp")
+	err := a.next.SignUp(ctx, inp)
+	end(span, err)
+	return err
+}
+
+func (a *authUseCase) SignIn(ctx context.Context, inp models.SignInput) (string, error) {
+	ctx, span := a.start(ctx, "SignIn")
+	token, err := a.next.SignIn(ctx, inp)
+	end(span, err)
+	return token, err
+}
+
+func (a *authUseCase) ChangePassword(ctx context.Context, inp models.ChangePasswordInput) error {
+	ctx, span := a.start(ctx, "ChangePassword")
+	err := a.next.ChangePassword(ctx, inp)
+	end(span, err)
+	return err
+}
+
+func (a *authUseCase) ParseToken(ctx context.Context, accessToken string) (*models.User, error) {
+	ctx, span := a.start(ctx, "ParseToken")
+	user, err := a.next.ParseToken(ctx, accessToken)
+	end(span, err)
+	return user, err
+}
+
+func (a *authUseCase) DeleteAccount(ctx context.Context, inp models.DeleteInput) error {
+	ctx, span := a.start(ctx, "DeleteAccount")
+	err := a.next.DeleteAccount(ctx, inp)
+	end(span, err)
+	return err
+}
+
+func (a *authUseCase) IntrospectToken(ctx context.Context, token string) (*models.IntrospectResponse, error) {
+	ctx, span := a.start(ctx, "IntrospectToken")
+	resp, err := a.next.IntrospectToken(ctx, token)
+	end(span, err)
+	return resp, err
+}
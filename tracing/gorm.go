@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"errors"
+
+	"github.com/khuchuz/go-clean-architecture-sql/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// RegisterGorm makes every query run through db with the context of a
+// traced request a span beneath it. Queries outside of a trace, like those
+// of background jobs, are not traced.
+func RegisterGorm(db *gorm.DB, tp trace.TracerProvider) error {
+	tracer := tp.Tracer(instrumentationName)
+	system := db.Dialector.Name()
+	if system == "postgres" {
+		system = "postgresql"
+	}
+
+	callbacks := db.Callback()
+	for _, hook := range []struct {
+		operation     string
+		before, after func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("*").Register, callbacks.Create().After("*").Register},
+		{"query", callbacks.Query().Before("*").Register, callbacks.Query().After("*").Register},
+		{"update", callbacks.Update().Before("*").Register, callbacks.Update().After("*").Register},
+		{"delete", callbacks.Delete().Before("*").Register, callbacks.Delete().After("*").Register},
+		{"row", callbacks.Row().Before("*").Register, callbacks.Row().After("*").Register},
+		{"raw", callbacks.Raw().Before("*").Register, callbacks.Raw().After("*").Register},
+	} {
+		if err := hook.before("tracing:before_"+hook.operation, startQuery(tracer, system, hook.operation)); err != nil {
+			return err
+		}
+		if err := hook.after("tracing:after_"+hook.operation, endQuery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuery(tracer trace.Tracer, system, operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			return
+		}
+		// The span is kept with the statement rather than in its context,
+		// which a chain of queries shares.
+		_, span := tracer.Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(system),
+				semconv.DBOperationKey.String(operation),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endQuery(db *gorm.DB) {
+	value, _ := db.InstanceGet(spanKey)
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	// A later query of the same statement outside of a trace must not end
+	// this span again.
+	db.InstanceSet(spanKey, nil)
+
+	span.SetAttributes(
+		semconv.DBStatement(logging.RedactSQL(db.Statement.SQL.String())),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBSQLTable(db.Statement.Table))
+	}
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+	span.End()
+}
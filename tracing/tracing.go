@@ -0,0 +1,60 @@
+// Package tracing follows requests through the server with OpenTelemetry:
+// a span for each request, each call to the auth usecase and each query
+// made on behalf of a request. Trace context comes in and goes out as W3C
+// traceparent and tracestate headers.
+package tracing
+
+import (
+	"context"
+	"io"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "github.com/khuchuz/go-clean-architecture-sql/tracing"
+
+// Propagator reads and writes the W3C trace context and baggage headers.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Setup builds the tracer provider cfg describes, writing the spans of the
+// stdout exporter to w. Shutdown sends the spans not yet sent; with the
+// exporter "none" nothing is recorded and it does nothing.
+func Setup(ctx context.Context, cfg config.TracingConfig, w io.Writer) (provider trace.TracerProvider, shutdown func(context.Context) error, err error) {
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case config.TracingOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case config.TracingStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	default:
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tp := NewProvider(cfg, sdktrace.NewBatchSpanProcessor(exporter))
+	return tp, tp.Shutdown, nil
+}
+
+// NewProvider builds a tracer provider that hands its spans to processor.
+// Tests pass a simple processor over an in-memory exporter.
+func NewProvider(cfg config.TracingConfig, processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+}
@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/khuchuz/go-clean-architecture-sql/auth"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/services/usecase/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+func newProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := config.Default().Tracing
+	return NewProvider(cfg, sdktrace.NewSimpleSpanProcessor(exporter)), exporter
+}
+
+func attr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSetup(t *testing.T) {
+	tp, shutdown, err := Setup(context.Background(), config.TracingConfig{Exporter: config.TracingNone}, nil)
+	require.NoError(t, err)
+	_, span := tp.Tracer("test").Start(context.Background(), "nothing")
+	assert.False(t, span.SpanContext().IsValid())
+	assert.NoError(t, shutdown(context.Background()))
+
+	out := new(bytes.Buffer)
+	cfg := config.Default().Tracing
+	cfg.Exporter = config.TracingStdout
+	tp, shutdown, err = Setup(context.Background(), cfg, out)
+	require.NoError(t, err)
+	_, span = tp.Tracer("test").Start(context.Background(), "something")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, out.String(), `"Name":"something"`)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tp, exporter := newProvider()
+	uc := new(mock.AuthUseCaseMock)
+	instrumented := InstrumentAuth(uc, tp)
+
+	r := gin.New()
+	r.Use(Middleware(tp))
+	r.POST("/auth/sign-in", func(c *gin.Context) {
+		if _, err := instrumented.SignIn(c.Request.Context(), models.SignInput{Username: "user", Password: "wrong"}); err != nil {
+			c.Status(http.StatusUnauthorized)
+		}
+	})
+	r.GET("/s/:token", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	uc.On("SignIn", "user", "wrong").Return("", auth.ErrUserNotFound)
+
+	req := httptest.NewRequest("POST", "/auth/sign-in", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/s/secret-token", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	usecase, signIn, share := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "POST /auth/sign-in", signIn.Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", signIn.SpanContext.TraceID().String(), "the trace of the caller goes on")
+	assert.Equal(t, "00f067aa0ba902b7", signIn.Parent.SpanID().String())
+	assert.Equal(t, "/auth/sign-in", attr(signIn, "http.route").AsString())
+	assert.Equal(t, int64(401), attr(signIn, "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Unset, signIn.Status.Code, "client errors are not errors of the server")
+
+	assert.Equal(t, "AuthUseCase.SignIn", usecase.Name)
+	assert.Equal(t, signIn.SpanContext.SpanID(), usecase.Parent.SpanID())
+	assert.Equal(t, codes.Error, usecase.Status.Code)
+	assert.Equal(t, auth.ErrUserNotFound.Error(), usecase.Status.Description)
+
+	assert.Equal(t, "GET /s/:token", share.Name)
+	assert.False(t, share.Parent.IsValid(), "a request without traceparent starts a trace")
+	assert.Equal(t, codes.Error, share.Status.Code)
+	for _, kv := range share.Attributes {
+		assert.NotContains(t, kv.Value.Emit(), "secret-token")
+	}
+}
+
+func TestRegisterGorm(t *testing.T) {
+	tp, exporter := newProvider()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterGorm(db, tp))
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	assert.Empty(t, exporter.GetSpans(), "queries outside of a trace are not traced")
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	tx := db.WithContext(ctx)
+	require.NoError(t, tx.Create(&models.User{Username: "user", Email: "user@example.com", Password: "hash"}).Error)
+	var user models.User
+	assert.ErrorIs(t, tx.Where("username = ?", "nobody").First(&user).Error, gorm.ErrRecordNotFound)
+	assert.Error(t, tx.Exec("SELECT * FROM missing").Error)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	create, query, raw := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "gorm.create", create.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), create.Parent.SpanID())
+	assert.Equal(t, "sqlite", attr(create, "db.system").AsString())
+	assert.Equal(t, "users", attr(create, "db.sql.table").AsString())
+	assert.Equal(t, int64(1), attr(create, "db.rows_affected").AsInt64())
+	assert.NotContains(t, attr(create, "db.statement").AsString(), "hash")
+
+	assert.Equal(t, "gorm.query", query.Name)
+	assert.Contains(t, attr(query, "db.statement").AsString(), "username = ?")
+	assert.Equal(t, codes.Unset, query.Status.Code, "a missing row is not an error")
+
+	assert.Equal(t, "gorm.raw", raw.Name)
+	assert.Equal(t, codes.Error, raw.Status.Code)
+}
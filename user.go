@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/app/config"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/models"
+	"github.com/khuchuz/go-clean-architecture-sql/auth/services"
+)
+
+const userUsage = `usage:
+  user [config flags] create [-role ROLE] [-password-stdin] NAME EMAIL
+  user [config flags] list
+  user [config flags] disable NAME
+  user [config flags] enable NAME
+  user [config flags] reset-password [-password-stdin] NAME
+  user [config flags] set-role NAME ROLE
+
+Without -password-stdin a random password is made up and printed. Roles are
+user and admin.`
+
+// user runs the user command with the arguments after "user".
+func (c *cli) user(ctx context.Context, args []string) error {
+	cfg, args, err := config.Load(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(userUsage)
+	}
+
+	command, args := args[0], args[1:]
+	fs := flag.NewFlagSet("user "+command, flag.ContinueOnError)
+	role := models.RoleUser
+	passwordStdin := false
+	if command == "create" {
+		fs.StringVar(&role, "role", role, "role of the new user: user or admin")
+	}
+	if command == "create" || command == "reset-password" {
+		fs.BoolVar(&passwordStdin, "password-stdin", false, "read the password from the first line of stdin")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	args = fs.Args()
+
+	uc, err := c.adminUseCase(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case command == "create" && len(args) == 2:
+		password, generated, err := c.password(passwordStdin)
+		if err != nil {
+			return err
+		}
+		user, err := uc.CreateUser(ctx, models.CreateUserInput{Username: args[0], Email: args[1], Password: password, Role: role})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "created %s, %s\n", user.Username, user.Role)
+		if generated {
+			fmt.Fprintf(c.out, "password: %s\n", password)
+		}
+		return nil
+
+	case command == "list" && len(args) == 0:
+		users, err := uc.ListUsers(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tROLE\tDISABLED")
+		for _, user := range users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\n", user.ID, user.Username, user.Email, user.Role, user.Disabled)
+		}
+		return w.Flush()
+
+	case (command == "disable" || command == "enable") && len(args) == 1:
+		if err := uc.SetDisabled(ctx, args[0], command == "disable"); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "%sd %s\n", command, args[0])
+		return nil
+
+	case command == "reset-password" && len(args) == 1:
+		password, generated, err := c.password(passwordStdin)
+		if err != nil {
+			return err
+		}
+		if err := uc.ResetPassword(ctx, args[0], password); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "reset the password of %s\n", args[0])
+		if generated {
+			fmt.Fprintf(c.out, "password: %s\n", password)
+		}
+		return nil
+
+	case command == "set-role" && len(args) == 2:
+		if err := uc.SetRole(ctx, args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "%s is now %s\n", args[0], args[1])
+		return nil
+	}
+	return errors.New(userUsage)
+}
+
+// password reads a password from the first line of stdin, or makes up one
+// when fromStdin is false, in which case generated is true.
+func (c *cli) password(fromStdin bool) (password string, generated bool, err error) {
+	if !fromStdin {
+		b := make([]byte, 12)
+		if _, err := rand.Read(b); err != nil {
+			return "", false, err
+		}
+		return base64.RawURLEncoding.EncodeToString(b), true, nil
+	}
+
+	scanner := bufio.NewScanner(c.in)
+	scanner.Scan()
+	if err := scanner.Err(); err != nil {
+		return "", false, err
+	}
+	password = strings.TrimRight(scanner.Text(), "\r")
+	if password == "" {
+		return "", false, errors.New("no password on stdin")
+	}
+	return password, false, nil
+}
+
+func (c *cli) adminUseCase(cfg *config.Config) (services.AdminUseCase, error) {
+	logger := c.logger(cfg)
+	db, err := openMigrated(cfg.Database, app.QueryLogger(cfg.Log, logger))
+	if err != nil {
+		return nil, err
+	}
+	return app.NewAuthUseCase(db, cfg.Auth, logger), nil
+}